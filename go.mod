@@ -15,6 +15,7 @@ require (
 	go.mongodb.org/mongo-driver v1.17.3
 	golang.org/x/crypto v0.37.0
 	google.golang.org/api v0.231.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -119,5 +120,4 @@ require (
 	google.golang.org/grpc v1.72.0 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -3,12 +3,11 @@ package ground
 import (
 	"fmt"
 	"os"
-	"reflect"
 	"time"
 
 	"github.com/LydiaTrack/ground/pkg/log"
 
-	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/manifests"
 	"github.com/LydiaTrack/ground/internal/provider"
 	"github.com/LydiaTrack/ground/pkg/auth"
 	"github.com/LydiaTrack/ground/pkg/domain/role"
@@ -71,11 +70,18 @@ func Initialize(r *gin.Engine) {
 		log.LogFatal("Error initializing default role")
 	}
 
-	// Create default roles
-	createDefaultRoles()
+	// Reconcile the roles declared in the role manifests
+	roleManifest, err := loadRoleManifest()
+	if err != nil {
+		log.LogFatal("Error loading role manifest: " + err.Error())
+	}
+	reconcileRoles(roleManifest)
+
+	// Register the roles marked as default in the manifest
+	registry.RegisterRoleProvider(provider.ManifestRoleProvider{Manifest: roleManifest})
 
-	// Register the self role provider
-	registry.RegisterRoleProvider(provider.SelfRoleProvider{})
+	// Register the permissions checked by Ground
+	registry.RegisterPermissionProvider(provider.GroundPermissionProvider{})
 
 	// Initialize default user
 	err = initializers.InitializeDefaultUser()
@@ -84,6 +90,59 @@ func Initialize(r *gin.Engine) {
 		panic(err)
 	}
 
+	// Sweep expired role assignments
+	startRoleAssignmentSweeper(service_initializer.GetServices())
+	startDeletedUserPurger(service_initializer.GetServices())
+	startDataExportSweeper(service_initializer.GetServices())
+	startAvatarMigration(service_initializer.GetServices())
+}
+
+// startRoleAssignmentSweeper periodically removes expired role assignments from users
+func startRoleAssignmentSweeper(services service_initializer.Services) {
+	go func() {
+		for {
+			time.Sleep(1 * time.Minute)
+			if err := services.UserService.RemoveExpiredRoleAssignments(); err != nil {
+				log.LogError("Error removing expired role assignments: %v", err)
+			}
+		}
+	}()
+}
+
+// startDeletedUserPurger periodically purges the users whose deletion grace or cooling-off period has passed
+func startDeletedUserPurger(services service_initializer.Services) {
+	go func() {
+		for {
+			time.Sleep(1 * time.Hour)
+			if err := services.UserService.PurgeDeletedUsers(); err != nil {
+				log.LogError("Error purging deleted users: %v", err)
+			}
+			if err := services.UserService.PurgeScheduledDeletions(); err != nil {
+				log.LogError("Error purging users scheduled for deletion: %v", err)
+			}
+		}
+	}()
+}
+
+// startDataExportSweeper periodically deletes the data exports whose download link expired
+func startDataExportSweeper(services service_initializer.Services) {
+	go func() {
+		for {
+			time.Sleep(1 * time.Hour)
+			if err := services.DataExportService.DeleteExpiredExports(); err != nil {
+				log.LogError("Error deleting expired data exports: %v", err)
+			}
+		}
+	}()
+}
+
+// startAvatarMigration moves the base64 avatars stored in the user documents to the blob store in the background
+func startAvatarMigration(services service_initializer.Services) {
+	go func() {
+		if err := services.AvatarService.MigrateInlineAvatars(); err != nil {
+			log.LogError("Error migrating inline avatars: %v", err)
+		}
+	}()
 }
 
 // initializeRoutes initializes routes for each API
@@ -96,12 +155,23 @@ func initializeRoutes(r *gin.Engine, services service_initializer.Services) {
 	// Add request logging middleware
 	r.Use(middlewares.RequestLoggingMiddleware(*services.AuthService, services.UserService))
 
+	// Set the services used by the permission middleware to build the auth context
+	middlewares.InitPermissionMiddleware(*services.AuthService, services.UserService)
+
 	api.InitAuth(r, services)
 	api.InitUser(r, services)
 	api.InitUserStats(r)
 	api.InitRole(r, services)
+	api.InitPermission(r, services)
+	api.InitOrganization(r, services)
+	api.InitGroup(r, services)
+	api.InitInvitation(r, services)
+	api.InitDataExport(r, services)
+	api.InitAvatar(r, services)
+	api.InitChangelog(r, services)
 	api.InitResetPassword(r, services)
 	api.InitFeedback(r, services)
+	api.InitAudit(r, services)
 	api.InitSwagger(r)
 	api.InitHealth(r)
 
@@ -113,58 +183,51 @@ func initializeRoutes(r *gin.Engine, services service_initializer.Services) {
 	}()
 }
 
-func createDefaultRoles() {
-	authContext := auth.PermissionContext{
-		Permissions: []auth.Permission{auth.AdminPermission},
-		UserID:      nil,
+// loadRoleManifest loads the roles managed by Ground and merges the manifest of the host application given by
+// ROLE_MANIFEST_PATH into them. The admin role and the default role are never pruned.
+func loadRoleManifest() (role.Manifest, error) {
+	content, err := manifests.FS.ReadFile("roles.yaml")
+	if err != nil {
+		return role.Manifest{}, err
 	}
-	selfServiceRoleCmd := role.CreateRoleCommand{
-		Name: "Ground Self Service Role",
-		Tags: []string{"self-service"},
-		Info: "This role is for the users who can manage their profiles",
-		Permissions: []auth.Permission{
-			permissions.UserSelfUpdatePermission,
-			permissions.UserSelfGetPermission,
-		},
+	roleManifest, err := role.ParseManifest("roles.yaml", content)
+	if err != nil {
+		return role.Manifest{}, err
 	}
 
-	isExists := service_initializer.GetServices().RoleService.ExistsByName(selfServiceRoleCmd.Name, authContext)
-	if isExists {
-		// If exists, check if the permissions are the same
-		roleModel, err := service_initializer.GetServices().RoleService.GetByName(selfServiceRoleCmd.Name, authContext)
+	manifestPath := os.Getenv("ROLE_MANIFEST_PATH")
+	if manifestPath != "" {
+		// The path is only set on purpose, so a missing file fails the startup instead of being ignored
+		content, err = os.ReadFile(manifestPath)
 		if err != nil {
-			log.LogFatal("Error creating default roles: " + err.Error())
-			return
-		}
-		currentRolePermissions := roleModel.Permissions
-		// Compare the permissions
-		isSamePermissions := reflect.DeepEqual(currentRolePermissions, selfServiceRoleCmd.Permissions)
-		if !isSamePermissions {
-			log.Log("Permissions are different for the role: " + selfServiceRoleCmd.Name)
-			// Update the role
-			cmd := role.UpdateRoleCommand{
-				Name:        roleModel.Name,
-				Info:        roleModel.Info,
-				Tags:        roleModel.Tags,
-				Permissions: selfServiceRoleCmd.Permissions,
-			}
-			log.Log("Updating the role's permissions: " + roleModel.Name)
-			_, err := service_initializer.GetServices().RoleService.UpdateRole(roleModel.ID.Hex(), cmd, authContext)
-			if err != nil {
-				log.LogFatal("Error creating default roles: " + err.Error())
-				return
-			}
-
+			return role.Manifest{}, fmt.Errorf("ROLE_MANIFEST_PATH: %w", err)
 		}
-		return
-	} else {
-		// Create the role if it does not exist
-		_, err := service_initializer.GetServices().RoleService.Create(selfServiceRoleCmd, authContext)
+		hostManifest, err := role.ParseManifest(manifestPath, content)
 		if err != nil {
-			log.LogFatal("Error creating default roles: " + err.Error())
-			return
+			return role.Manifest{}, err
 		}
+		roleManifest = roleManifest.Merge(hostManifest)
+		if err = roleManifest.Validate(); err != nil {
+			return role.Manifest{}, err
+		}
+	}
+
+	roleManifest.Keep = append(roleManifest.Keep, "ADMIN", os.Getenv("DEFAULT_ROLE_NAME"))
+	return roleManifest, nil
+}
+
+// reconcileRoles applies the role manifest and logs the changes
+func reconcileRoles(roleManifest role.Manifest) {
+	result, err := service_initializer.GetServices().RoleService.Reconcile(roleManifest, auth.CreateAdminAuthContext())
+	if err != nil {
+		log.LogFatal("Error reconciling roles: " + err.Error())
+		return
+	}
+	if result.IsEmpty() {
+		log.Log("Roles are up to date with the role manifest")
+		return
 	}
+	log.Log("Roles reconciled with the role manifest:\n" + result.String())
 }
 
 func initMetrics(r *gin.Engine) {
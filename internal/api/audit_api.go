@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/middlewares"
+	"github.com/LydiaTrack/ground/pkg/service_initializer"
+	"github.com/gin-gonic/gin"
+)
+
+// InitAudit initializes audit routes
+func InitAudit(r *gin.Engine, services service_initializer.Services) {
+
+	auditHandler := handlers.NewAuditHandler(*services.AuditService, *services.AuthService, *services.UserService)
+
+	routerGroup := r.Group("/audits")
+	routerGroup.Use(middlewares.JwtAuthMiddleware())
+	middlewares.Handle(routerGroup, http.MethodGet, "", middlewares.All(permissions.AuditReadPermission), auditHandler.GetAudits)
+
+	log.Log("Audit routes initialized")
+}
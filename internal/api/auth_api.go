@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/pkg/middlewares"
 	"github.com/LydiaTrack/ground/pkg/service_initializer"
 	"github.com/gin-gonic/gin"
 )
@@ -17,4 +18,5 @@ func InitAuth(r *gin.Engine, services service_initializer.Services) {
 	routeGroup.GET("/currentUser", authHandler.GetCurrentUser)
 	routeGroup.POST("/refreshToken", authHandler.RefreshToken)
 	routeGroup.POST("/oauth/:provider", authHandler.OAuthLogin)
+	routeGroup.POST("/switchOrganization", middlewares.JwtAuthMiddleware(), authHandler.SwitchOrganization)
 }
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/service_initializer"
+	"github.com/gin-gonic/gin"
+)
+
+// InitAvatar initializes avatar routes, the uploads are initialized with the user routes
+func InitAvatar(r *gin.Engine, services service_initializer.Services) {
+
+	avatarHandler := handlers.NewAvatarHandler(*services.AvatarService, *services.AuthService, *services.UserService)
+
+	// Avatars are displayed wherever the users are, the URL is enough to get them
+	r.Group("/avatars").GET("/:id", avatarHandler.GetAvatar)
+
+	log.Log("Avatar routes initialized")
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/middlewares"
+	"github.com/LydiaTrack/ground/pkg/service_initializer"
+	"github.com/gin-gonic/gin"
+)
+
+// InitChangelog initializes changelog routes
+func InitChangelog(r *gin.Engine, services service_initializer.Services) {
+
+	changelogHandler := handlers.NewChangelogHandler(*services.ChangelogService, *services.AuthService, *services.UserService)
+
+	routerGroup := r.Group("/changelog")
+	routerGroup.Use(middlewares.JwtAuthMiddleware())
+	middlewares.Handle(routerGroup, http.MethodGet, "/unseen", middlewares.All(permissions.UserSelfGetPermission), changelogHandler.GetUnseenChangelog)
+	middlewares.Handle(routerGroup, http.MethodPost, "/seen", middlewares.All(permissions.UserSelfUpdatePermission), changelogHandler.MarkChangelogSeen)
+	middlewares.Handle(routerGroup, http.MethodGet, "", middlewares.All(permissions.ChangelogReadPermission), changelogHandler.GetChangelogEntries)
+	middlewares.Handle(routerGroup, http.MethodGet, "/:id", middlewares.All(permissions.ChangelogReadPermission), changelogHandler.GetChangelogEntry)
+	middlewares.Handle(routerGroup, http.MethodPost, "", middlewares.All(permissions.ChangelogCreatePermission), changelogHandler.CreateChangelogEntry)
+	middlewares.Handle(routerGroup, http.MethodPut, "/:id", middlewares.All(permissions.ChangelogUpdatePermission), changelogHandler.UpdateChangelogEntry)
+	middlewares.Handle(routerGroup, http.MethodDelete, "/:id", middlewares.All(permissions.ChangelogDeletePermission), changelogHandler.DeleteChangelogEntry)
+
+	log.Log("Changelog routes initialized")
+}
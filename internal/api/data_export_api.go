@@ -0,0 +1,19 @@
+package api
+
+import (
+	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/service_initializer"
+	"github.com/gin-gonic/gin"
+)
+
+// InitDataExport initializes data export routes, the export requests are initialized with the user routes
+func InitDataExport(r *gin.Engine, services service_initializer.Services) {
+
+	dataExportHandler := handlers.NewDataExportHandler(*services.DataExportService, *services.AuthService, *services.UserService)
+
+	// The download link is opened from the email, the token authenticates it
+	r.Group("/data-exports").GET("/download", dataExportHandler.DownloadExport)
+
+	log.Log("Data export routes initialized")
+}
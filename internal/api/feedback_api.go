@@ -2,15 +2,18 @@ package api
 
 import (
 	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/pkg/middlewares"
 	"github.com/LydiaTrack/ground/pkg/service_initializer"
 	"github.com/gin-gonic/gin"
 )
 
 func InitFeedback(r *gin.Engine, services service_initializer.Services) {
-	feedbackHandler := handlers.NewFeedbackHandler(*services.FeedbackService)
+	feedbackHandler := handlers.NewFeedbackHandler(*services.FeedbackService, *services.AuthService, *services.UserService)
 
 	routeGroup := r.Group("/feedback")
+	routeGroup.Use(middlewares.JwtAuthMiddleware())
 	routeGroup.POST("", feedbackHandler.CreateFeedback)
+	routeGroup.GET("", feedbackHandler.GetFeedbacks)
 	routeGroup.GET("/user/:userID", feedbackHandler.GetFeedbackByUser)
 	routeGroup.PUT("/:feedbackID/status", feedbackHandler.UpdateFeedbackStatus)
 }
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/middlewares"
+	"github.com/LydiaTrack/ground/pkg/service_initializer"
+	"github.com/gin-gonic/gin"
+)
+
+// InitGroup initializes group routes
+func InitGroup(r *gin.Engine, services service_initializer.Services) {
+
+	groupHandler := handlers.NewGroupHandler(*services.GroupService, *services.AuthService, *services.UserService)
+
+	routerGroup := r.Group("/groups")
+	routerGroup.Use(middlewares.JwtAuthMiddleware())
+	middlewares.Handle(routerGroup, http.MethodGet, "", middlewares.All(permissions.GroupReadPermission), groupHandler.GetGroups)
+	middlewares.Handle(routerGroup, http.MethodGet, "/:id", middlewares.All(permissions.GroupReadPermission), groupHandler.GetGroup)
+	middlewares.Handle(routerGroup, http.MethodPost, "", middlewares.All(permissions.GroupCreatePermission), groupHandler.CreateGroup)
+	middlewares.Handle(routerGroup, http.MethodPut, "/:id", middlewares.All(permissions.GroupUpdatePermission), groupHandler.UpdateGroup)
+	middlewares.Handle(routerGroup, http.MethodDelete, "/:id", middlewares.All(permissions.GroupDeletePermission), groupHandler.DeleteGroup)
+	middlewares.Handle(routerGroup, http.MethodGet, "/:id/members", middlewares.All(permissions.GroupMemberReadPermission), groupHandler.GetMembers)
+	middlewares.Handle(routerGroup, http.MethodPost, "/:id/members", middlewares.All(permissions.GroupMemberManagePermission), groupHandler.AddMember)
+	middlewares.Handle(routerGroup, http.MethodDelete, "/:id/members/:userId", middlewares.All(permissions.GroupMemberManagePermission), groupHandler.RemoveMember)
+
+	log.Log("Group routes initialized")
+}
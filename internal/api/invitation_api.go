@@ -0,0 +1,30 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/middlewares"
+	"github.com/LydiaTrack/ground/pkg/service_initializer"
+	"github.com/gin-gonic/gin"
+)
+
+// InitInvitation initializes invitation routes
+func InitInvitation(r *gin.Engine, services service_initializer.Services) {
+
+	invitationHandler := handlers.NewInvitationHandler(*services.InvitationService, *services.AuthService, *services.UserService)
+
+	// The invitee does not have an account yet
+	r.Group("/invitations").POST("/accept", invitationHandler.AcceptInvitation)
+
+	routerGroup := r.Group("/invitations")
+	routerGroup.Use(middlewares.JwtAuthMiddleware())
+	middlewares.Handle(routerGroup, http.MethodGet, "", middlewares.All(permissions.InvitationReadPermission), invitationHandler.GetInvitations)
+	middlewares.Handle(routerGroup, http.MethodGet, "/:id", middlewares.All(permissions.InvitationReadPermission), invitationHandler.GetInvitation)
+	middlewares.Handle(routerGroup, http.MethodPost, "", middlewares.All(permissions.InvitationCreatePermission), invitationHandler.CreateInvitation)
+	middlewares.Handle(routerGroup, http.MethodDelete, "/:id", middlewares.All(permissions.InvitationDeletePermission), invitationHandler.RevokeInvitation)
+
+	log.Log("Invitation routes initialized")
+}
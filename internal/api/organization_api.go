@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/middlewares"
+	"github.com/LydiaTrack/ground/pkg/service_initializer"
+	"github.com/gin-gonic/gin"
+)
+
+// InitOrganization initializes organization routes
+func InitOrganization(r *gin.Engine, services service_initializer.Services) {
+
+	organizationHandler := handlers.NewOrganizationHandler(*services.OrganizationService, *services.AuthService, *services.UserService)
+
+	routerGroup := r.Group("/organizations")
+	routerGroup.Use(middlewares.JwtAuthMiddleware())
+	middlewares.Handle(routerGroup, http.MethodGet, "", middlewares.All(permissions.OrganizationReadPermission), organizationHandler.GetOrganizations)
+	middlewares.Handle(routerGroup, http.MethodGet, "/mine", middlewares.All(), organizationHandler.GetMyOrganizations)
+	middlewares.Handle(routerGroup, http.MethodGet, "/:id", middlewares.All(permissions.OrganizationReadPermission).InTenant(), organizationHandler.GetOrganization)
+	middlewares.Handle(routerGroup, http.MethodPost, "", middlewares.All(permissions.OrganizationCreatePermission), organizationHandler.CreateOrganization)
+	middlewares.Handle(routerGroup, http.MethodPut, "/:id", middlewares.All(permissions.OrganizationUpdatePermission).InTenant(), organizationHandler.UpdateOrganization)
+	middlewares.Handle(routerGroup, http.MethodDelete, "/:id", middlewares.All(permissions.OrganizationDeletePermission), organizationHandler.DeleteOrganization)
+	middlewares.Handle(routerGroup, http.MethodGet, "/:id/members", middlewares.All(permissions.OrganizationMemberReadPermission).InTenant(), organizationHandler.GetMembers)
+	middlewares.Handle(routerGroup, http.MethodPost, "/:id/members", middlewares.All(permissions.OrganizationMemberManagePermission).InTenant(), organizationHandler.AddMember)
+	middlewares.Handle(routerGroup, http.MethodPut, "/:id/members/:userId", middlewares.All(permissions.OrganizationMemberManagePermission).InTenant(), organizationHandler.UpdateMemberRoles)
+	middlewares.Handle(routerGroup, http.MethodDelete, "/:id/members/:userId", middlewares.All(permissions.OrganizationMemberManagePermission).InTenant(), organizationHandler.RemoveMember)
+
+	log.Log("Organization routes initialized")
+}
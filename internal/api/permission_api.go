@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/middlewares"
+	"github.com/LydiaTrack/ground/pkg/service_initializer"
+	"github.com/gin-gonic/gin"
+)
+
+// InitPermission initializes permission routes
+func InitPermission(r *gin.Engine, services service_initializer.Services) {
+
+	permissionHandler := handlers.NewPermissionHandler(*services.PermissionService, *services.AuthService, *services.UserService)
+
+	routerGroup := r.Group("/permissions")
+	routerGroup.Use(middlewares.JwtAuthMiddleware())
+	middlewares.Handle(routerGroup, http.MethodGet, "", middlewares.All(permissions.RoleReadPermission), permissionHandler.GetPermissions)
+	middlewares.Handle(routerGroup, http.MethodGet, "/routes", middlewares.All(permissions.RoleReadPermission), permissionHandler.GetRoutePermissions)
+	middlewares.Handle(routerGroup, http.MethodPost, "/explain", middlewares.All(permissions.PermissionExplainPermission), permissionHandler.ExplainPermission)
+
+	log.Log("Permission routes initialized")
+}
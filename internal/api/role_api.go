@@ -1,24 +1,30 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/internal/permissions"
 	"github.com/LydiaTrack/ground/pkg/log"
 	"github.com/LydiaTrack/ground/pkg/middlewares"
 	"github.com/LydiaTrack/ground/pkg/service_initializer"
 	"github.com/gin-gonic/gin"
 )
 
-// InitUser initializes role routes
+// InitRole initializes role routes
 func InitRole(r *gin.Engine, services service_initializer.Services) {
 
 	roleHandler := handlers.NewRoleHandler(*services.RoleService, *services.AuthService, *services.UserService)
 
 	routerGroup := r.Group("/roles")
 	routerGroup.Use(middlewares.JwtAuthMiddleware())
-	routerGroup.GET("", roleHandler.GetRoles)
-	routerGroup.GET("/:id", roleHandler.GetRole)
-	routerGroup.POST("", roleHandler.CreateRole)
-	routerGroup.DELETE("/:id", roleHandler.DeleteRole)
+	middlewares.Handle(routerGroup, http.MethodGet, "", middlewares.All(permissions.RoleReadPermission), roleHandler.GetRoles)
+	middlewares.Handle(routerGroup, http.MethodGet, "/:id", middlewares.All(permissions.RoleReadPermission), roleHandler.GetRole)
+	middlewares.Handle(routerGroup, http.MethodGet, "/:id/users", middlewares.All(permissions.RoleReadPermission, permissions.UserReadPermission), roleHandler.GetRoleUsers)
+	middlewares.Handle(routerGroup, http.MethodPost, "", middlewares.All(permissions.RoleCreatePermission), roleHandler.CreateRole)
+	middlewares.Handle(routerGroup, http.MethodPut, "/:id", middlewares.All(permissions.RoleUpdatePermission), roleHandler.UpdateRole)
+	middlewares.Handle(routerGroup, http.MethodPatch, "/:id", middlewares.All(permissions.RoleUpdatePermission), roleHandler.PatchRole)
+	middlewares.Handle(routerGroup, http.MethodDelete, "/:id", middlewares.All(permissions.RoleDeletePermission), roleHandler.DeleteRole)
 
 	log.Log("Role routes initialized")
 }
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/internal/permissions"
 	"github.com/LydiaTrack/ground/pkg/log"
 	"github.com/LydiaTrack/ground/pkg/middlewares"
 	"github.com/LydiaTrack/ground/pkg/service_initializer"
@@ -12,6 +15,13 @@ import (
 func InitUser(r *gin.Engine, services service_initializer.Services) {
 
 	userHandler := handlers.NewUserHandler(*services.UserService, *services.AuthService)
+	accountStatusHandler := handlers.NewAccountStatusHandler(*services.AccountStatusService, *services.AuthService, *services.UserService)
+	accountDeletionHandler := handlers.NewAccountDeletionHandler(*services.AccountDeletionService, *services.AuthService, *services.UserService)
+	dataExportHandler := handlers.NewDataExportHandler(*services.DataExportService, *services.AuthService, *services.UserService)
+	userImportHandler := handlers.NewUserImportHandler(*services.UserImportService, *services.AuthService, *services.UserService)
+	avatarHandler := handlers.NewAvatarHandler(*services.AvatarService, *services.AuthService, *services.UserService)
+	phoneVerificationHandler := handlers.NewPhoneVerificationHandler(*services.PhoneVerificationService, *services.AuthService, *services.UserService)
+	settingsHandler := handlers.NewSettingsHandler(*services.SettingsService, *services.AuthService, *services.UserService)
 
 	routerGroup := r.Group("/users")
 	routerGroup.Use(middlewares.JwtAuthMiddleware()).
@@ -23,6 +33,15 @@ func InitUser(r *gin.Engine, services service_initializer.Services) {
 		GET("/roles/:id", userHandler.GetUserRoles).
 		POST("/roles", userHandler.AddRoleToUser).
 		DELETE("/roles", userHandler.RemoveRoleFromUser)
+	middlewares.Handle(routerGroup, http.MethodPatch, "/:id", middlewares.All(permissions.UserUpdatePermission), userHandler.PatchUser)
+	middlewares.Handle(routerGroup, http.MethodPut, "/:id/status", middlewares.All(permissions.UserStatusUpdatePermission), accountStatusHandler.ChangeStatus)
+	middlewares.Handle(routerGroup, http.MethodPost, "/:id/restore", middlewares.All(permissions.UserRestorePermission), userHandler.RestoreUser)
+	middlewares.Handle(routerGroup, http.MethodGet, "/:id/identity-history", middlewares.All(permissions.UserIdentityHistoryReadPermission), userHandler.GetUserIdentityHistory)
+	middlewares.Handle(routerGroup, http.MethodPost, "/import", middlewares.All(permissions.UserImportPermission), userImportHandler.ImportUsers)
+	middlewares.Handle(routerGroup, http.MethodGet, "/export", middlewares.All(permissions.UserBulkExportPermission), userImportHandler.ExportUsers)
+	middlewares.Handle(routerGroup, http.MethodPost, "/:id/export", middlewares.All(permissions.UserExportPermission), dataExportHandler.ExportUser)
+	middlewares.Handle(routerGroup, http.MethodPut, "/:id/avatar", middlewares.All(permissions.UserUpdatePermission), avatarHandler.UploadUserAvatar)
+	middlewares.Handle(routerGroup, http.MethodDelete, "/:id/avatar", middlewares.All(permissions.UserUpdatePermission), avatarHandler.RemoveUserAvatar)
 	checkUsernameGroup := r.Group("/users/checkUsername")
 	checkUsernameGroup.GET("/:username", userHandler.CheckUsername)
 
@@ -33,6 +52,14 @@ func InitUser(r *gin.Engine, services service_initializer.Services) {
 	selfRouterGroup.Use(middlewares.JwtAuthMiddleware()).
 		PUT("", userHandler.UpdateUserSelf).
 		PUT("/password", userHandler.UpdateUserSelfPassword)
+	middlewares.Handle(selfRouterGroup, http.MethodDelete, "", middlewares.All(permissions.UserSelfDeletePermission), accountDeletionHandler.DeleteSelf)
+	middlewares.Handle(selfRouterGroup, http.MethodPost, "/export", middlewares.All(permissions.UserSelfExportPermission), dataExportHandler.ExportSelf)
+	middlewares.Handle(selfRouterGroup, http.MethodPut, "/avatar", middlewares.All(permissions.UserSelfUpdatePermission), avatarHandler.UploadSelfAvatar)
+	middlewares.Handle(selfRouterGroup, http.MethodDelete, "/avatar", middlewares.All(permissions.UserSelfUpdatePermission), avatarHandler.RemoveSelfAvatar)
+	middlewares.Handle(selfRouterGroup, http.MethodPost, "/phone/verification", middlewares.All(permissions.UserSelfUpdatePermission), phoneVerificationHandler.SendPhoneVerificationCode)
+	middlewares.Handle(selfRouterGroup, http.MethodPost, "/phone/verify", middlewares.All(permissions.UserSelfUpdatePermission), phoneVerificationHandler.VerifyPhone)
+	middlewares.Handle(selfRouterGroup, http.MethodGet, "/settings/:namespace", middlewares.All(permissions.UserSelfGetPermission), settingsHandler.GetSelfSettings)
+	middlewares.Handle(selfRouterGroup, http.MethodPut, "/settings/:namespace", middlewares.All(permissions.UserSelfUpdatePermission), settingsHandler.UpdateSelfSettings)
 
 	log.Log("User routes initialized")
 }
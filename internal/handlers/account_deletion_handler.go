@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AccountDeletionHandler struct {
+	accountDeletionService service.AccountDeletionService
+	authService            auth.Service
+	userService            service.UserService
+}
+
+func NewAccountDeletionHandler(accountDeletionService service.AccountDeletionService, authService auth.Service, userService service.UserService) AccountDeletionHandler {
+	return AccountDeletionHandler{
+		accountDeletionService: accountDeletionService,
+		authService:            authService,
+		userService:            userService,
+	}
+}
+
+// DeleteSelf godoc
+// @Summary Delete own account
+// @Description schedule the deletion of the own account after the cooling-off period. Logging in cancels it.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param command body user.DeleteSelfCommand true "Password, or OAuth token for accounts without a password"
+// @Success 200 {object} map[string]interface{}
+// @Router /users-self [delete]
+func (h AccountDeletionHandler) DeleteSelf(c *gin.Context) {
+	var deleteCmd user.DeleteSelfCommand
+	if err := c.ShouldBindJSON(&deleteCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	scheduledAt, err := h.accountDeletionService.DeleteSelf(deleteCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deletionScheduledAt": scheduledAt})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AccountStatusHandler struct {
+	accountStatusService service.AccountStatusService
+	authService          auth.Service
+	userService          service.UserService
+}
+
+func NewAccountStatusHandler(accountStatusService service.AccountStatusService, authService auth.Service, userService service.UserService) AccountStatusHandler {
+	return AccountStatusHandler{
+		accountStatusService: accountStatusService,
+		authService:          authService,
+		userService:          userService,
+	}
+}
+
+// ChangeStatus godoc
+// @Summary Change account status
+// @Description suspend, ban, deactivate or reactivate an account. Sessions of inactive accounts are revoked.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param command body user.ChangeStatusCommand true "Status"
+// @Success 200 {object} user.Model
+// @Router /users/:id/status [put]
+func (h AccountStatusHandler) ChangeStatus(c *gin.Context) {
+	var changeCmd user.ChangeStatusCommand
+	if err := c.ShouldBindJSON(&changeCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	userModel, err := h.accountStatusService.ChangeStatus(c.Param("id"), changeCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, userModel)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler defines the HTTP handler for audit-related operations
+type AuditHandler struct {
+	auditService service.AuditService
+	authService  auth.Service
+	userService  service.UserService
+}
+
+// NewAuditHandler creates a new AuditHandler instance
+func NewAuditHandler(auditService service.AuditService, authService auth.Service, userService service.UserService) AuditHandler {
+	return AuditHandler{
+		auditService: auditService,
+		authService:  authService,
+		userService:  userService,
+	}
+}
+
+// GetAudits godoc
+// @Summary Get audits
+// @Description get the audits in the order of their instants, paginated by page or by cursor.
+// @Tags audits
+// @Accept */*
+// @Produce json
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Param cursor query string false "Cursor of the page, empty for the first page. Selects keyset pagination instead of pages"
+// @Param count query bool false "Count the total with keyset pagination"
+// @Param search query string false "Search text"
+// @Success 200 {object} map[string]interface{}
+// @Router /audits [get]
+func (h AuditHandler) GetAudits(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	searchText := c.DefaultQuery("search", "")
+
+	cursorRequest, cursorMode, err := parseCursorRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if cursorMode {
+		result, err := h.auditService.QueryCursor(searchText, cursorRequest, authContext)
+		if err != nil {
+			utils.EvaluateError(err, c)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	result, err := h.auditService.QueryPaginated(searchText, page, limit, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
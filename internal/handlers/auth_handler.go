@@ -9,6 +9,7 @@ import (
 	"github.com/LydiaTrack/ground/internal/blocker"
 	"github.com/LydiaTrack/ground/pkg/auth"
 	"github.com/LydiaTrack/ground/pkg/auth/types"
+	"github.com/LydiaTrack/ground/pkg/domain/organization"
 	"github.com/LydiaTrack/ground/pkg/domain/user"
 	"github.com/LydiaTrack/ground/pkg/log"
 	"github.com/LydiaTrack/ground/pkg/service_initializer"
@@ -131,7 +132,7 @@ func (h AuthHandler) SignUp(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Router /currentUser [get]
 func (h AuthHandler) GetCurrentUser(c *gin.Context) {
-	userModel, err := h.authService.GetCurrentUser(c)
+	userModel, err := h.authService.GetCurrentSelfUser(c)
 	if err != nil {
 		utils.EvaluateError(err, c)
 		return
@@ -172,6 +173,31 @@ func (h AuthHandler) RefreshToken(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// SwitchOrganization godoc
+// @Summary Switch organization
+// @Description issue a token pair with the given organization as the active one, an empty ID leaves the organization.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param command body organization.SwitchOrganizationCommand true "Organization to switch to"
+// @Success 200 {object} map[string]interface{}
+// @Router /auth/switchOrganization [post]
+func (h AuthHandler) SwitchOrganization(c *gin.Context) {
+	var switchCmd organization.SwitchOrganizationCommand
+	if err := c.ShouldBindJSON(&switchCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.authService.SwitchOrganization(c, switchCmd.OrganizationID)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 // OAuthLogin godoc
 // @Summary OAuth login
 // @Description login with OAuth provider (Google or Apple).
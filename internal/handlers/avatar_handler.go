@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AvatarHandler struct {
+	avatarService service.AvatarService
+	authService   auth.Service
+	userService   service.UserService
+}
+
+func NewAvatarHandler(avatarService service.AvatarService, authService auth.Service, userService service.UserService) AvatarHandler {
+	return AvatarHandler{
+		avatarService: avatarService,
+		authService:   authService,
+		userService:   userService,
+	}
+}
+
+// errAvatarTooLarge is returned for avatar uploads larger than user.MaxAvatarUploadSize
+var errAvatarTooLarge = errors.New("avatar exceeds the maximum upload size of " + strconv.Itoa(user.MaxAvatarUploadSize) + " bytes")
+
+// UploadSelfAvatar godoc
+// @Summary Upload own avatar
+// @Description replace the own avatar with a PNG, JPEG or GIF image, cropped to a square and resized to the standard sizes.
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Param avatar formData file true "Avatar image"
+// @Success 200 {object} user.Model
+// @Router /users-self/avatar [put]
+func (h AvatarHandler) UploadSelfAvatar(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	data, ok := readAvatar(c)
+	if !ok {
+		return
+	}
+
+	userModel, err := h.avatarService.UploadSelf(data, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, userModel)
+}
+
+// RemoveSelfAvatar godoc
+// @Summary Remove own avatar
+// @Description remove the own avatar.
+// @Tags users
+// @Accept */*
+// @Produce json
+// @Success 200 {object} user.Model
+// @Router /users-self/avatar [delete]
+func (h AvatarHandler) RemoveSelfAvatar(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	userModel, err := h.avatarService.RemoveSelf(authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, userModel)
+}
+
+// UploadUserAvatar godoc
+// @Summary Upload user avatar
+// @Description replace the avatar of a user with a PNG, JPEG or GIF image, cropped to a square and resized to the standard sizes.
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Param avatar formData file true "Avatar image"
+// @Success 200 {object} user.Model
+// @Router /users/:id/avatar [put]
+func (h AvatarHandler) UploadUserAvatar(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	data, ok := readAvatar(c)
+	if !ok {
+		return
+	}
+
+	userModel, err := h.avatarService.Upload(c.Param("id"), data, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, userModel)
+}
+
+// RemoveUserAvatar godoc
+// @Summary Remove user avatar
+// @Description remove the avatar of a user.
+// @Tags users
+// @Accept */*
+// @Produce json
+// @Success 200 {object} user.Model
+// @Router /users/:id/avatar [delete]
+func (h AvatarHandler) RemoveUserAvatar(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	userModel, err := h.avatarService.Remove(c.Param("id"), authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, userModel)
+}
+
+// GetAvatar godoc
+// @Summary Get avatar
+// @Description get an uploaded avatar in the smallest standard size that is at least the requested one, the largest by default.
+// @Tags avatars
+// @Accept */*
+// @Produce image/png
+// @Param size query int false "Requested size in pixels"
+// @Success 200 {file} file
+// @Router /avatars/:id [get]
+func (h AvatarHandler) GetAvatar(c *gin.Context) {
+	size := 0
+	if sizeParam := c.Query("size"); sizeParam != "" {
+		var err error
+		if size, err = strconv.Atoi(sizeParam); err != nil || size < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "size must be a positive integer"})
+			return
+		}
+	}
+
+	blob, err := h.avatarService.Get(c.Param("id"), size)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	// A new upload gets a new avatar ID, so the content behind a URL never changes
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Data(http.StatusOK, blob.ContentType, blob.Data)
+}
+
+// readAvatar reads the avatar file of a multipart upload, writing the error response if it cannot be read
+func readAvatar(c *gin.Context) ([]byte, bool) {
+	// Leave room for the multipart headers around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, user.MaxAvatarUploadSize+64*1024)
+	fileHeader, err := c.FormFile("avatar")
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errAvatarTooLarge.Error()})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the avatar must be uploaded as the avatar field of a multipart form"})
+		return nil, false
+	}
+	if fileHeader.Size > user.MaxAvatarUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errAvatarTooLarge.Error()})
+		return nil, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return data, true
+}
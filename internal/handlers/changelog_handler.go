@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/changelog"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ChangelogHandler struct {
+	changelogService service.ChangelogService
+	authService      auth.Service
+	userService      service.UserService
+}
+
+func NewChangelogHandler(changelogService service.ChangelogService, authService auth.Service, userService service.UserService) ChangelogHandler {
+	return ChangelogHandler{
+		changelogService: changelogService,
+		authService:      authService,
+		userService:      userService,
+	}
+}
+
+// GetChangelogEntries godoc
+// @Summary Get changelog entries
+// @Description get changelog entries including unpublished ones, paginated, the latest published first.
+// @Tags changelog
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /changelog [get]
+func (h ChangelogHandler) GetChangelogEntries(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	result, err := h.changelogService.QueryPaginated(c.DefaultQuery("search", ""), page, limit, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetChangelogEntry godoc
+// @Summary Get changelog entry by ID
+// @Description get changelog entry by ID.
+// @Tags changelog
+// @Accept */*
+// @Produce json
+// @Success 200 {object} changelog.Model
+// @Router /changelog/:id [get]
+func (h ChangelogHandler) GetChangelogEntry(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	entry, err := h.changelogService.Get(c.Param("id"), authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// CreateChangelogEntry godoc
+// @Summary Create changelog entry
+// @Description create the release notes of a version, published now unless a publish date is given.
+// @Tags changelog
+// @Accept json
+// @Produce json
+// @Param command body changelog.CreateChangelogEntryCommand true "Changelog entry"
+// @Success 200 {object} changelog.Model
+// @Router /changelog [post]
+func (h ChangelogHandler) CreateChangelogEntry(c *gin.Context) {
+	var createCmd changelog.CreateChangelogEntryCommand
+	if err := c.ShouldBindJSON(&createCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	entry, err := h.changelogService.Create(createCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// UpdateChangelogEntry godoc
+// @Summary Update changelog entry
+// @Description replace the content, publish date and audience of a changelog entry.
+// @Tags changelog
+// @Accept json
+// @Produce json
+// @Param command body changelog.UpdateChangelogEntryCommand true "Changelog entry"
+// @Success 200 {object} changelog.Model
+// @Router /changelog/:id [put]
+func (h ChangelogHandler) UpdateChangelogEntry(c *gin.Context) {
+	var updateCmd changelog.UpdateChangelogEntryCommand
+	if err := c.ShouldBindJSON(&updateCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	entry, err := h.changelogService.Update(c.Param("id"), updateCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// DeleteChangelogEntry godoc
+// @Summary Delete changelog entry
+// @Description delete a changelog entry.
+// @Tags changelog
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /changelog/:id [delete]
+func (h ChangelogHandler) DeleteChangelogEntry(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	if err = h.changelogService.Delete(c.Param("id"), authContext); err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Changelog entry deleted successfully"})
+}
+
+// GetUnseenChangelog godoc
+// @Summary Get unseen changelog entries
+// @Description get the published entries newer than the last seen changelog version of the current user, the newest version first.
+// @Tags changelog
+// @Accept */*
+// @Produce json
+// @Success 200 {array} changelog.Model
+// @Router /changelog/unseen [get]
+func (h ChangelogHandler) GetUnseenChangelog(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	entries, err := h.changelogService.GetUnseen(authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// MarkChangelogSeen godoc
+// @Summary Mark changelog as seen
+// @Description advance the last seen changelog version of the current user to the given version, or to the newest visible entry.
+// @Tags changelog
+// @Accept json
+// @Produce json
+// @Param command body changelog.MarkSeenCommand false "Seen version"
+// @Success 200 {object} user.Model
+// @Router /changelog/seen [post]
+func (h ChangelogHandler) MarkChangelogSeen(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	// The body is optional, without it every published entry is marked as seen
+	var seenCmd changelog.MarkSeenCommand
+	if err := c.ShouldBindJSON(&seenCmd); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userModel, err := h.changelogService.MarkSeen(seenCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, userModel)
+}
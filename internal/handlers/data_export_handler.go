@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type DataExportHandler struct {
+	dataExportService service.DataExportService
+	authService       auth.Service
+	userService       service.UserService
+}
+
+func NewDataExportHandler(dataExportService service.DataExportService, authService auth.Service, userService service.UserService) DataExportHandler {
+	return DataExportHandler{
+		dataExportService: dataExportService,
+		authService:       authService,
+		userService:       userService,
+	}
+}
+
+// ExportSelf godoc
+// @Summary Export own data
+// @Description request an export of the own data. The download link is sent by email when the export is ready.
+// @Tags users
+// @Accept */*
+// @Produce json
+// @Success 202 {object} dataexport.Model
+// @Router /users-self/export [post]
+func (h DataExportHandler) ExportSelf(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	exportModel, err := h.dataExportService.ExportSelf(authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusAccepted, exportModel)
+}
+
+// ExportUser godoc
+// @Summary Export user data
+// @Description request an export of the data of a user. The download link is sent to the user by email.
+// @Tags users
+// @Accept */*
+// @Produce json
+// @Success 202 {object} dataexport.Model
+// @Router /users/:id/export [post]
+func (h DataExportHandler) ExportUser(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	exportModel, err := h.dataExportService.Export(c.Param("id"), authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusAccepted, exportModel)
+}
+
+// DownloadExport godoc
+// @Summary Download data export
+// @Description download the ZIP archive of a data export with the token of the download link.
+// @Tags users
+// @Accept */*
+// @Produce application/zip
+// @Param token query string true "Download token"
+// @Success 200 {file} file
+// @Router /data-exports/download [get]
+func (h DataExportHandler) DownloadExport(c *gin.Context) {
+	exportModel, archive, err := h.dataExportService.Download(c.Query("token"))
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"export-%s.zip\"", exportModel.ID.Hex()))
+	c.Data(http.StatusOK, archive.ContentType, archive.Data)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag sets the ETag of the response to the version of the document
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// parseIfMatch parses the version the If-Match header expects, nil if the header is missing or matches any version.
+// Documents stored before they were versioned have version zero.
+func parseIfMatch(c *gin.Context) (*int, error) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return nil, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 0 {
+		return nil, errors.New("invalid If-Match header")
+	}
+	return &version, nil
+}
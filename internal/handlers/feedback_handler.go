@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
 	"github.com/LydiaTrack/ground/pkg/domain/feedback"
 	"github.com/LydiaTrack/ground/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -12,11 +14,17 @@ import (
 // FeedbackHandler defines the HTTP handler for feedback-related operations
 type FeedbackHandler struct {
 	feedbackService service.FeedbackService
+	authService     auth.Service
+	userService     service.UserService
 }
 
 // NewFeedbackHandler creates a new FeedbackHandler instance
-func NewFeedbackHandler(feedbackService service.FeedbackService) FeedbackHandler {
-	return FeedbackHandler{feedbackService: feedbackService}
+func NewFeedbackHandler(feedbackService service.FeedbackService, authService auth.Service, userService service.UserService) FeedbackHandler {
+	return FeedbackHandler{
+		feedbackService: feedbackService,
+		authService:     authService,
+		userService:     userService,
+	}
 }
 
 // CreateFeedback godoc
@@ -35,7 +43,13 @@ func (h FeedbackHandler) CreateFeedback(c *gin.Context) {
 		return
 	}
 
-	newFeedback, err := h.feedbackService.CreateFeedback(cmd)
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	newFeedback, err := h.feedbackService.CreateFeedback(cmd, authContext)
 	if err != nil {
 		utils.EvaluateError(err, c)
 		return
@@ -44,6 +58,59 @@ func (h FeedbackHandler) CreateFeedback(c *gin.Context) {
 	c.JSON(http.StatusOK, newFeedback)
 }
 
+// GetFeedbacks godoc
+// @Summary Get Feedbacks
+// @Description Retrieve the feedbacks, the latest first, paginated by page or by cursor.
+// @Tags feedback
+// @Accept json
+// @Produce json
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Param cursor query string false "Cursor of the page, empty for the first page. Selects keyset pagination instead of pages"
+// @Param count query bool false "Count the total with keyset pagination"
+// @Success 200 {object} map[string]interface{}
+// @Router /feedback [get]
+func (h FeedbackHandler) GetFeedbacks(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	cursorRequest, cursorMode, err := parseCursorRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if cursorMode {
+		result, err := h.feedbackService.QueryCursor(cursorRequest, authContext)
+		if err != nil {
+			utils.EvaluateError(err, c)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	result, err := h.feedbackService.QueryPaginated(page, limit, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 // GetFeedbackByUser godoc
 // @Summary Get Feedback by User
 // @Description Retrieve all feedback submitted by a specific user.
@@ -56,7 +123,13 @@ func (h FeedbackHandler) CreateFeedback(c *gin.Context) {
 func (h FeedbackHandler) GetFeedbackByUser(c *gin.Context) {
 	userIDParam := c.Param("userID")
 
-	feedbacks, err := h.feedbackService.GetFeedbacksByUser(userIDParam)
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	feedbacks, err := h.feedbackService.GetFeedbacksByUser(userIDParam, authContext)
 	if err != nil {
 		utils.EvaluateError(err, c)
 		return
@@ -71,12 +144,12 @@ func (h FeedbackHandler) GetFeedbackByUser(c *gin.Context) {
 // @Tags feedback
 // @Accept json
 // @Produce json
-// @Param id path string true "Feedback ID"
+// @Param feedbackID path string true "Feedback ID"
 // @Param status body feedback.FeedbackStatus true "New Status"
 // @Success 200 {object} feedback.Feedback
-// @Router /feedback/{id}/status [put]
+// @Router /feedback/{feedbackID}/status [put]
 func (h FeedbackHandler) UpdateFeedbackStatus(c *gin.Context) {
-	feedbackIDParam := c.Param("id")
+	feedbackIDParam := c.Param("feedbackID")
 
 	var status struct {
 		Status feedback.FeedbackStatus `json:"status"`
@@ -86,13 +159,19 @@ func (h FeedbackHandler) UpdateFeedbackStatus(c *gin.Context) {
 		return
 	}
 
-	err := h.feedbackService.UpdateFeedbackStatus(feedbackIDParam, status.Status)
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	err = h.feedbackService.UpdateFeedbackStatus(feedbackIDParam, status.Status, authContext)
 	if err != nil {
 		utils.EvaluateError(err, c)
 		return
 	}
 
-	updatedFeedback, err := h.feedbackService.GetFeedback(feedbackIDParam)
+	updatedFeedback, err := h.feedbackService.GetFeedback(feedbackIDParam, authContext)
 	if err != nil {
 		utils.EvaluateError(err, c)
 		return
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/group"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type GroupHandler struct {
+	groupService service.GroupService
+	authService  auth.Service
+	userService  service.UserService
+}
+
+func NewGroupHandler(groupService service.GroupService, authService auth.Service, userService service.UserService) GroupHandler {
+	return GroupHandler{
+		groupService: groupService,
+		authService:  authService,
+		userService:  userService,
+	}
+}
+
+// GetGroups godoc
+// @Summary Get groups
+// @Description get groups, paginated.
+// @Tags groups
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /groups [get]
+func (h GroupHandler) GetGroups(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	result, err := h.groupService.QueryPaginated(c.DefaultQuery("search", ""), page, limit, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetGroup godoc
+// @Summary Get group by ID
+// @Description get group by ID.
+// @Tags groups
+// @Accept */*
+// @Produce json
+// @Success 200 {object} group.Model
+// @Router /groups/:id [get]
+func (h GroupHandler) GetGroup(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	groupModel, err := h.groupService.Get(c.Param("id"), authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, groupModel)
+}
+
+// CreateGroup godoc
+// @Summary Create group
+// @Description create group with its roles and parent groups.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param command body group.CreateGroupCommand true "Group"
+// @Success 200 {object} group.Model
+// @Router /groups [post]
+func (h GroupHandler) CreateGroup(c *gin.Context) {
+	var createCmd group.CreateGroupCommand
+	if err := c.ShouldBindJSON(&createCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	groupModel, err := h.groupService.Create(createCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, groupModel)
+}
+
+// UpdateGroup godoc
+// @Summary Update group
+// @Description update group, its roles and parent groups.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param command body group.UpdateGroupCommand true "Group"
+// @Success 200 {object} group.Model
+// @Router /groups/:id [put]
+func (h GroupHandler) UpdateGroup(c *gin.Context) {
+	var updateCmd group.UpdateGroupCommand
+	if err := c.ShouldBindJSON(&updateCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	groupModel, err := h.groupService.Update(c.Param("id"), updateCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, groupModel)
+}
+
+// DeleteGroup godoc
+// @Summary Delete group
+// @Description delete group, its members are removed and nested groups are detached.
+// @Tags groups
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /groups/:id [delete]
+func (h GroupHandler) DeleteGroup(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	if err = h.groupService.Delete(c.Param("id"), authContext); err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Group deleted successfully"})
+}
+
+// GetMembers godoc
+// @Summary Get group members
+// @Description get the direct members of the group, paginated.
+// @Tags groups
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /groups/:id/members [get]
+func (h GroupHandler) GetMembers(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	result, err := h.groupService.GetMembers(c.Param("id"), page, limit, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// AddMember godoc
+// @Summary Add group member
+// @Description add a user to the group.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param command body group.AddGroupMemberCommand true "Member"
+// @Success 200 {object} group.Member
+// @Router /groups/:id/members [post]
+func (h GroupHandler) AddMember(c *gin.Context) {
+	var addCmd group.AddGroupMemberCommand
+	if err := c.ShouldBindJSON(&addCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	member, err := h.groupService.AddMember(c.Param("id"), addCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember godoc
+// @Summary Remove group member
+// @Description remove the user from the group.
+// @Tags groups
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /groups/:id/members/:userId [delete]
+func (h GroupHandler) RemoveMember(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	if err = h.groupService.RemoveMember(c.Param("id"), c.Param("userId"), authContext); err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/invitation"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationHandler struct {
+	invitationService service.InvitationService
+	authService       auth.Service
+	userService       service.UserService
+}
+
+func NewInvitationHandler(invitationService service.InvitationService, authService auth.Service, userService service.UserService) InvitationHandler {
+	return InvitationHandler{
+		invitationService: invitationService,
+		authService:       authService,
+		userService:       userService,
+	}
+}
+
+// GetInvitations godoc
+// @Summary Get invitations
+// @Description get invitations, paginated and optionally filtered by status.
+// @Tags invitations
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /invitations [get]
+func (h InvitationHandler) GetInvitations(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	result, err := h.invitationService.QueryPaginated(c.DefaultQuery("search", ""), c.DefaultQuery("status", ""), page, limit, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetInvitation godoc
+// @Summary Get invitation by ID
+// @Description get invitation by ID.
+// @Tags invitations
+// @Accept */*
+// @Produce json
+// @Success 200 {object} invitation.Model
+// @Router /invitations/:id [get]
+func (h InvitationHandler) GetInvitation(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	invitationModel, err := h.invitationService.Get(c.Param("id"), authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, invitationModel)
+}
+
+// CreateInvitation godoc
+// @Summary Create invitation
+// @Description invite a user by email with preassigned roles, the invitee receives the token by email.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Param command body invitation.CreateInvitationCommand true "Invitation"
+// @Success 200 {object} invitation.Model
+// @Router /invitations [post]
+func (h InvitationHandler) CreateInvitation(c *gin.Context) {
+	var createCmd invitation.CreateInvitationCommand
+	if err := c.ShouldBindJSON(&createCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	invitationModel, err := h.invitationService.Create(createCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, invitationModel)
+}
+
+// RevokeInvitation godoc
+// @Summary Revoke invitation
+// @Description revoke a pending invitation.
+// @Tags invitations
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /invitations/:id [delete]
+func (h InvitationHandler) RevokeInvitation(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	if err = h.invitationService.Revoke(c.Param("id"), authContext); err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked successfully"})
+}
+
+// AcceptInvitation godoc
+// @Summary Accept invitation
+// @Description create the account of the invitee with the email and roles of the invitation.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Param command body invitation.AcceptInvitationCommand true "Account"
+// @Success 200 {object} map[string]interface{}
+// @Router /invitations/accept [post]
+func (h InvitationHandler) AcceptInvitation(c *gin.Context) {
+	var acceptCmd invitation.AcceptInvitationCommand
+	if err := c.ShouldBindJSON(&acceptCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userModel, err := h.invitationService.Accept(acceptCmd)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, h.userService.SelfView(userModel))
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/organization"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationHandler struct {
+	organizationService service.OrganizationService
+	authService         auth.Service
+	userService         service.UserService
+}
+
+func NewOrganizationHandler(organizationService service.OrganizationService, authService auth.Service, userService service.UserService) OrganizationHandler {
+	return OrganizationHandler{
+		organizationService: organizationService,
+		authService:         authService,
+		userService:         userService,
+	}
+}
+
+// GetOrganizations godoc
+// @Summary Get organizations
+// @Description get every organization, paginated.
+// @Tags organizations
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /organizations [get]
+func (h OrganizationHandler) GetOrganizations(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	result, err := h.organizationService.QueryPaginated(c.DefaultQuery("search", ""), page, limit, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetMyOrganizations godoc
+// @Summary Get my organizations
+// @Description get the organizations the current user is a member of.
+// @Tags organizations
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /organizations/mine [get]
+func (h OrganizationHandler) GetMyOrganizations(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	result, err := h.organizationService.GetMine(authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetOrganization godoc
+// @Summary Get organization by ID
+// @Description get organization by ID.
+// @Tags organizations
+// @Accept */*
+// @Produce json
+// @Success 200 {object} organization.Model
+// @Router /organizations/:id [get]
+func (h OrganizationHandler) GetOrganization(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	organizationModel, err := h.organizationService.Get(c.Param("id"), authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, organizationModel)
+}
+
+// CreateOrganization godoc
+// @Summary Create organization
+// @Description create organization.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param command body organization.CreateOrganizationCommand true "Organization"
+// @Success 200 {object} organization.Model
+// @Router /organizations [post]
+func (h OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var createCmd organization.CreateOrganizationCommand
+	if err := c.ShouldBindJSON(&createCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	organizationModel, err := h.organizationService.Create(createCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, organizationModel)
+}
+
+// UpdateOrganization godoc
+// @Summary Update organization
+// @Description update organization.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param command body organization.UpdateOrganizationCommand true "Organization"
+// @Success 200 {object} organization.Model
+// @Router /organizations/:id [put]
+func (h OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	var updateCmd organization.UpdateOrganizationCommand
+	if err := c.ShouldBindJSON(&updateCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	organizationModel, err := h.organizationService.Update(c.Param("id"), updateCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, organizationModel)
+}
+
+// DeleteOrganization godoc
+// @Summary Delete organization
+// @Description delete organization together with its memberships.
+// @Tags organizations
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /organizations/:id [delete]
+func (h OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	if err = h.organizationService.Delete(c.Param("id"), authContext); err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Organization deleted successfully"})
+}
+
+// GetMembers godoc
+// @Summary Get organization members
+// @Description get the members of the organization with their roles, paginated.
+// @Tags organizations
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /organizations/:id/members [get]
+func (h OrganizationHandler) GetMembers(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	result, err := h.organizationService.GetMembers(c.Param("id"), page, limit, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// AddMember godoc
+// @Summary Add organization member
+// @Description add a user to the organization with the given roles.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param command body organization.AddMemberCommand true "Member"
+// @Success 200 {object} organization.Membership
+// @Router /organizations/:id/members [post]
+func (h OrganizationHandler) AddMember(c *gin.Context) {
+	var addCmd organization.AddMemberCommand
+	if err := c.ShouldBindJSON(&addCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	membership, err := h.organizationService.AddMember(c.Param("id"), addCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, membership)
+}
+
+// UpdateMemberRoles godoc
+// @Summary Update organization member roles
+// @Description replace the roles the user holds in the organization.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Param command body organization.UpdateMemberRolesCommand true "Roles"
+// @Success 200 {object} organization.Membership
+// @Router /organizations/:id/members/:userId [put]
+func (h OrganizationHandler) UpdateMemberRoles(c *gin.Context) {
+	var updateCmd organization.UpdateMemberRolesCommand
+	if err := c.ShouldBindJSON(&updateCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	membership, err := h.organizationService.UpdateMemberRoles(c.Param("id"), c.Param("userId"), updateCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, membership)
+}
+
+// RemoveMember godoc
+// @Summary Remove organization member
+// @Description remove the user from the organization.
+// @Tags organizations
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /organizations/:id/members/:userId [delete]
+func (h OrganizationHandler) RemoveMember(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	if err = h.organizationService.RemoveMember(c.Param("id"), c.Param("userId"), authContext); err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// parseCursorRequest parses the keyset pagination parameters and reports whether keyset pagination is requested.
+// The cursor parameter selects it: it is empty for the first page, then the next or prev cursor of the previous
+// response. The limit is at most repository.MaxLimit and the total is only counted with count=true.
+func parseCursorRequest(c *gin.Context) (repository.CursorRequest, bool, error) {
+	cursor, ok := c.GetQuery("cursor")
+	if !ok {
+		return repository.CursorRequest{}, false, nil
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		return repository.CursorRequest{}, true, errors.New("invalid limit parameter")
+	}
+	count, err := strconv.ParseBool(c.DefaultQuery("count", "false"))
+	if err != nil {
+		return repository.CursorRequest{}, true, errors.New("invalid count parameter")
+	}
+
+	return repository.CursorRequest{Cursor: cursor, Limit: min(limit, repository.MaxLimit), Count: count}, true, nil
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/LydiaTrack/ground/pkg/mongodb/patch"
+	"github.com/gin-gonic/gin"
+)
+
+// readPatch reads the patch of the request and its content type, responding with an error if it is neither a
+// merge patch nor a JSON patch
+func readPatch(c *gin.Context) (string, []byte, bool) {
+	contentType := c.ContentType()
+	if contentType != patch.MergePatchContentType && contentType != patch.JSONPatchContentType {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": fmt.Sprintf("%v: %q", patch.ErrUnsupportedContentType, contentType)})
+		return "", nil, false
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", nil, false
+	}
+	return contentType, data, true
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/permission"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PermissionHandler struct {
+	permissionService service.PermissionService
+	authService       auth.Service
+	userService       service.UserService
+}
+
+func NewPermissionHandler(permissionService service.PermissionService, authService auth.Service, userService service.UserService) PermissionHandler {
+	return PermissionHandler{
+		permissionService: permissionService,
+		authService:       authService,
+		userService:       userService,
+	}
+}
+
+// GetPermissions godoc
+// @Summary Get permissions
+// @Description get every permission that can be assigned to roles.
+// @Tags permissions
+// @Accept */*
+// @Produce json
+// @Success 200 {array} auth.Permission
+// @Router /permissions [get]
+func (h PermissionHandler) GetPermissions(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	permissionList, err := h.permissionService.GetAll(authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, permissionList)
+}
+
+// GetRoutePermissions godoc
+// @Summary Get route permissions
+// @Description get the permissions required by each route, for security review.
+// @Tags permissions
+// @Accept */*
+// @Produce json
+// @Success 200 {array} middlewares.RoutePermission
+// @Router /permissions/routes [get]
+func (h PermissionHandler) GetRoutePermissions(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	routePermissions, err := h.permissionService.GetRoutePermissions(authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, routePermissions)
+}
+
+// ExplainPermission godoc
+// @Summary Explain permission
+// @Description explain why a user is allowed or denied a permission, with the roles contributing each permission.
+// @Tags permissions
+// @Accept json
+// @Produce json
+// @Param command body permission.ExplainCommand true "User and required permission"
+// @Success 200 {object} permission.Explanation
+// @Router /permissions/explain [post]
+func (h PermissionHandler) ExplainPermission(c *gin.Context) {
+	var explainCmd permission.ExplainCommand
+	if err := c.ShouldBindJSON(&explainCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	explanation, err := h.permissionService.Explain(explainCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, explanation)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/phoneverification"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PhoneVerificationHandler struct {
+	phoneVerificationService service.PhoneVerificationService
+	authService              auth.Service
+	userService              service.UserService
+}
+
+func NewPhoneVerificationHandler(phoneVerificationService service.PhoneVerificationService, authService auth.Service, userService service.UserService) PhoneVerificationHandler {
+	return PhoneVerificationHandler{
+		phoneVerificationService: phoneVerificationService,
+		authService:              authService,
+		userService:              userService,
+	}
+}
+
+// SendPhoneVerificationCode godoc
+// @Summary Send phone verification code
+// @Description send a one-time code by SMS to the own phone number, resends are rate limited.
+// @Tags users
+// @Accept */*
+// @Produce json
+// @Success 201 {object} phoneverification.Model
+// @Router /users-self/phone/verification [post]
+func (h PhoneVerificationHandler) SendPhoneVerificationCode(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	verification, err := h.phoneVerificationService.SendCode(authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusCreated, verification)
+}
+
+// VerifyPhone godoc
+// @Summary Verify phone number
+// @Description verify the own phone number with the code sent to it.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param verifyPhoneCommand body phoneverification.VerifyPhoneCommand true "Verification code"
+// @Success 200 {object} user.Model
+// @Router /users-self/phone/verify [post]
+func (h PhoneVerificationHandler) VerifyPhone(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	var verifyCmd phoneverification.VerifyPhoneCommand
+	if err := c.ShouldBindJSON(&verifyCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userModel, err := h.phoneVerificationService.Verify(verifyCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, userModel)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/LydiaTrack/ground/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type RoleHandler struct {
@@ -29,10 +30,15 @@ func NewRoleHandler(roleService service.RoleService, authService auth.Service, u
 
 // GetRoles godoc
 // @Summary Get all roles
-// @Description get all roles.
+// @Description get all roles, paginated by page or by cursor.
 // @Tags root
 // @Accept */*
 // @Produce json
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Param cursor query string false "Cursor of the page, empty for the first page. Selects keyset pagination instead of pages"
+// @Param count query bool false "Count the total with keyset pagination"
+// @Param search query string false "Search text"
 // @Success 200 {object} map[string]interface{}
 // @Router /roles [get]
 func (h RoleHandler) GetRoles(c *gin.Context) {
@@ -48,6 +54,22 @@ func (h RoleHandler) GetRoles(c *gin.Context) {
 	limitStr := c.Query("limit")
 	searchText := c.DefaultQuery("search", "")
 
+	cursorRequest, cursorMode, err := parseCursorRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if cursorMode {
+		// Handle keyset paginated query
+		result, err := h.roleService.QueryCursor(searchText, cursorRequest, authContext)
+		if err != nil {
+			utils.EvaluateError(err, c)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
 	if pageStr != "" && limitStr != "" {
 		// Handle paginated query
 		// Handle paginated request
@@ -89,11 +111,12 @@ func (h RoleHandler) GetRoles(c *gin.Context) {
 
 // GetRole godoc
 // @Summary Get role by ID
-// @Description get the status of server.
+// @Description get the role with its direct and effective (inherited) permissions.
 // @Tags root
 // @Accept */*
 // @Produce json
 // @Success 200 {object} map[string]interface{}
+// @Header 200 {string} ETag "Version of the role"
 // @Router /roles:id [get]
 func (h RoleHandler) GetRole(c *gin.Context) {
 	id := c.Param("id")
@@ -104,11 +127,12 @@ func (h RoleHandler) GetRole(c *gin.Context) {
 		return
 	}
 
-	getRoleResult, err := h.roleService.Get(id, authContext)
+	getRoleResult, err := h.roleService.GetEffective(id, authContext)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	setETag(c, getRoleResult.Version)
 	c.JSON(http.StatusOK, getRoleResult)
 }
 
@@ -135,18 +159,144 @@ func (h RoleHandler) CreateRole(c *gin.Context) {
 
 	roleModel, err := h.roleService.Create(createCmd, authContext)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, roleModel)
+}
+
+// UpdateRole godoc
+// @Summary Update role
+// @Description update role.
+// @Tags root
+// @Accept */*
+// @Produce json
+// @Param If-Match header string false "ETag of the role the update applies to"
+// @Success 200 {object} map[string]interface{}
+// @Header 200 {string} ETag "Version of the role"
+// @Failure 409 {object} map[string]interface{}
+// @Router /roles/:id [put]
+func (h RoleHandler) UpdateRole(c *gin.Context) {
+	id := c.Param("id")
+	var updateCmd role.UpdateRoleCommand
+	if err := c.ShouldBindJSON(&updateCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updateCmd.ExpectedVersion = expectedVersion
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	roleModel, err := h.roleService.UpdateRole(id, updateCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	setETag(c, roleModel.Version)
+	c.JSON(http.StatusOK, roleModel)
+}
+
+// PatchRole godoc
+// @Summary Patch role
+// @Description partially update a role with a JSON merge patch (RFC 7396) or a JSON patch (RFC 6902).
+// @Description Only name, info, tags, permissions and parentIds can be patched.
+// @Tags root
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param If-Match header string false "ETag of the role the patch applies to"
+// @Success 200 {object} map[string]interface{}
+// @Header 200 {string} ETag "Version of the role"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /roles/:id [patch]
+func (h RoleHandler) PatchRole(c *gin.Context) {
+	contentType, data, ok := readPatch(c)
+	if !ok {
+		return
+	}
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	roleModel, err := h.roleService.PatchRole(c.Param("id"), role.PatchRoleCommand{
+		ContentType:     contentType,
+		Patch:           data,
+		ExpectedVersion: expectedVersion,
+	}, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
 		return
 	}
+	setETag(c, roleModel.Version)
 	c.JSON(http.StatusOK, roleModel)
 }
 
+// GetRoleUsers godoc
+// @Summary Get role users
+// @Description get the users that have the role assigned, paginated.
+// @Tags root
+// @Accept */*
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /roles/:id/users [get]
+func (h RoleHandler) GetRoleUsers(c *gin.Context) {
+	roleID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+		return
+	}
+
+	result, err := h.userService.QueryByRolePaginated(roleID, c.DefaultQuery("search", ""), page, limit, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 // DeleteRole godoc
 // @Summary Delete role
-// @Description delete role.
+// @Description delete role. Refuses with 409 while users hold the role, unless cascade=true is given.
 // @Tags root
 // @Accept */*
 // @Produce json
+// @Param cascade query bool false "Remove the role from its users and child roles before deleting"
 // @Success 200 {object} map[string]interface{}
 // @Router /roles:id [delete]
 func (h RoleHandler) DeleteRole(c *gin.Context) {
@@ -158,7 +308,11 @@ func (h RoleHandler) DeleteRole(c *gin.Context) {
 		return
 	}
 
-	err = h.roleService.Delete(id, authContext)
+	if c.Query("cascade") == "true" {
+		err = h.roleService.DeleteCascade(id, authContext)
+	} else {
+		err = h.roleService.Delete(id, authContext)
+	}
 	if err != nil {
 		utils.EvaluateError(err, c)
 		return
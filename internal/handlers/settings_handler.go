@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/settings"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	settingsService service.SettingsService
+	authService     auth.Service
+	userService     service.UserService
+}
+
+func NewSettingsHandler(settingsService service.SettingsService, authService auth.Service, userService service.UserService) SettingsHandler {
+	return SettingsHandler{
+		settingsService: settingsService,
+		authService:     authService,
+		userService:     userService,
+	}
+}
+
+// GetSelfSettings godoc
+// @Summary Get own settings
+// @Description get the own settings of a namespace, the defaults fill in the keys that were not set.
+// @Tags users
+// @Accept */*
+// @Produce json
+// @Success 200 {object} settings.Settings
+// @Router /users-self/settings/:namespace [get]
+func (h SettingsHandler) GetSelfSettings(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	namespaceSettings, err := h.settingsService.GetSelf(c.Param("namespace"), authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, namespaceSettings)
+}
+
+// UpdateSelfSettings godoc
+// @Summary Update own settings
+// @Description set the given keys of a namespace of the own settings, the other keys are kept. Null values reset the keys to their defaults.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param updateSettingsCommand body settings.UpdateSettingsCommand true "Settings"
+// @Success 200 {object} settings.Settings
+// @Router /users-self/settings/:namespace [put]
+func (h SettingsHandler) UpdateSelfSettings(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	var updateCmd settings.UpdateSettingsCommand
+	if err := c.ShouldBindJSON(&updateCmd); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	namespaceSettings, err := h.settingsService.UpdateSelf(c.Param("namespace"), updateCmd, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, namespaceSettings)
+}
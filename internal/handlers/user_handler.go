@@ -8,6 +8,7 @@ import (
 	"github.com/LydiaTrack/ground/internal/service"
 	"github.com/LydiaTrack/ground/pkg/auth"
 	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/registry"
 	"github.com/LydiaTrack/ground/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -27,7 +28,7 @@ func NewUserHandler(userService service.UserService, authService auth.Service) U
 
 // CheckUsername
 // @Summary Check username
-// @Description check username.
+// @Description check if the username is taken or reserved for the user who released it.
 // @Tags root
 // @Accept */*
 // @Produce json
@@ -65,11 +66,19 @@ func (h UserHandler) CheckEmail(c *gin.Context) {
 
 // GetUsers godoc
 // @Summary Get users
-// @Description get users.
+// @Description get users, filtered by field[operator]=value parameters and sorted by sort=-field,field.
+// @Description The filters are role, status, createdDate, oauthProvider, emailDomain and properties.<key>.
 // @Tags root
 // @Accept */*
 // @Produce json
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Param cursor query string false "Cursor of the page, empty for the first page. Selects keyset pagination instead of pages"
+// @Param count query bool false "Count the total with keyset pagination"
+// @Param search query string false "Search text"
+// @Param sort query string false "Comma separated sort fields, a leading - sorts descending"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Router /users [get]
 func (h UserHandler) GetUsers(c *gin.Context) {
 
@@ -84,6 +93,28 @@ func (h UserHandler) GetUsers(c *gin.Context) {
 	limitStr := c.Query("limit")
 	searchText := c.DefaultQuery("search", "")
 
+	userQuery, err := user.QuerySchemaWithProperties(registry.GetUserPropertiesSchema().Schema).Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cursorRequest, cursorMode, err := parseCursorRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if cursorMode {
+		// Handle keyset paginated request
+		result, err := h.userService.QueryCursor(searchText, userQuery, cursorRequest, authContext)
+		if err != nil {
+			utils.EvaluateError(err, c)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
 	if pageStr != "" && limitStr != "" {
 		// Handle paginated request
 		page, err := strconv.Atoi(pageStr)
@@ -99,7 +130,7 @@ func (h UserHandler) GetUsers(c *gin.Context) {
 		}
 
 		var userQueryPaginatedResult responses.PaginatedResult[user.Model]
-		userQueryPaginatedResult, err = h.userService.QueryPaginated(searchText, page, limit, authContext)
+		userQueryPaginatedResult, err = h.userService.QueryPaginated(searchText, userQuery, page, limit, authContext)
 
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -110,7 +141,7 @@ func (h UserHandler) GetUsers(c *gin.Context) {
 	} else {
 		// Handle non-paginated request
 		var userQueryResult responses.QueryResult[user.Model]
-		userQueryResult, err = h.userService.Query(searchText, authContext)
+		userQueryResult, err = h.userService.Query(searchText, userQuery, authContext)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -127,6 +158,7 @@ func (h UserHandler) GetUsers(c *gin.Context) {
 // @Accept */*
 // @Produce json
 // @Success 200 {object} map[string]interface{}
+// @Header 200 {string} ETag "Version of the user"
 // @Router /users:id [get]
 func (h UserHandler) GetUser(c *gin.Context) {
 	id := c.Param("id")
@@ -142,6 +174,7 @@ func (h UserHandler) GetUser(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	setETag(c, userModel.Version)
 	c.JSON(http.StatusOK, userModel)
 }
 
@@ -207,6 +240,52 @@ func (h UserHandler) DeleteUser(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// RestoreUser godoc
+// @Summary Restore user
+// @Description restore a deleted user within the deletion grace period.
+// @Tags root
+// @Accept */*
+// @Produce json
+// @Success 200 {object} user.Model
+// @Router /users/:id/restore [post]
+func (h UserHandler) RestoreUser(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	restoredUser, err := h.userService.Restore(c.Param("id"), authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, restoredUser)
+}
+
+// GetUserIdentityHistory godoc
+// @Summary Get user identity history
+// @Description get the username and email changes of a user, the latest first. Released usernames stay reserved until reservedUntil.
+// @Tags root
+// @Accept */*
+// @Produce json
+// @Success 200 {array} user.IdentityChange
+// @Router /users/:id/identity-history [get]
+func (h UserHandler) GetUserIdentityHistory(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	history, err := h.userService.GetIdentityHistory(c.Param("id"), authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
 // AddRoleToUser godoc
 // @Summary Add role to user
 // @Description add role to user.
@@ -299,7 +378,10 @@ func (h UserHandler) GetUserRoles(c *gin.Context) {
 // @Tags root
 // @Accept */*
 // @Produce json
+// @Param If-Match header string false "ETag of the user the update applies to"
 // @Success 200 {object} map[string]interface{}
+// @Header 200 {string} ETag "Version of the user"
+// @Failure 409 {object} map[string]interface{}
 // @Router /users/:id [put]
 func (h UserHandler) UpdateUser(c *gin.Context) {
 	id := c.Param("id")
@@ -308,6 +390,12 @@ func (h UserHandler) UpdateUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updateUserCommand.ExpectedVersion = expectedVersion
 
 	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
 	if err != nil {
@@ -325,16 +413,64 @@ func (h UserHandler) UpdateUser(c *gin.Context) {
 		utils.EvaluateError(err, c)
 		return
 	}
+	setETag(c, updatedUser.Version)
 	c.JSON(http.StatusOK, updatedUser)
 }
 
+// PatchUser godoc
+// @Summary Patch user
+// @Description partially update a user with a JSON merge patch (RFC 7396) or a JSON patch (RFC 6902).
+// @Description Only username, avatar, personInfo, contactInfo, properties and lastSeenChangelogVersion can be patched.
+// @Tags root
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param If-Match header string false "ETag of the user the patch applies to"
+// @Success 200 {object} map[string]interface{}
+// @Header 200 {string} ETag "Version of the user"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Router /users/:id [patch]
+func (h UserHandler) PatchUser(c *gin.Context) {
+	contentType, data, ok := readPatch(c)
+	if !ok {
+		return
+	}
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	patchedUser, err := h.userService.Patch(c.Param("id"), user.PatchUserCommand{
+		ContentType:     contentType,
+		Patch:           data,
+		ExpectedVersion: expectedVersion,
+	}, authContext)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	setETag(c, patchedUser.Version)
+	c.JSON(http.StatusOK, patchedUser)
+}
+
 // UpdateUserSelf godoc
 // @Summary Update user self
 // @Description update user self.
 // @Tags root
 // @Accept */*
 // @Produce json
+// @Param If-Match header string false "ETag of the user the update applies to"
 // @Success 200 {object} map[string]interface{}
+// @Header 200 {string} ETag "Version of the user"
+// @Failure 409 {object} map[string]interface{}
 // @Router /users-self [put]
 func (h UserHandler) UpdateUserSelf(c *gin.Context) {
 	var updateUserCommand user.UpdateUserCommand
@@ -342,6 +478,12 @@ func (h UserHandler) UpdateUserSelf(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updateUserCommand.ExpectedVersion = expectedVersion
 
 	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
 	if err != nil {
@@ -354,6 +496,7 @@ func (h UserHandler) UpdateUserSelf(c *gin.Context) {
 		utils.EvaluateError(err, c)
 		return
 	}
+	setETag(c, updatedUser.Version)
 	c.JSON(http.StatusOK, updatedUser)
 }
 
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// errImportTooLarge is returned for imports larger than user.MaxImportUploadSize
+var errImportTooLarge = errors.New("the import exceeds the maximum upload size of " + strconv.Itoa(user.MaxImportUploadSize) + " bytes")
+
+type UserImportHandler struct {
+	userImportService service.UserImportService
+	authService       auth.Service
+	userService       service.UserService
+}
+
+func NewUserImportHandler(userImportService service.UserImportService, authService auth.Service, userService service.UserService) UserImportHandler {
+	return UserImportHandler{
+		userImportService: userImportService,
+		authService:       authService,
+		userService:       userService,
+	}
+}
+
+// ImportUsers godoc
+// @Summary Import users
+// @Description create or update users in bulk from a CSV or NDJSON upload of at most 10 MB and 10000 rows, and report the outcome of every row.
+// @Tags users
+// @Accept text/csv,application/x-ndjson,multipart/form-data
+// @Produce json
+// @Param format query string false "csv or ndjson, detected from the content type by default"
+// @Param dryRun query bool false "Validate without writing"
+// @Param matchBy query string false "email or username, defaults to email"
+// @Param invite query bool false "Invite the rows without a password"
+// @Success 200 {object} user.ImportReport
+// @Router /users/import [post]
+func (h UserImportHandler) ImportUsers(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	// Leave room for the multipart headers around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, user.MaxImportUploadSize+64*1024)
+	var body io.Reader = c.Request.Body
+	contentType := c.ContentType()
+	fileHeader, err := c.FormFile("file")
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errImportTooLarge.Error()})
+		return
+	}
+	if err == nil {
+		if fileHeader.Size > user.MaxImportUploadSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errImportTooLarge.Error()})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+		body = file
+		contentType = fileContentTypes[strings.ToLower(path.Ext(fileHeader.Filename))]
+	}
+
+	format := user.ImportFormat(c.DefaultQuery("format", string(importFormatOf(contentType))))
+	if !format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format parameter"})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dryRun parameter"})
+		return
+	}
+	invite, err := strconv.ParseBool(c.DefaultQuery("invite", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invite parameter"})
+		return
+	}
+
+	report, err := h.userImportService.Import(format, body, user.ImportOptions{
+		DryRun:  dryRun,
+		MatchBy: user.ImportMatchField(c.DefaultQuery("matchBy", "")),
+		Invite:  invite,
+	}, authContext)
+	if errors.As(err, &maxBytesError) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errImportTooLarge.Error()})
+		return
+	}
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ExportUsers godoc
+// @Summary Export users
+// @Description stream every user with the selected fields as CSV or NDJSON.
+// @Tags users
+// @Accept */*
+// @Produce text/csv,application/x-ndjson
+// @Param format query string false "csv or ndjson, defaults to csv"
+// @Param fields query string false "Fields separated by commas"
+// @Success 200 {file} file
+// @Router /users/export [get]
+func (h UserImportHandler) ExportUsers(c *gin.Context) {
+	authContext, err := auth.CreateAuthContext(c, h.authService, &h.userService)
+	if err != nil {
+		utils.EvaluateError(err, c)
+		return
+	}
+
+	format := user.ImportFormat(c.DefaultQuery("format", string(user.ImportFormatCSV)))
+	if !format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format parameter"})
+		return
+	}
+
+	var fields []string
+	if selected := c.Query("fields"); selected != "" {
+		for _, field := range strings.Split(selected, ",") {
+			fields = append(fields, strings.TrimSpace(field))
+		}
+	}
+
+	contentType := "text/csv"
+	if format == user.ImportFormatNDJSON {
+		contentType = "application/x-ndjson"
+	}
+
+	// The permissions and fields are checked before anything is written, later errors can only be logged
+	// as the response is already streaming
+	writer := &headerWriter{ResponseWriter: c.Writer, contentType: contentType, filename: "users." + string(format)}
+	if err = h.userImportService.Export(format, fields, writer, authContext); err != nil {
+		if !writer.written {
+			utils.EvaluateError(err, c)
+			return
+		}
+		log.LogError("Failed to export users: %v", err)
+	}
+}
+
+// headerWriter sets the download headers of an export on the first write
+type headerWriter struct {
+	gin.ResponseWriter
+	contentType string
+	filename    string
+	written     bool
+}
+
+func (w *headerWriter) Write(data []byte) (int, error) {
+	if !w.written {
+		w.written = true
+		w.Header().Set("Content-Type", w.contentType)
+		w.Header().Set("Content-Disposition", "attachment; filename=\""+w.filename+"\"")
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// importFormatOf detects the import format from a content type
+func importFormatOf(contentType string) user.ImportFormat {
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return user.ImportFormatCSV
+	case strings.Contains(contentType, "ndjson"), strings.Contains(contentType, "jsonl"):
+		return user.ImportFormatNDJSON
+	default:
+		return ""
+	}
+}
+
+// fileContentTypes are the content types of the uploaded import files by extension
+var fileContentTypes = map[string]string{
+	".csv":    "text/csv",
+	".ndjson": "application/x-ndjson",
+	".jsonl":  "application/x-ndjson",
+}
@@ -20,7 +20,7 @@ func InitializeDefaultUser() error {
 	// While using remote connection for MongoDB instead of container, the user can be existed in the database.
 	// In this case, the default user will not be created.
 	roleService := service.NewRoleService(repository.GetRoleMongoRepository())
-	userService := service.NewUserService(repository.GetUserMongoRepository(repository.GetRoleMongoRepository()), *roleService, nil)
+	userService := service.NewUserService(repository.GetUserMongoRepository(repository.GetRoleMongoRepository()), *roleService, nil, nil, nil)
 	err := userService.InitializeDefaultRolesForAllUsers()
 	if err != nil {
 		return err
@@ -0,0 +1,6 @@
+package manifests
+
+import "embed"
+
+//go:embed *.yaml
+var FS embed.FS
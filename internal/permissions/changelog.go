@@ -0,0 +1,25 @@
+package permissions
+
+import (
+	"github.com/LydiaTrack/ground/pkg/auth"
+)
+
+var ChangelogCreatePermission = auth.Permission{
+	Domain: "changelog",
+	Action: "CREATE",
+}
+
+var ChangelogReadPermission = auth.Permission{
+	Domain: "changelog",
+	Action: "READ",
+}
+
+var ChangelogUpdatePermission = auth.Permission{
+	Domain: "changelog",
+	Action: "UPDATE",
+}
+
+var ChangelogDeletePermission = auth.Permission{
+	Domain: "changelog",
+	Action: "DELETE",
+}
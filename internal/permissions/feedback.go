@@ -0,0 +1,25 @@
+package permissions
+
+import (
+	"github.com/LydiaTrack/ground/pkg/auth"
+)
+
+var FeedbackCreatePermission = auth.Permission{
+	Domain: "feedback",
+	Action: "CREATE",
+}
+
+var FeedbackReadPermission = auth.Permission{
+	Domain: "feedback",
+	Action: "READ",
+}
+
+var FeedbackUpdatePermission = auth.Permission{
+	Domain: "feedback",
+	Action: "UPDATE",
+}
+
+var FeedbackDeletePermission = auth.Permission{
+	Domain: "feedback",
+	Action: "DELETE",
+}
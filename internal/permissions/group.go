@@ -0,0 +1,35 @@
+package permissions
+
+import (
+	"github.com/LydiaTrack/ground/pkg/auth"
+)
+
+var GroupCreatePermission = auth.Permission{
+	Domain: "group",
+	Action: "CREATE",
+}
+
+var GroupReadPermission = auth.Permission{
+	Domain: "group",
+	Action: "READ",
+}
+
+var GroupUpdatePermission = auth.Permission{
+	Domain: "group",
+	Action: "UPDATE",
+}
+
+var GroupDeletePermission = auth.Permission{
+	Domain: "group",
+	Action: "DELETE",
+}
+
+var GroupMemberReadPermission = auth.Permission{
+	Domain: "groupMember",
+	Action: "READ",
+}
+
+var GroupMemberManagePermission = auth.Permission{
+	Domain: "groupMember",
+	Action: "MANAGE",
+}
@@ -0,0 +1,20 @@
+package permissions
+
+import (
+	"github.com/LydiaTrack/ground/pkg/auth"
+)
+
+var InvitationCreatePermission = auth.Permission{
+	Domain: "invitation",
+	Action: "CREATE",
+}
+
+var InvitationReadPermission = auth.Permission{
+	Domain: "invitation",
+	Action: "READ",
+}
+
+var InvitationDeletePermission = auth.Permission{
+	Domain: "invitation",
+	Action: "DELETE",
+}
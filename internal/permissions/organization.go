@@ -0,0 +1,35 @@
+package permissions
+
+import (
+	"github.com/LydiaTrack/ground/pkg/auth"
+)
+
+var OrganizationCreatePermission = auth.Permission{
+	Domain: "organization",
+	Action: "CREATE",
+}
+
+var OrganizationReadPermission = auth.Permission{
+	Domain: "organization",
+	Action: "READ",
+}
+
+var OrganizationUpdatePermission = auth.Permission{
+	Domain: "organization",
+	Action: "UPDATE",
+}
+
+var OrganizationDeletePermission = auth.Permission{
+	Domain: "organization",
+	Action: "DELETE",
+}
+
+var OrganizationMemberReadPermission = auth.Permission{
+	Domain: "organizationMember",
+	Action: "READ",
+}
+
+var OrganizationMemberManagePermission = auth.Permission{
+	Domain: "organizationMember",
+	Action: "MANAGE",
+}
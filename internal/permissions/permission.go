@@ -0,0 +1,10 @@
+package permissions
+
+import (
+	"github.com/LydiaTrack/ground/pkg/auth"
+)
+
+var PermissionExplainPermission = auth.Permission{
+	Domain: "permission",
+	Action: "EXPLAIN",
+}
@@ -0,0 +1,26 @@
+package permissions
+
+import (
+	"github.com/LydiaTrack/ground/pkg/auth"
+)
+
+// UserStatsOwnerPolicy lets users read and update their own stats
+var UserStatsOwnerPolicy = auth.Policy{
+	Name:      "user-stats-owner",
+	Effect:    auth.EffectAllow,
+	Actions:   []auth.Permission{UserStatsReadPermission, UserStatsUpdatePermission},
+	Condition: auth.IsOwner,
+}
+
+// FeedbackOwnerPolicy lets users submit feedback and read the feedback they submitted
+var FeedbackOwnerPolicy = auth.Policy{
+	Name:      "feedback-owner",
+	Effect:    auth.EffectAllow,
+	Actions:   []auth.Permission{FeedbackCreatePermission, FeedbackReadPermission},
+	Condition: auth.IsOwner,
+}
+
+func init() {
+	auth.RegisterPolicy(UserStatsOwnerPolicy)
+	auth.RegisterPolicy(FeedbackOwnerPolicy)
+}
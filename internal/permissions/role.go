@@ -23,3 +23,10 @@ var RoleReadPermission = auth.Permission{
 	Domain: "role",
 	Action: "READ",
 }
+
+// RoleAssignPermission allows granting any role, without it only the roles whose permissions are all held can be
+// granted
+var RoleAssignPermission = auth.Permission{
+	Domain: "role",
+	Action: "ASSIGN",
+}
@@ -33,3 +33,43 @@ var UserReadPermission = auth.Permission{
 	Domain: "user",
 	Action: "READ",
 }
+
+var UserStatusUpdatePermission = auth.Permission{
+	Domain: "user",
+	Action: "UPDATE_STATUS",
+}
+
+var UserRestorePermission = auth.Permission{
+	Domain: "user",
+	Action: "RESTORE",
+}
+
+var UserSelfDeletePermission = auth.Permission{
+	Domain: "user",
+	Action: "SELF_DELETE",
+}
+
+var UserSelfExportPermission = auth.Permission{
+	Domain: "user",
+	Action: "SELF_EXPORT",
+}
+
+var UserExportPermission = auth.Permission{
+	Domain: "user",
+	Action: "EXPORT",
+}
+
+var UserImportPermission = auth.Permission{
+	Domain: "user",
+	Action: "IMPORT",
+}
+
+var UserBulkExportPermission = auth.Permission{
+	Domain: "user",
+	Action: "BULK_EXPORT",
+}
+
+var UserIdentityHistoryReadPermission = auth.Permission{
+	Domain: "user",
+	Action: "READ_IDENTITY_HISTORY",
+}
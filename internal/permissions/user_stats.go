@@ -0,0 +1,15 @@
+package permissions
+
+import (
+	"github.com/LydiaTrack/ground/pkg/auth"
+)
+
+var UserStatsReadPermission = auth.Permission{
+	Domain: "userStats",
+	Action: "READ",
+}
+
+var UserStatsUpdatePermission = auth.Permission{
+	Domain: "userStats",
+	Action: "UPDATE",
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+)
+
+type GroundPermissionProvider struct {
+}
+
+func (p GroundPermissionProvider) GetPermissions() []auth.Permission {
+	return []auth.Permission{
+		permissions.UserCreatePermission,
+		permissions.UserReadPermission,
+		permissions.UserStatusUpdatePermission,
+		permissions.UserRestorePermission,
+		permissions.UserUpdatePermission,
+		permissions.UserDeletePermission,
+		permissions.UserSelfGetPermission,
+		permissions.UserSelfUpdatePermission,
+		permissions.UserSelfDeletePermission,
+		permissions.UserSelfExportPermission,
+		permissions.UserExportPermission,
+		permissions.UserImportPermission,
+		permissions.UserBulkExportPermission,
+		permissions.UserIdentityHistoryReadPermission,
+		permissions.RoleCreatePermission,
+		permissions.RoleReadPermission,
+		permissions.RoleUpdatePermission,
+		permissions.RoleDeletePermission,
+		permissions.RoleAssignPermission,
+		permissions.AuditCreatePermission,
+		permissions.AuditReadPermission,
+		permissions.AuditDeletePermission,
+		permissions.UserStatsReadPermission,
+		permissions.UserStatsUpdatePermission,
+		permissions.FeedbackCreatePermission,
+		permissions.FeedbackReadPermission,
+		permissions.FeedbackUpdatePermission,
+		permissions.FeedbackDeletePermission,
+		permissions.PermissionExplainPermission,
+		permissions.OrganizationCreatePermission,
+		permissions.OrganizationReadPermission,
+		permissions.OrganizationUpdatePermission,
+		permissions.OrganizationDeletePermission,
+		permissions.OrganizationMemberReadPermission,
+		permissions.OrganizationMemberManagePermission,
+		permissions.GroupCreatePermission,
+		permissions.GroupReadPermission,
+		permissions.GroupUpdatePermission,
+		permissions.GroupDeletePermission,
+		permissions.GroupMemberReadPermission,
+		permissions.GroupMemberManagePermission,
+		permissions.InvitationCreatePermission,
+		permissions.InvitationReadPermission,
+		permissions.InvitationDeletePermission,
+		permissions.ChangelogCreatePermission,
+		permissions.ChangelogReadPermission,
+		permissions.ChangelogUpdatePermission,
+		permissions.ChangelogDeletePermission,
+	}
+}
@@ -0,0 +1,12 @@
+package provider
+
+import "github.com/LydiaTrack/ground/pkg/domain/role"
+
+// ManifestRoleProvider provides the roles marked as default in the role manifest
+type ManifestRoleProvider struct {
+	Manifest role.Manifest
+}
+
+func (p ManifestRoleProvider) GetDefaultRoleNames() []string {
+	return p.Manifest.GetDefaultRoleNames()
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"os"
+
+	"github.com/LydiaTrack/ground/pkg/mongodb"
+	"github.com/LydiaTrack/ground/pkg/storage"
+)
+
+const (
+	// LocalBlobStoreType stores the blobs under the BLOB_STORE_PATH directory
+	LocalBlobStoreType = "LOCAL"
+	// GridFSBlobStoreType stores the blobs in the blobs GridFS bucket of the database
+	GridFSBlobStoreType  = "GRIDFS"
+	defaultBlobStorePath = "blobs"
+	blobBucketName       = "blobs"
+)
+
+// GetBlobStore creates the blob store selected by BLOB_STORE_TYPE, GridFS by default
+func GetBlobStore() storage.BlobStore {
+	if os.Getenv("BLOB_STORE_TYPE") == LocalBlobStoreType {
+		dir := os.Getenv("BLOB_STORE_PATH")
+		if dir == "" {
+			dir = defaultBlobStorePath
+		}
+		blobStore, err := storage.NewLocalBlobStore(dir)
+		if err != nil {
+			panic(err)
+		}
+		return blobStore
+	}
+
+	collection, err := mongodb.GetCollection(blobBucketName + ".files")
+	if err != nil {
+		panic(err)
+	}
+	blobStore, err := storage.NewGridFSBlobStore(collection.Database(), blobBucketName)
+	if err != nil {
+		panic(err)
+	}
+	return blobStore
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/changelog"
+	"github.com/LydiaTrack/ground/pkg/mongodb"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// A ChangelogMongoRepository that implements ChangelogRepository
+type ChangelogMongoRepository struct {
+	*repository.BaseRepository[changelog.Model]
+}
+
+// GetChangelogMongoRepository creates a new ChangelogMongoRepository instance
+func GetChangelogMongoRepository() *ChangelogMongoRepository {
+	collection, err := mongodb.GetCollection("changelog")
+	if err != nil {
+		panic(err)
+	}
+
+	return &ChangelogMongoRepository{
+		BaseRepository: repository.NewBaseRepository[changelog.Model](collection),
+	}
+}
+
+// ExistsByVersion checks if an entry other than exceptID has the version
+func (r *ChangelogMongoRepository) ExistsByVersion(version string, exceptID *primitive.ObjectID) (bool, error) {
+	filter := bson.M{"version": version}
+	if exceptID != nil {
+		filter["_id"] = bson.M{"$ne": *exceptID}
+	}
+	return r.Exists(context.Background(), filter)
+}
+
+// GetPublished retrieves the entries published until now
+func (r *ChangelogMongoRepository) GetPublished(now time.Time) ([]changelog.Model, error) {
+	result, err := r.Query(context.Background(), bson.M{"publishDate": bson.M{"$lte": now}}, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
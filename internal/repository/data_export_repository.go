@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/dataexport"
+	"github.com/LydiaTrack/ground/pkg/mongodb"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// A DataExportMongoRepository that implements DataExportRepository
+type DataExportMongoRepository struct {
+	*repository.BaseRepository[dataexport.Model]
+}
+
+// GetDataExportMongoRepository creates a new DataExportMongoRepository instance
+func GetDataExportMongoRepository() *DataExportMongoRepository {
+	collection, err := mongodb.GetCollection("data_exports")
+	if err != nil {
+		panic(err)
+	}
+
+	return &DataExportMongoRepository{
+		BaseRepository: repository.NewBaseRepository[dataexport.Model](collection),
+	}
+}
+
+// GetByTokenHash gets an export by the hash of its download token
+func (r *DataExportMongoRepository) GetByTokenHash(tokenHash string) (dataexport.Model, error) {
+	var exportModel dataexport.Model
+	err := r.Collection.FindOne(context.Background(), bson.M{"tokenHash": tokenHash}).Decode(&exportModel)
+	if err != nil {
+		return dataexport.Model{}, err
+	}
+	return exportModel, nil
+}
+
+// Complete records the blob store key of the archive of an export and marks it as ready, or as failed when
+// the key is empty
+func (r *DataExportMongoRepository) Complete(id primitive.ObjectID, archiveKey string) error {
+	set := bson.M{"status": dataexport.StatusFailed, "completedAt": time.Now()}
+	if archiveKey != "" {
+		set["status"] = dataexport.StatusReady
+		set["archiveKey"] = archiveKey
+	}
+	_, err := r.Collection.UpdateOne(context.Background(), bson.M{"_id": id},
+		bson.M{"$set": set, "$inc": bson.M{"version": 1}})
+	return err
+}
+
+// GetExpired gets the exports whose download link expired before the given time
+func (r *DataExportMongoRepository) GetExpired(before time.Time) ([]dataexport.Model, error) {
+	return r.find(bson.M{"expiresAt": bson.M{"$lte": before}})
+}
+
+// GetByUserID gets every export of a user
+func (r *DataExportMongoRepository) GetByUserID(userID primitive.ObjectID) ([]dataexport.Model, error) {
+	return r.find(bson.M{"userId": userID})
+}
+
+// DeleteExpired deletes the exports whose download link expired before the given time
+func (r *DataExportMongoRepository) DeleteExpired(before time.Time) error {
+	_, err := r.Collection.DeleteMany(context.Background(), bson.M{"expiresAt": bson.M{"$lte": before}})
+	return err
+}
+
+// DeleteByUserID deletes every export of a user
+func (r *DataExportMongoRepository) DeleteByUserID(userID primitive.ObjectID) error {
+	_, err := r.Collection.DeleteMany(context.Background(), bson.M{"userId": userID})
+	return err
+}
+
+// find gets the exports matching the filter
+func (r *DataExportMongoRepository) find(filter bson.M) ([]dataexport.Model, error) {
+	cursor, err := r.Collection.Find(context.Background(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var exports []dataexport.Model
+	if err = cursor.All(context.Background(), &exports); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/LydiaTrack/ground/pkg/domain/feedback"
 	"github.com/LydiaTrack/ground/pkg/mongodb"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/responses"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -13,6 +15,8 @@ import (
 
 type FeedbackMongoRepository struct {
 	collection *mongo.Collection
+	// base pages the feedback records
+	base *repository.BaseRepository[feedback.Model]
 }
 
 var (
@@ -27,6 +31,7 @@ func newFeedbackRepository() *FeedbackMongoRepository {
 
 	return &FeedbackMongoRepository{
 		collection: collection,
+		base:       repository.NewBaseRepository[feedback.Model](collection),
 	}
 }
 
@@ -86,6 +91,17 @@ func (r *FeedbackMongoRepository) GetFeedbacks() ([]feedback.Model, error) {
 	return feedbacks, nil
 }
 
+// QueryFeedbacksPaginated retrieves a page of the feedback records, the latest first
+func (r *FeedbackMongoRepository) QueryFeedbacksPaginated(page, limit int) (responses.PaginatedResult[feedback.Model], error) {
+	return r.base.QueryPaginate(context.Background(), nil, nil, "", page, limit, bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+}
+
+// QueryFeedbacksCursor retrieves a page of the feedback records with keyset pagination, the latest first
+func (r *FeedbackMongoRepository) QueryFeedbacksCursor(request repository.CursorRequest) (responses.CursorResult[feedback.Model], error) {
+	request.Sort = bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+	return r.base.QueryCursor(context.Background(), nil, nil, "", request)
+}
+
 // DeleteFeedback deletes a feedback record by ID
 func (r *FeedbackMongoRepository) DeleteFeedback(id primitive.ObjectID) error {
 	_, err := r.collection.DeleteOne(context.Background(), bson.M{"_id": id})
@@ -119,3 +135,9 @@ func (r *FeedbackMongoRepository) GetFeedbacksByUser(userID primitive.ObjectID)
 	}
 	return feedbacks, nil
 }
+
+// DeleteFeedbacksByUser deletes all feedback records submitted by a specific user
+func (r *FeedbackMongoRepository) DeleteFeedbacksByUser(userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(context.Background(), bson.M{"userId": userID})
+	return err
+}
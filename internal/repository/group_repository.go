@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LydiaTrack/ground/pkg/domain/group"
+	"github.com/LydiaTrack/ground/pkg/mongodb"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/responses"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// A GroupMongoRepository that implements GroupRepository.
+// Group members are stored in their own collection.
+type GroupMongoRepository struct {
+	*repository.BaseRepository[group.Model]
+	members *repository.BaseRepository[group.Member]
+}
+
+// GetGroupMongoRepository creates a new GroupMongoRepository instance
+func GetGroupMongoRepository() *GroupMongoRepository {
+	collection, err := mongodb.GetCollection("groups")
+	if err != nil {
+		panic(err)
+	}
+	memberCollection, err := mongodb.GetCollection("group_members")
+	if err != nil {
+		panic(err)
+	}
+
+	return &GroupMongoRepository{
+		BaseRepository: repository.NewBaseRepository[group.Model](collection),
+		members:        repository.NewBaseRepository[group.Member](memberCollection),
+	}
+}
+
+// ExistsByName checks if a group exists by name
+func (r *GroupMongoRepository) ExistsByName(name string) (bool, error) {
+	count, err := r.Collection.CountDocuments(context.Background(), bson.M{"name": name})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountChildGroups counts the groups that are nested in the group
+func (r *GroupMongoRepository) CountChildGroups(groupID primitive.ObjectID) (int64, error) {
+	return r.Collection.CountDocuments(context.Background(), bson.M{"parentIds": groupID})
+}
+
+// RemoveParentFromGroups removes the group from the parents of every group nested in it
+func (r *GroupMongoRepository) RemoveParentFromGroups(groupID primitive.ObjectID) error {
+	_, err := r.Collection.UpdateMany(context.Background(), bson.M{"parentIds": groupID}, bson.M{"$pull": bson.M{"parentIds": groupID}})
+	return err
+}
+
+// AddMember adds a member to a group
+func (r *GroupMongoRepository) AddMember(member group.Member) error {
+	_, err := r.members.Create(context.Background(), member)
+	return err
+}
+
+// IsMember checks if the user is a direct member of the group
+func (r *GroupMongoRepository) IsMember(groupID primitive.ObjectID, userID primitive.ObjectID) (bool, error) {
+	return r.members.Exists(context.Background(), bson.M{"groupId": groupID, "userId": userID})
+}
+
+// RemoveMember removes a member from a group
+func (r *GroupMongoRepository) RemoveMember(groupID primitive.ObjectID, userID primitive.ObjectID) error {
+	_, err := r.members.Collection.DeleteOne(context.Background(), bson.M{"groupId": groupID, "userId": userID})
+	return err
+}
+
+// RemoveMembers removes every member from a group
+func (r *GroupMongoRepository) RemoveMembers(groupID primitive.ObjectID) error {
+	_, err := r.members.Collection.DeleteMany(context.Background(), bson.M{"groupId": groupID})
+	return err
+}
+
+// RemoveMemberships removes the user from every group
+func (r *GroupMongoRepository) RemoveMemberships(userID primitive.ObjectID) error {
+	_, err := r.members.Collection.DeleteMany(context.Background(), bson.M{"userId": userID})
+	return err
+}
+
+// QueryMembersPaginated gets the direct members of a group, paginated
+func (r *GroupMongoRepository) QueryMembersPaginated(groupID primitive.ObjectID, page, limit int) (responses.PaginatedResult[group.Member], error) {
+	return r.members.QueryPaginate(context.Background(), bson.M{"groupId": groupID}, nil, "", page, limit, bson.M{"addedAt": 1})
+}
+
+// GetGroupIDsOfUser gets the IDs of the groups the user is a direct member of
+func (r *GroupMongoRepository) GetGroupIDsOfUser(userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	var members []group.Member
+	cursor, err := r.members.Collection.Find(context.Background(), bson.M{"userId": userID})
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(context.Background(), &members); err != nil {
+		return nil, err
+	}
+
+	groupIDs := make([]primitive.ObjectID, 0, len(members))
+	for _, member := range members {
+		groupIDs = append(groupIDs, member.GroupID)
+	}
+	return groupIDs, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/mongodb"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// An IdentityChangeMongoRepository that implements IdentityChangeRepository
+type IdentityChangeMongoRepository struct {
+	*repository.BaseRepository[user.IdentityChange]
+}
+
+// GetIdentityChangeMongoRepository creates a new IdentityChangeMongoRepository instance
+func GetIdentityChangeMongoRepository() *IdentityChangeMongoRepository {
+	collection, err := mongodb.GetCollection("user_identity_changes")
+	if err != nil {
+		panic(err)
+	}
+
+	return &IdentityChangeMongoRepository{
+		BaseRepository: repository.NewBaseRepository[user.IdentityChange](collection),
+	}
+}
+
+// IsReserved checks if the value of the field was released by another user than exceptUserID and is still reserved
+func (r *IdentityChangeMongoRepository) IsReserved(field user.IdentityField, value string, exceptUserID *primitive.ObjectID, now time.Time) (bool, error) {
+	filter := bson.M{"field": field, "oldValue": value, "reservedUntil": bson.M{"$gt": now}}
+	if exceptUserID != nil {
+		filter["userId"] = bson.M{"$ne": *exceptUserID}
+	}
+	count, err := r.Collection.CountDocuments(context.Background(), filter)
+	return count > 0, err
+}
+
+// GetByUserID retrieves the changes of a user, the latest first
+func (r *IdentityChangeMongoRepository) GetByUserID(userID primitive.ObjectID) ([]user.IdentityChange, error) {
+	result, err := r.QuerySorted(context.Background(), bson.M{"userId": userID}, nil, "", bson.D{{Key: "changedAt", Value: -1}})
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// DeleteByUserID deletes the changes of a user, which releases their reservations
+func (r *IdentityChangeMongoRepository) DeleteByUserID(userID primitive.ObjectID) error {
+	_, err := r.Collection.DeleteMany(context.Background(), bson.M{"userId": userID})
+	return err
+}
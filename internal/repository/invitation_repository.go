@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/invitation"
+	"github.com/LydiaTrack/ground/pkg/mongodb"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// A InvitationMongoRepository that implements InvitationRepository
+type InvitationMongoRepository struct {
+	*repository.BaseRepository[invitation.Model]
+}
+
+// GetInvitationMongoRepository creates a new InvitationMongoRepository instance
+func GetInvitationMongoRepository() *InvitationMongoRepository {
+	collection, err := mongodb.GetCollection("invitations")
+	if err != nil {
+		panic(err)
+	}
+
+	return &InvitationMongoRepository{
+		BaseRepository: repository.NewBaseRepository[invitation.Model](collection),
+	}
+}
+
+// GetByTokenHash gets an invitation by the hash of its token
+func (r *InvitationMongoRepository) GetByTokenHash(tokenHash string) (invitation.Model, error) {
+	var invitationModel invitation.Model
+	err := r.Collection.FindOne(context.Background(), bson.M{"tokenHash": tokenHash}).Decode(&invitationModel)
+	if err != nil {
+		return invitation.Model{}, err
+	}
+	return invitationModel, nil
+}
+
+// ExistsPendingByEmail checks if there is a pending invitation for the email that has not expired
+func (r *InvitationMongoRepository) ExistsPendingByEmail(email string) (bool, error) {
+	return r.Exists(context.Background(), bson.M{
+		"email":     email,
+		"status":    invitation.StatusPending,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	})
+}
+
+// UpdateStatus sets the status of a pending invitation, it returns false if the invitation is no longer pending
+func (r *InvitationMongoRepository) UpdateStatus(id primitive.ObjectID, status invitation.Status, acceptedUserID *primitive.ObjectID) (bool, error) {
+	set := bson.M{"status": status}
+	if acceptedUserID != nil {
+		set["acceptedUserId"] = acceptedUserID
+		set["acceptedAt"] = time.Now()
+	}
+	result, err := r.Collection.UpdateOne(context.Background(),
+		bson.M{"_id": id, "status": invitation.StatusPending},
+		bson.M{"$set": set, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
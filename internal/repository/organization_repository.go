@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LydiaTrack/ground/pkg/domain/organization"
+	"github.com/LydiaTrack/ground/pkg/mongodb"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// A OrganizationMongoRepository that implements OrganizationRepository
+type OrganizationMongoRepository struct {
+	*repository.BaseRepository[organization.Model]
+}
+
+// GetOrganizationMongoRepository creates a new OrganizationMongoRepository instance
+func GetOrganizationMongoRepository() *OrganizationMongoRepository {
+	collection, err := mongodb.GetCollection("organizations")
+	if err != nil {
+		panic(err)
+	}
+
+	return &OrganizationMongoRepository{
+		BaseRepository: repository.NewBaseRepository[organization.Model](collection),
+	}
+}
+
+// ExistsBySlug checks if an organization exists by slug
+func (r *OrganizationMongoRepository) ExistsBySlug(slug string) (bool, error) {
+	count, err := r.Collection.CountDocuments(context.Background(), bson.M{"slug": slug})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// A MembershipMongoRepository that implements MembershipRepository.
+// Memberships are scoped by the organization in the context.
+type MembershipMongoRepository struct {
+	*repository.BaseRepository[organization.Membership]
+}
+
+// GetMembershipMongoRepository creates a new MembershipMongoRepository instance
+func GetMembershipMongoRepository() *MembershipMongoRepository {
+	collection, err := mongodb.GetCollection("memberships")
+	if err != nil {
+		panic(err)
+	}
+
+	return &MembershipMongoRepository{
+		BaseRepository: repository.NewTenantBaseRepository[organization.Membership](collection, "organizationId"),
+	}
+}
+
+// GetMembership gets the membership of the user in the organization of the context
+func (r *MembershipMongoRepository) GetMembership(ctx context.Context, userID primitive.ObjectID) (organization.Membership, error) {
+	var membership organization.Membership
+	filter, err := r.ScopeFilter(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return organization.Membership{}, err
+	}
+	err = r.Collection.FindOne(ctx, filter).Decode(&membership)
+	if err != nil {
+		return organization.Membership{}, err
+	}
+	return membership, nil
+}
+
+// UpdateMemberRoles replaces the roles of the user in the organization of the context
+func (r *MembershipMongoRepository) UpdateMemberRoles(ctx context.Context, userID primitive.ObjectID, roleIDs []primitive.ObjectID) error {
+	filter, err := r.ScopeFilter(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return err
+	}
+	_, err = r.Collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"roleIds": roleIDs}})
+	return err
+}
+
+// DeleteMembership removes the user from the organization of the context
+func (r *MembershipMongoRepository) DeleteMembership(ctx context.Context, userID primitive.ObjectID) error {
+	filter, err := r.ScopeFilter(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return err
+	}
+	_, err = r.Collection.DeleteOne(ctx, filter)
+	return err
+}
+
+// DeleteMemberships removes every member from the organization of the context
+func (r *MembershipMongoRepository) DeleteMemberships(ctx context.Context) error {
+	filter, err := r.ScopeFilter(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	_, err = r.Collection.DeleteMany(ctx, filter)
+	return err
+}
+
+// DeleteMembershipsOfUser removes the user from the organizations of the context, every organization
+// with a global scope
+func (r *MembershipMongoRepository) DeleteMembershipsOfUser(ctx context.Context, userID primitive.ObjectID) error {
+	filter, err := r.ScopeFilter(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return err
+	}
+	_, err = r.Collection.DeleteMany(ctx, filter)
+	return err
+}
+
+// GetOrganizationIDsOfUser gets the IDs of the organizations of the context the user is a member of,
+// every organization with a global scope
+func (r *MembershipMongoRepository) GetOrganizationIDsOfUser(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	filter, err := r.ScopeFilter(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return nil, err
+	}
+
+	var memberships []organization.Membership
+	cursor, err := r.Collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(ctx, &memberships); err != nil {
+		return nil, err
+	}
+
+	organizationIDs := make([]primitive.ObjectID, 0, len(memberships))
+	for _, membership := range memberships {
+		organizationIDs = append(organizationIDs, membership.OrganizationID)
+	}
+	return organizationIDs, nil
+}
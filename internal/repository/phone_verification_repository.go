@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/phoneverification"
+	"github.com/LydiaTrack/ground/pkg/mongodb"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// A PhoneVerificationMongoRepository that implements PhoneVerificationRepository
+type PhoneVerificationMongoRepository struct {
+	*repository.BaseRepository[phoneverification.Model]
+}
+
+// GetPhoneVerificationMongoRepository creates a new PhoneVerificationMongoRepository instance
+func GetPhoneVerificationMongoRepository() *PhoneVerificationMongoRepository {
+	collection, err := mongodb.GetCollection("phone_verifications")
+	if err != nil {
+		panic(err)
+	}
+
+	return &PhoneVerificationMongoRepository{
+		BaseRepository: repository.NewBaseRepository[phoneverification.Model](collection),
+	}
+}
+
+// GetByUserID gets the verification of the phone number of a user
+func (r *PhoneVerificationMongoRepository) GetByUserID(userID primitive.ObjectID) (phoneverification.Model, error) {
+	var verification phoneverification.Model
+	err := r.Collection.FindOne(context.Background(), bson.M{"userId": userID}).Decode(&verification)
+	if err != nil {
+		return phoneverification.Model{}, err
+	}
+	return verification, nil
+}
+
+// ReserveSend counts the send of a new code to the phone number of a user and stores the hash of the code, in one
+// update that only applies if phoneverification.ResendCooldown has passed and phoneverification.MaxSendsPerWindow is
+// not reached. It returns the verification the send was counted for, mongo.ErrNoDocuments if a limit was reached.
+func (r *PhoneVerificationMongoRepository) ReserveSend(userID primitive.ObjectID, phoneNumber, codeHash string, now time.Time) (phoneverification.Model, error) {
+	// The verification is created first, with the ID of its user, so concurrent first sends cannot create two
+	created := phoneverification.NewVerification(userID, now)
+	created.ID = userID
+	_, err := r.Collection.UpdateOne(context.Background(), bson.M{"userId": userID}, bson.M{"$setOnInsert": created},
+		options.Update().SetUpsert(true))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return phoneverification.Model{}, err
+	}
+
+	windowStart := now.Add(-phoneverification.SendWindow)
+	windowExpired := bson.M{"$lte": bson.A{"$windowStart", windowStart}}
+	filter := bson.M{
+		"userId":     userID,
+		"lastSentAt": bson.M{"$lte": now.Add(-phoneverification.ResendCooldown)},
+		"$or": bson.A{
+			bson.M{"windowStart": bson.M{"$lte": windowStart}},
+			bson.M{"sentCount": bson.M{"$lt": phoneverification.MaxSendsPerWindow}},
+		},
+	}
+	update := bson.A{bson.M{"$set": bson.M{
+		"windowStart": bson.M{"$cond": bson.A{windowExpired, now, "$windowStart"}},
+		"sentCount":   bson.M{"$cond": bson.A{windowExpired, 1, bson.M{"$add": bson.A{"$sentCount", 1}}}},
+		"phoneNumber": bson.M{"$literal": phoneNumber},
+		"codeHash":    bson.M{"$literal": codeHash},
+		"attempts":    0,
+		"lastSentAt":  now,
+		"expiresAt":   now.Add(phoneverification.CodeLifetime),
+	}}, repository.VersionIncrement()}
+
+	var verification phoneverification.Model
+	err = r.Collection.FindOneAndUpdate(context.Background(), filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&verification)
+	if err != nil {
+		return phoneverification.Model{}, err
+	}
+	return verification, nil
+}
+
+// ReserveAttempt counts an attempt to enter the code of the verification and returns the verification it was
+// counted for, mongo.ErrNoDocuments if the attempts were already used up
+func (r *PhoneVerificationMongoRepository) ReserveAttempt(id primitive.ObjectID) (phoneverification.Model, error) {
+	var verification phoneverification.Model
+	err := r.Collection.FindOneAndUpdate(context.Background(),
+		bson.M{"_id": id, "attempts": bson.M{"$lt": phoneverification.MaxAttempts}},
+		bson.M{"$inc": bson.M{"attempts": 1, "version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&verification)
+	if err != nil {
+		return phoneverification.Model{}, err
+	}
+	return verification, nil
+}
+
+// DeleteByUserID deletes the verification of the phone number of a user
+func (r *PhoneVerificationMongoRepository) DeleteByUserID(userID primitive.ObjectID) error {
+	_, err := r.Collection.DeleteMany(context.Background(), bson.M{"userId": userID})
+	return err
+}
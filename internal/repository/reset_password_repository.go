@@ -74,3 +74,9 @@ func (r *ResetPasswordMongoRepository) DeleteResetPasswordByCode(code string) er
 	_, err := r.collection.DeleteOne(context.Background(), primitive.M{"code": code})
 	return err
 }
+
+// DeleteResetPasswordsByEmail deletes every resetPassword of an email
+func (r *ResetPasswordMongoRepository) DeleteResetPasswordsByEmail(email string) error {
+	_, err := r.collection.DeleteMany(context.Background(), primitive.M{"email": email})
+	return err
+}
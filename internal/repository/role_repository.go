@@ -8,11 +8,15 @@ import (
 	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // A RoleMongoRepository that implements RoleRepository
 type RoleMongoRepository struct {
 	*repository.BaseRepository[role.Model]
+	userCollection       *mongo.Collection
+	groupCollection      *mongo.Collection
+	membershipCollection *mongo.Collection
 }
 
 // GetRoleMongoRepository creates a new RoleMongoRepository instance with the given collection
@@ -23,8 +27,26 @@ func GetRoleMongoRepository() *RoleMongoRepository {
 		panic(err)
 	}
 
+	userCollection, err := mongodb.GetCollection("users")
+	if err != nil {
+		panic(err)
+	}
+	groupCollection, err := mongodb.GetCollection("groups")
+	if err != nil {
+		panic(err)
+	}
+	membershipCollection, err := mongodb.GetCollection("memberships")
+	if err != nil {
+		panic(err)
+	}
+
+	baseRepository := repository.NewBaseRepository[role.Model](collection)
+	baseRepository.Versioned = true
 	return &RoleMongoRepository{
-		BaseRepository: repository.NewBaseRepository[role.Model](collection),
+		BaseRepository:       baseRepository,
+		userCollection:       userCollection,
+		groupCollection:      groupCollection,
+		membershipCollection: membershipCollection,
 	}
 }
 
@@ -46,3 +68,55 @@ func (r *RoleMongoRepository) GetRoleByName(name string) (role.Model, error) {
 	}
 	return roleModel, nil
 }
+
+// CountRoleHolders counts the users, groups and organization memberships that hold the role, including the users
+// with only an assignment record of it
+func (r *RoleMongoRepository) CountRoleHolders(roleID primitive.ObjectID) (int64, error) {
+	userFilter := bson.M{"$or": bson.A{bson.M{"roleIds": roleID}, bson.M{"roleAssignments.roleId": roleID}}}
+	count, err := r.userCollection.CountDocuments(context.Background(), userFilter)
+	if err != nil {
+		return 0, err
+	}
+	for _, collection := range []*mongo.Collection{r.groupCollection, r.membershipCollection} {
+		holders, err := collection.CountDocuments(context.Background(), bson.M{"roleIds": roleID})
+		if err != nil {
+			return 0, err
+		}
+		count += holders
+	}
+	return count, nil
+}
+
+// RemoveRoleFromHolders removes the role and its assignment records from every user, group and organization
+// membership that holds it
+func (r *RoleMongoRepository) RemoveRoleFromHolders(roleID primitive.ObjectID) error {
+	_, err := r.userCollection.UpdateMany(context.Background(),
+		bson.M{"$or": bson.A{bson.M{"roleIds": roleID}, bson.M{"roleAssignments.roleId": roleID}}},
+		bson.M{
+			"$pull": bson.M{"roleIds": roleID, "roleAssignments": bson.M{"roleId": roleID}},
+			"$inc":  bson.M{repository.VersionField: 1},
+		})
+	if err != nil {
+		return err
+	}
+	_, err = r.groupCollection.UpdateMany(context.Background(), bson.M{"roleIds": roleID},
+		bson.M{"$pull": bson.M{"roleIds": roleID}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return err
+	}
+	_, err = r.membershipCollection.UpdateMany(context.Background(), bson.M{"roleIds": roleID},
+		bson.M{"$pull": bson.M{"roleIds": roleID}})
+	return err
+}
+
+// CountChildRoles counts the roles that inherit from the role
+func (r *RoleMongoRepository) CountChildRoles(roleID primitive.ObjectID) (int64, error) {
+	return r.Collection.CountDocuments(context.Background(), bson.M{"parentIds": roleID})
+}
+
+// RemoveParentFromRoles removes the role from the parents of every role that inherits from it
+func (r *RoleMongoRepository) RemoveParentFromRoles(roleID primitive.ObjectID) error {
+	_, err := r.Collection.UpdateMany(context.Background(), bson.M{"parentIds": roleID},
+		bson.M{"$pull": bson.M{"parentIds": roleID}, "$inc": bson.M{repository.VersionField: 1}})
+	return err
+}
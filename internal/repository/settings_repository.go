@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/settings"
+	"github.com/LydiaTrack/ground/pkg/mongodb"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// A SettingsMongoRepository that implements SettingsRepository
+type SettingsMongoRepository struct {
+	*repository.BaseRepository[settings.Model]
+}
+
+// GetSettingsMongoRepository creates a new SettingsMongoRepository instance
+func GetSettingsMongoRepository() *SettingsMongoRepository {
+	collection, err := mongodb.GetCollection("user_settings")
+	if err != nil {
+		panic(err)
+	}
+
+	return &SettingsMongoRepository{
+		BaseRepository: repository.NewBaseRepository[settings.Model](collection),
+	}
+}
+
+// GetByUserID gets the settings of a user
+func (r *SettingsMongoRepository) GetByUserID(userID primitive.ObjectID) (settings.Model, error) {
+	var settingsModel settings.Model
+	err := r.Collection.FindOne(context.Background(), bson.M{"_id": userID}).Decode(&settingsModel)
+	if err != nil {
+		return settings.Model{}, err
+	}
+	return settingsModel, nil
+}
+
+// UpdateValues sets and unsets keys of a namespace of the settings of a user, only the given keys are written.
+// The settings of the user are created if they do not exist.
+func (r *SettingsMongoRepository) UpdateValues(userID primitive.ObjectID, namespace string, set map[string]interface{}, unset []string) error {
+	now := time.Now()
+	setDoc := bson.M{"updatedDate": now}
+	for key, value := range set {
+		setDoc["namespaces."+namespace+"."+key] = value
+	}
+	update := bson.M{
+		"$set":         setDoc,
+		"$setOnInsert": bson.M{"createdDate": now},
+		"$inc":         bson.M{"version": 1},
+	}
+	if len(unset) > 0 {
+		unsetDoc := bson.M{}
+		for _, key := range unset {
+			unsetDoc["namespaces."+namespace+"."+key] = ""
+		}
+		update["$unset"] = unsetDoc
+	}
+	_, err := r.Collection.UpdateOne(context.Background(), bson.M{"_id": userID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// DeleteByUserID deletes the settings of a user
+func (r *SettingsMongoRepository) DeleteByUserID(userID primitive.ObjectID) error {
+	_, err := r.Collection.DeleteOne(context.Background(), bson.M{"_id": userID})
+	return err
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/LydiaTrack/ground/pkg/domain/role"
@@ -23,13 +25,16 @@ func GetUserMongoRepository(roleRepo *RoleMongoRepository) *UserMongoRepository
 	if err != nil {
 		panic(err)
 	}
+	baseRepository := repository.NewSoftDeleteBaseRepository[user.Model](collection)
+	baseRepository.Versioned = true
 	return &UserMongoRepository{
-		BaseRepository: repository.NewBaseRepository[user.Model](collection),
+		BaseRepository: baseRepository,
 		roleRepository: roleRepo,
 	}
 }
 
-// ExistsByUsernameAndEmail checks if a user exists by username or email
+// ExistsByUsernameAndEmail checks if a user exists by username or email.
+// The exists checks also match the deleted users, so their username and email stay reserved until they are purged.
 func (r *UserMongoRepository) ExistsByUsernameAndEmail(username string, email string) bool {
 	count, err := r.Collection.CountDocuments(context.Background(), bson.M{"$or": []bson.M{{"username": username}, {"contactInfo.email": email}}})
 	if err != nil {
@@ -53,29 +58,79 @@ func (r *UserMongoRepository) ExistsByEmail(email string) bool {
 // GetByUsername retrieves a user by username
 func (r *UserMongoRepository) GetByUsername(username string) (user.Model, error) {
 	var userModel user.Model
-	err := r.Collection.FindOne(context.Background(), bson.M{"username": username}).Decode(&userModel)
+	err := r.Collection.FindOne(context.Background(), bson.M{"username": username, repository.DeletedAtField: bson.M{"$exists": false}}).Decode(&userModel)
 	return userModel, err
 }
 
 // GetByEmail retrieves a user by email
 func (r *UserMongoRepository) GetByEmail(email string) (user.Model, error) {
 	var userModel user.Model
-	err := r.Collection.FindOne(context.Background(), bson.M{"contactInfo.email": email}).Decode(&userModel)
+	err := r.Collection.FindOne(context.Background(), bson.M{"contactInfo.email": email, repository.DeletedAtField: bson.M{"$exists": false}}).Decode(&userModel)
 	return userModel, err
 }
 
-// AddRole adds a role to a user
-func (r *UserMongoRepository) AddRole(userID, roleID primitive.ObjectID) error {
-	_, err := r.Collection.UpdateOne(context.Background(), bson.M{"_id": userID}, bson.M{"$addToSet": bson.M{"roleIds": roleID}})
+// AddRole adds a role to a user, replacing the previous assignment of the same role in the same update
+func (r *UserMongoRepository) AddRole(userID primitive.ObjectID, assignment user.RoleAssignment) error {
+	roleIDs := bson.M{"$ifNull": bson.A{"$roleIds", bson.A{}}}
+	otherAssignments := bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$roleAssignments", bson.A{}}},
+		"cond":  bson.M{"$ne": bson.A{"$$this.roleId", assignment.RoleID}},
+	}}
+	update := bson.A{bson.M{"$set": bson.M{
+		"roleIds": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{assignment.RoleID, roleIDs}},
+			roleIDs,
+			bson.M{"$concatArrays": bson.A{roleIDs, bson.A{assignment.RoleID}}},
+		}},
+		// The assignment is a literal, so a reason starting with $ is not read as a field path
+		"roleAssignments": bson.M{"$concatArrays": bson.A{otherAssignments, bson.A{bson.M{"$literal": assignment}}}},
+	}}, repository.VersionIncrement()}
+	_, err := r.Collection.UpdateOne(context.Background(), bson.M{"_id": userID}, update)
 	return err
 }
 
 // RemoveRole removes a role from a user
 func (r *UserMongoRepository) RemoveRole(userID, roleID primitive.ObjectID) error {
-	_, err := r.Collection.UpdateOne(context.Background(), bson.M{"_id": userID}, bson.M{"$pull": bson.M{"roleIds": roleID}})
+	_, err := r.UpdateOne(context.Background(), userID, bson.M{"$pull": bson.M{
+		"roleIds":         roleID,
+		"roleAssignments": bson.M{"roleId": roleID},
+	}})
 	return err
 }
 
+// RemoveExpiredRoleAssignments removes the roles whose assignment expired before now from every user, it returns the
+// number of users whose roles were removed. Each user is updated at once and only loses the roles of the assignments
+// removed in the same update, so a role granted again concurrently is kept.
+func (r *UserMongoRepository) RemoveExpiredRoleAssignments(now time.Time) (int64, error) {
+	isExpired := bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": "$$this.expiresAt"}, "date"}},
+		bson.M{"$lte": bson.A{"$$this.expiresAt", now}},
+	}}
+	update := bson.A{
+		bson.M{"$set": bson.M{"expiredRoleIds": bson.M{"$map": bson.M{
+			"input": bson.M{"$filter": bson.M{"input": "$roleAssignments", "cond": isExpired}},
+			"in":    "$$this.roleId",
+		}}}},
+		bson.M{"$set": bson.M{
+			"roleIds": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$roleIds", bson.A{}}},
+				"cond":  bson.M{"$not": bson.A{bson.M{"$in": bson.A{"$$this", "$expiredRoleIds"}}}},
+			}},
+			"roleAssignments": bson.M{"$filter": bson.M{
+				"input": "$roleAssignments",
+				"cond":  bson.M{"$not": bson.A{isExpired}},
+			}},
+		}},
+		bson.M{"$unset": "expiredRoleIds"},
+		repository.VersionIncrement(),
+	}
+	result, err := r.Collection.UpdateMany(context.Background(), bson.M{"roleAssignments.expiresAt": bson.M{"$lte": now}}, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
 // GetUserRoles retrieves roles for a user
 func (r *UserMongoRepository) GetUserRoles(roleIds []primitive.ObjectID) (responses.QueryResult[role.Model], error) {
 	var roles []role.Model
@@ -103,6 +158,64 @@ func (r *UserMongoRepository) GetUserRoles(roleIds []primitive.ObjectID) (respon
 
 // UpdateUserPassword updates a user's password
 func (r *UserMongoRepository) UpdateUserPassword(userID primitive.ObjectID, password string) error {
-	_, err := r.Collection.UpdateOne(context.Background(), bson.M{"_id": userID}, bson.M{"$set": bson.M{"password": password}})
+	_, err := r.UpdateOne(context.Background(), userID, bson.M{"$set": bson.M{"password": password}})
+	return err
+}
+
+// UpdateStatus sets the account status of the user
+func (r *UserMongoRepository) UpdateStatus(userID primitive.ObjectID, status user.AccountStatus) error {
+	_, err := r.UpdateOne(context.Background(), userID, bson.M{"$set": bson.M{"status": status}})
+	return err
+}
+
+// GetDeletedBefore retrieves the users that were marked as deleted before the given time
+func (r *UserMongoRepository) GetDeletedBefore(before time.Time) ([]user.Model, error) {
+	cursor, err := r.Collection.Find(context.Background(), bson.M{repository.DeletedAtField: bson.M{"$lte": before}})
+	if err != nil {
+		return nil, err
+	}
+	var users []user.Model
+	if err := cursor.All(context.Background(), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// SetDeletionSchedule sets the time the user is deleted at, nil cancels the scheduled deletion
+func (r *UserMongoRepository) SetDeletionSchedule(userID primitive.ObjectID, scheduledAt *time.Time) error {
+	update := bson.M{"$unset": bson.M{"deletionScheduledAt": ""}}
+	if scheduledAt != nil {
+		update = bson.M{"$set": bson.M{"deletionScheduledAt": scheduledAt}}
+	}
+	_, err := r.UpdateOne(context.Background(), userID, update)
 	return err
 }
+
+// ReplaceInlineAvatar points the user to the stored avatar if the user still has the given inline avatar, an empty
+// avatar ID removes it. It reports whether the avatar was replaced, so concurrent migrations and uploads are not
+// overwritten.
+func (r *UserMongoRepository) ReplaceInlineAvatar(userID primitive.ObjectID, inlineAvatar string, avatar string, avatarID string) (bool, error) {
+	update := bson.M{"$unset": bson.M{"avatar": ""}}
+	if avatarID != "" {
+		update = bson.M{"$set": bson.M{"avatar": avatar, "avatarId": avatarID}}
+	}
+	update["$inc"] = bson.M{repository.VersionField: 1}
+	result, err := r.Collection.UpdateOne(context.Background(), bson.M{"_id": userID, "avatar": inlineAvatar}, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// GetDeletionsScheduledBefore retrieves the users whose deletion is scheduled before the given time
+func (r *UserMongoRepository) GetDeletionsScheduledBefore(before time.Time) ([]user.Model, error) {
+	cursor, err := r.Collection.Find(context.Background(), bson.M{"deletionScheduledAt": bson.M{"$lte": before}})
+	if err != nil {
+		return nil, err
+	}
+	var users []user.Model
+	if err := cursor.All(context.Background(), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
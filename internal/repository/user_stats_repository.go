@@ -162,3 +162,9 @@ func (r *UserStatsMongoRepository) UpdateFields(statsID primitive.ObjectID, fiel
 	)
 	return err
 }
+
+// DeleteStatsByUserID deletes the stats of a user
+func (r *UserStatsMongoRepository) DeleteStatsByUserID(userID primitive.ObjectID) error {
+	_, err := r.Collection.DeleteMany(context.Background(), bson.M{"userId": userID})
+	return err
+}
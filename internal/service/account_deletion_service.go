@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// defaultSelfDeletionCoolingOffInHour is the default period a self-service deletion can be cancelled in by logging in
+const defaultSelfDeletionCoolingOffInHour = 168
+
+// OAuthReauthenticator re-authenticates the users signed up with an OAuth provider
+type OAuthReauthenticator interface {
+	// ReauthenticateOAuth validates a fresh token of the OAuth provider of the user
+	ReauthenticateOAuth(userModel user.Model, token string) error
+}
+
+// AccountDeletionService lets users delete their own accounts
+type AccountDeletionService struct {
+	userService          UserService
+	sessionService       SessionService
+	oauthReauthenticator OAuthReauthenticator
+}
+
+func NewAccountDeletionService(userService UserService, sessionService SessionService, oauthReauthenticator OAuthReauthenticator) *AccountDeletionService {
+	return &AccountDeletionService{
+		userService:          userService,
+		sessionService:       sessionService,
+		oauthReauthenticator: oauthReauthenticator,
+	}
+}
+
+// DeleteSelf schedules the deletion of the own account after the cooling-off period and revokes its session.
+// Logging in during the cooling-off period cancels the deletion, after it the user is purged with its data.
+func (s AccountDeletionService) DeleteSelf(command user.DeleteSelfCommand, authContext auth.PermissionContext) (time.Time, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfDeletePermission) != nil {
+		return time.Time{}, constants.ErrorPermissionDenied
+	}
+
+	userModel, err := s.userService.userRepository.GetByID(context.Background(), *authContext.UserID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return time.Time{}, constants.ErrorInternalServerError
+	}
+
+	if err = s.reauthenticate(userModel, command); err != nil {
+		return time.Time{}, err
+	}
+
+	scheduledAt := time.Now().Add(GetSelfDeletionCoolingOffPeriod())
+	if err = s.userService.userRepository.SetDeletionSchedule(userModel.ID, &scheduledAt); err != nil {
+		return time.Time{}, constants.ErrorInternalServerError
+	}
+
+	if err = s.sessionService.DeleteSessionByUser(userModel.ID.Hex()); err != nil {
+		log.LogError("Failed to revoke the session of user %s: %v", userModel.ID.Hex(), err)
+	}
+
+	return scheduledAt, nil
+}
+
+// reauthenticate checks the password of the user, users signed up with an OAuth provider have no password
+// and re-authenticate with a fresh token of the provider instead
+func (s AccountDeletionService) reauthenticate(userModel user.Model, command user.DeleteSelfCommand) error {
+	if userModel.Password != "" {
+		if bcrypt.CompareHashAndPassword([]byte(userModel.Password), []byte(command.Password)) != nil {
+			return constants.ErrorUnauthorized
+		}
+		return nil
+	}
+
+	if userModel.OAuthInfo == nil || s.oauthReauthenticator == nil {
+		return constants.ErrorUnauthorized
+	}
+	if s.oauthReauthenticator.ReauthenticateOAuth(userModel, command.OAuthToken) != nil {
+		return constants.ErrorUnauthorized
+	}
+	return nil
+}
+
+// GetSelfDeletionCoolingOffPeriod reads the period a self-service deletion can be cancelled in
+// from USER_SELF_DELETION_COOLING_OFF_IN_HOUR
+func GetSelfDeletionCoolingOffPeriod() time.Duration {
+	coolingOffInHour, err := strconv.Atoi(os.Getenv("USER_SELF_DELETION_COOLING_OFF_IN_HOUR"))
+	if err != nil || coolingOffInHour < 0 {
+		coolingOffInHour = defaultSelfDeletionCoolingOffInHour
+	}
+	return time.Duration(coolingOffInHour) * time.Hour
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AccountStatusService suspends, bans, deactivates and reactivates accounts
+type AccountStatusService struct {
+	userService    UserService
+	sessionService SessionService
+}
+
+func NewAccountStatusService(userService UserService, sessionService SessionService) *AccountStatusService {
+	return &AccountStatusService{
+		userService:    userService,
+		sessionService: sessionService,
+	}
+}
+
+// ChangeStatus changes the state of an account. The sessions of accounts that are no longer active are revoked,
+// so their refresh tokens stop working immediately.
+func (s AccountStatusService) ChangeStatus(id string, command user.ChangeStatusCommand, authContext auth.PermissionContext) (user.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserStatusUpdatePermission) != nil {
+		return user.Model{}, constants.ErrorPermissionDenied
+	}
+
+	if err := command.Validate(); err != nil {
+		return user.Model{}, constants.ErrorBadRequest
+	}
+
+	userID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return user.Model{}, constants.ErrorBadRequest
+	}
+
+	// Users cannot lock themselves out
+	if authContext.UserID != nil && *authContext.UserID == userID && command.State != user.AccountStateActive {
+		return user.Model{}, constants.ErrorBadRequest
+	}
+
+	_, err = s.userService.userRepository.GetByID(context.Background(), userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+
+	status := user.AccountStatus{
+		State:     command.State,
+		Until:     command.Until,
+		Reason:    command.Reason,
+		ChangedBy: authContext.UserID,
+		ChangedAt: time.Now(),
+	}
+	if err = s.userService.userRepository.UpdateStatus(userID, status); err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+
+	if command.State != user.AccountStateActive {
+		if err = s.sessionService.DeleteSessionByUser(userID.Hex()); err != nil {
+			log.LogError("Failed to revoke the sessions of user %s: %v", userID.Hex(), err)
+			return user.Model{}, constants.ErrorInternalServerError
+		}
+	}
+
+	return s.userService.userRepository.GetByID(context.Background(), userID)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
 	"github.com/LydiaTrack/ground/pkg/responses"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/LydiaTrack/ground/pkg/auth"
 	"github.com/LydiaTrack/ground/pkg/constants"
 	"github.com/LydiaTrack/ground/pkg/domain/audit"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -113,6 +115,23 @@ func (s AuditService) QueryPaginated(searchText string, page, limit int, authCon
 	return result, nil
 }
 
+// QueryCursor retrieves the audits in the order of their instants with keyset pagination after permission validation.
+func (s AuditService) QueryCursor(searchText string, request repository.CursorRequest, authContext auth.PermissionContext) (responses.CursorResult[audit.Model], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.AuditReadPermission) != nil {
+		return responses.CursorResult[audit.Model]{}, constants.ErrorPermissionDenied
+	}
+
+	request.Sort = bson.D{{Key: "instant", Value: 1}}
+	result, err := s.auditRepository.QueryCursor(context.Background(), nil, auditSearchFields, searchText, request)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return responses.CursorResult[audit.Model]{}, cursorError(err)
+		}
+		return responses.CursorResult[audit.Model]{}, constants.ErrorInternalServerError
+	}
+	return result, nil
+}
+
 // DeleteOlderThan deletes audits older than a given date after permission validation.
 func (s AuditService) DeleteOlderThan(command audit.DeleteOlderThanAuditCommand, authContext auth.PermissionContext) error {
 	if auth.CheckPermission(authContext.Permissions, permissions.AuditDeletePermission) != nil {
@@ -142,3 +161,16 @@ func (s AuditService) DeleteInterval(command audit.DeleteIntervalAuditCommand, a
 	err = s.auditRepository.DeleteInterval(context.Background(), command.From, command.To)
 	return err
 }
+
+// ExportUserData exports the audit records the user performed or is the subject of for the data export
+func (s AuditService) ExportUserData(userID primitive.ObjectID) (interface{}, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"relatedPrincipal": userID.Hex()},
+		{"additionalData.userId": userID.Hex()},
+	}}
+	result, err := s.auditRepository.Query(context.Background(), filter, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/utils"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AvatarService stores the uploaded avatars of the users in a blob store, resized to user.AvatarSizes. The users
+// only keep the URL the avatar is served from.
+type AvatarService struct {
+	userService UserService
+	blobStore   storage.BlobStore
+}
+
+func NewAvatarService(userService UserService, blobStore storage.BlobStore) *AvatarService {
+	return &AvatarService{
+		userService: userService,
+		blobStore:   blobStore,
+	}
+}
+
+// UploadSelf replaces the avatar of the current user with the uploaded image
+func (s AvatarService) UploadSelf(data []byte, authContext auth.PermissionContext) (user.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfUpdatePermission) != nil {
+		return user.Model{}, constants.ErrorPermissionDenied
+	}
+	userModel, err := s.upload(*authContext.UserID, data)
+	if err != nil {
+		return user.Model{}, err
+	}
+	return s.userService.SelfView(userModel), nil
+}
+
+// Upload replaces the avatar of a user with the uploaded image
+func (s AvatarService) Upload(userID string, data []byte, authContext auth.PermissionContext) (user.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserUpdatePermission) != nil {
+		return user.Model{}, constants.ErrorPermissionDenied
+	}
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return user.Model{}, constants.ErrorBadRequest
+	}
+	return s.upload(objID, data)
+}
+
+// RemoveSelf removes the avatar of the current user
+func (s AvatarService) RemoveSelf(authContext auth.PermissionContext) (user.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfUpdatePermission) != nil {
+		return user.Model{}, constants.ErrorPermissionDenied
+	}
+	userModel, err := s.remove(*authContext.UserID)
+	if err != nil {
+		return user.Model{}, err
+	}
+	return s.userService.SelfView(userModel), nil
+}
+
+// Remove removes the avatar of a user
+func (s AvatarService) Remove(userID string, authContext auth.PermissionContext) (user.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserUpdatePermission) != nil {
+		return user.Model{}, constants.ErrorPermissionDenied
+	}
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return user.Model{}, constants.ErrorBadRequest
+	}
+	return s.remove(objID)
+}
+
+// Get retrieves the stored size of an avatar closest to the requested one, the largest size if it is zero.
+// Avatars are served to anyone who has their URL.
+func (s AvatarService) Get(avatarID string, size int) (storage.Blob, error) {
+	if _, err := primitive.ObjectIDFromHex(avatarID); err != nil {
+		return storage.Blob{}, constants.ErrorNotFound
+	}
+	blob, err := s.blobStore.Get(context.Background(), user.AvatarKey(avatarID, user.AvatarSize(size)))
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return storage.Blob{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return storage.Blob{}, constants.ErrorInternalServerError
+	}
+	return blob, nil
+}
+
+// MigrateInlineAvatars moves the base64 avatars stored in the user documents to the blob store. Avatars that cannot
+// be decoded are removed, as they cannot be displayed either. Users are only updated if their avatar is still the
+// migrated one, so the replicas can run the migration concurrently.
+func (s AvatarService) MigrateInlineAvatars() error {
+	ctx := repository.IncludeDeleted(context.Background())
+	filter := bson.M{"avatar": bson.M{"$regex": "^data:"}}
+	migrated, removed := 0, 0
+	err := s.userService.userRepository.ForEach(ctx, filter, func(userModel user.Model) error {
+		if !utils.IsInlineAvatar(userModel.Avatar) {
+			return nil
+		}
+		data, err := utils.DecodeInlineAvatar(userModel.Avatar)
+		var img image.Image
+		if err == nil {
+			img, err = utils.DecodeImage(data, user.MaxAvatarDimension)
+		}
+		if err != nil {
+			replaced, updateErr := s.userService.userRepository.ReplaceInlineAvatar(userModel.ID, userModel.Avatar, "", "")
+			if updateErr != nil {
+				return updateErr
+			}
+			if replaced {
+				log.LogWarning(fmt.Sprintf("Removed the inline avatar of user %s that cannot be migrated: %v", userModel.ID.Hex(), err))
+				removed++
+			}
+			return nil
+		}
+
+		avatarID, err := s.storeAvatar(img)
+		if err != nil {
+			return err
+		}
+		replaced, err := s.userService.userRepository.ReplaceInlineAvatar(userModel.ID, userModel.Avatar, utils.AvatarURL(avatarID), avatarID)
+		if err != nil || !replaced {
+			// The avatar was changed or migrated by someone else in the meantime
+			s.deleteAvatar(avatarID)
+			return err
+		}
+		migrated++
+		return nil
+	})
+	if migrated > 0 || removed > 0 {
+		log.Log("Migrated %d inline avatars to the blob store, removed %d invalid ones", migrated, removed)
+	}
+	return err
+}
+
+// CleanupUser deletes the avatar of a purged user
+func (s AvatarService) CleanupUser(userID primitive.ObjectID) error {
+	userModel, err := s.userService.userRepository.GetByID(repository.IncludeDeleted(context.Background()), userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if userModel.AvatarID != "" {
+		return s.deleteAvatarSizes(userModel.AvatarID)
+	}
+	return nil
+}
+
+// upload stores the sizes of the uploaded image, points the user to them and deletes the previous avatar
+func (s AvatarService) upload(userID primitive.ObjectID, data []byte) (user.Model, error) {
+	currentUser, err := s.userService.userRepository.GetByID(context.Background(), userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+
+	img, err := utils.DecodeImage(data, user.MaxAvatarDimension)
+	if err != nil {
+		return user.Model{}, fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+	avatarID, err := s.storeAvatar(img)
+	if err != nil {
+		log.LogError("Failed to store the avatar of user %s: %v", userID.Hex(), err)
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+
+	set := bson.M{"avatar": utils.AvatarURL(avatarID), "avatarId": avatarID}
+	if _, err = s.userService.userRepository.UpdateFields(context.Background(), userID, set, nil); err != nil {
+		s.deleteAvatar(avatarID)
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	s.deleteAvatar(currentUser.AvatarID)
+
+	updatedUser, err := s.userService.userRepository.GetByID(context.Background(), userID)
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	return updatedUser, nil
+}
+
+// remove unsets the avatar of the user and deletes its sizes
+func (s AvatarService) remove(userID primitive.ObjectID) (user.Model, error) {
+	currentUser, err := s.userService.userRepository.GetByID(context.Background(), userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	if currentUser.Avatar == "" && currentUser.AvatarID == "" {
+		return currentUser, nil
+	}
+
+	if _, err = s.userService.userRepository.UpdateFields(context.Background(), userID, nil, []string{"avatar", "avatarId"}); err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	s.deleteAvatar(currentUser.AvatarID)
+
+	updatedUser, err := s.userService.userRepository.GetByID(context.Background(), userID)
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	return updatedUser, nil
+}
+
+// storeAvatar resizes the image to every avatar size and stores them under a new avatar ID
+func (s AvatarService) storeAvatar(img image.Image) (string, error) {
+	avatarID := primitive.NewObjectID().Hex()
+	for _, size := range user.AvatarSizes {
+		var buffer bytes.Buffer
+		if err := png.Encode(&buffer, utils.SquareThumbnail(img, size)); err != nil {
+			s.deleteAvatar(avatarID)
+			return "", err
+		}
+		blob := storage.Blob{ContentType: user.AvatarContentType, Data: buffer.Bytes()}
+		if err := s.blobStore.Put(context.Background(), user.AvatarKey(avatarID, size), blob); err != nil {
+			s.deleteAvatar(avatarID)
+			return "", err
+		}
+	}
+	return avatarID, nil
+}
+
+// deleteAvatar deletes the sizes of an avatar that is no longer referenced, failures only leave unused blobs behind
+func (s AvatarService) deleteAvatar(avatarID string) {
+	if avatarID == "" {
+		return
+	}
+	if err := s.deleteAvatarSizes(avatarID); err != nil {
+		log.LogError("Failed to delete avatar %s: %v", avatarID, err)
+	}
+}
+
+// deleteAvatarSizes deletes every size of an avatar
+func (s AvatarService) deleteAvatarSizes(avatarID string) error {
+	return deleteAvatarSizes(s.blobStore, avatarID)
+}
+
+// deleteAvatarSizes deletes every size of an avatar from the blob store
+func deleteAvatarSizes(blobStore storage.BlobStore, avatarID string) error {
+	for _, size := range user.AvatarSizes {
+		if err := blobStore.Delete(context.Background(), user.AvatarKey(avatarID, size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// avatarFields returns the fields to write with the new avatar of a user. An uploaded avatar is written with its
+// ID, a link or an empty avatar removes the ID of the previous one.
+func (s UserService) avatarFields(userID primitive.ObjectID, avatar string) (bson.M, []string, error) {
+	avatarID, err := s.uploadedAvatarID(userID, avatar)
+	if err != nil {
+		return nil, nil, err
+	}
+	if avatarID == "" {
+		return nil, []string{"avatarId"}, nil
+	}
+	return bson.M{"avatarId": avatarID}, nil, nil
+}
+
+// uploadedAvatarID returns the ID of the avatar of a user if it is uploaded, empty if it is a link. An uploaded
+// avatar must be stored and must not be the avatar of another user, whose avatar would be deleted with this one.
+func (s UserService) uploadedAvatarID(userID primitive.ObjectID, avatar string) (string, error) {
+	avatarID, uploaded := utils.UploadedAvatarID(avatar)
+	if !uploaded {
+		return "", nil
+	}
+	if s.blobStore == nil {
+		return "", fmt.Errorf("%w: avatars cannot be uploaded", constants.ErrorBadRequest)
+	}
+	_, err := s.blobStore.Get(context.Background(), user.AvatarKey(avatarID, user.AvatarSizes[0]))
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return "", fmt.Errorf("%w: avatar %s does not exist", constants.ErrorBadRequest, avatarID)
+	}
+	if err != nil {
+		return "", constants.ErrorInternalServerError
+	}
+
+	owners, err := s.userRepository.Query(repository.IncludeDeleted(context.Background()), bson.M{"avatarId": avatarID}, nil, "")
+	if err != nil {
+		return "", constants.ErrorInternalServerError
+	}
+	for _, owner := range owners.Data {
+		if owner.ID != userID {
+			return "", fmt.Errorf("%w: avatar %s belongs to another user", constants.ErrorBadRequest, avatarID)
+		}
+	}
+	return avatarID, nil
+}
+
+// releaseAvatar deletes the previous uploaded avatar of an updated user, failures only leave unused blobs behind
+func (s UserService) releaseAvatar(before, after user.Model) {
+	if before.AvatarID == "" || before.AvatarID == after.AvatarID || s.blobStore == nil {
+		return
+	}
+	if err := deleteAvatarSizes(s.blobStore, before.AvatarID); err != nil {
+		log.LogError("Failed to delete avatar %s: %v", before.AvatarID, err)
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/changelog"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/responses"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var changelogSearchFields = []string{"version", "title"}
+
+// ChangelogService manages the release notes of the host application. The users see the entries newer than their
+// user.Model.LastSeenChangelogVersion, ordered by semantic version.
+type ChangelogService struct {
+	changelogRepository ChangelogRepository
+	userService         UserService
+	roleService         RoleService
+}
+
+func NewChangelogService(changelogRepository ChangelogRepository, userService UserService, roleService RoleService) *ChangelogService {
+	return &ChangelogService{
+		changelogRepository: changelogRepository,
+		userService:         userService,
+		roleService:         roleService,
+	}
+}
+
+type ChangelogRepository interface {
+	repository.Repository[changelog.Model]
+	// UpdateFields sets and unsets the fields of an entry
+	UpdateFields(ctx context.Context, id interface{}, set bson.M, unset []string) (*mongo.UpdateResult, error)
+	// ExistsByVersion checks if an entry other than exceptID has the version
+	ExistsByVersion(version string, exceptID *primitive.ObjectID) (bool, error)
+	// GetPublished retrieves the entries published until now
+	GetPublished(now time.Time) ([]changelog.Model, error)
+}
+
+// Create creates a changelog entry, it is published now if no publish date is given
+func (s ChangelogService) Create(command changelog.CreateChangelogEntryCommand, authContext auth.PermissionContext) (changelog.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.ChangelogCreatePermission) != nil {
+		return changelog.Model{}, constants.ErrorPermissionDenied
+	}
+	if err := command.Validate(); err != nil {
+		return changelog.Model{}, fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+	command.Version = changelog.CanonicalVersion(command.Version)
+	if err := s.checkEntry(command.Version, command.AudienceRoleIDs, nil); err != nil {
+		return changelog.Model{}, err
+	}
+
+	entry := changelog.NewEntry(command, authContext.UserID, time.Now())
+	if _, err := s.changelogRepository.Create(context.Background(), entry); err != nil {
+		return changelog.Model{}, constants.ErrorInternalServerError
+	}
+	return entry, nil
+}
+
+// Get gets a changelog entry by ID, including unpublished ones
+func (s ChangelogService) Get(id string, authContext auth.PermissionContext) (changelog.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.ChangelogReadPermission) != nil {
+		return changelog.Model{}, constants.ErrorPermissionDenied
+	}
+
+	entryID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return changelog.Model{}, constants.ErrorBadRequest
+	}
+	return s.getEntry(entryID)
+}
+
+// QueryPaginated queries the changelog entries including unpublished ones, the latest published first
+func (s ChangelogService) QueryPaginated(searchText string, page, limit int, authContext auth.PermissionContext) (responses.PaginatedResult[changelog.Model], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.ChangelogReadPermission) != nil {
+		return responses.PaginatedResult[changelog.Model]{}, constants.ErrorPermissionDenied
+	}
+
+	return s.changelogRepository.QueryPaginate(context.Background(), bson.M{}, changelogSearchFields, searchText, page, limit, bson.M{"publishDate": -1})
+}
+
+// Update replaces the content, publish date and audience of a changelog entry
+func (s ChangelogService) Update(id string, command changelog.UpdateChangelogEntryCommand, authContext auth.PermissionContext) (changelog.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.ChangelogUpdatePermission) != nil {
+		return changelog.Model{}, constants.ErrorPermissionDenied
+	}
+
+	entryID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return changelog.Model{}, constants.ErrorBadRequest
+	}
+	if _, err := s.getEntry(entryID); err != nil {
+		return changelog.Model{}, err
+	}
+	if err := command.Validate(); err != nil {
+		return changelog.Model{}, fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+	command.Version = changelog.CanonicalVersion(command.Version)
+	if command.AudienceRoleIDs == nil {
+		command.AudienceRoleIDs = []primitive.ObjectID{}
+	}
+	if err := s.checkEntry(command.Version, command.AudienceRoleIDs, &entryID); err != nil {
+		return changelog.Model{}, err
+	}
+
+	set := bson.M{
+		"version":         command.Version,
+		"title":           command.Title,
+		"body":            command.Body,
+		"publishDate":     command.PublishDate,
+		"audienceRoleIds": command.AudienceRoleIDs,
+		"updatedDate":     time.Now(),
+	}
+	if _, err = s.changelogRepository.UpdateFields(context.Background(), entryID, set, nil); err != nil {
+		return changelog.Model{}, constants.ErrorInternalServerError
+	}
+	return s.getEntry(entryID)
+}
+
+// Delete deletes a changelog entry
+func (s ChangelogService) Delete(id string, authContext auth.PermissionContext) error {
+	if auth.CheckPermission(authContext.Permissions, permissions.ChangelogDeletePermission) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	entryID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return constants.ErrorBadRequest
+	}
+	if _, err := s.getEntry(entryID); err != nil {
+		return err
+	}
+	if _, err = s.changelogRepository.Delete(context.Background(), entryID); err != nil {
+		return constants.ErrorInternalServerError
+	}
+	return nil
+}
+
+// GetUnseen retrieves the published entries the current user has not seen, the newest version first
+func (s ChangelogService) GetUnseen(authContext auth.PermissionContext) ([]changelog.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfGetPermission) != nil {
+		return nil, constants.ErrorPermissionDenied
+	}
+
+	userModel, roleIDs, err := s.getUserWithRoles(*authContext.UserID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	entries, err := s.changelogRepository.GetPublished(now)
+	if err != nil {
+		return nil, constants.ErrorInternalServerError
+	}
+	return changelog.Unseen(entries, userModel.LastSeenChangelogVersion, roleIDs, now), nil
+}
+
+// MarkSeen advances the last seen changelog version of the current user to the version of the command, or to the
+// newest entry the user can see. It never moves back.
+func (s ChangelogService) MarkSeen(command changelog.MarkSeenCommand, authContext auth.PermissionContext) (user.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfUpdatePermission) != nil {
+		return user.Model{}, constants.ErrorPermissionDenied
+	}
+	if err := command.Validate(); err != nil {
+		return user.Model{}, fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+
+	userModel, roleIDs, err := s.getUserWithRoles(*authContext.UserID)
+	if err != nil {
+		return user.Model{}, err
+	}
+	seenVersion := changelog.CanonicalVersion(command.Version)
+	if seenVersion == "" {
+		now := time.Now()
+		entries, err := s.changelogRepository.GetPublished(now)
+		if err != nil {
+			return user.Model{}, constants.ErrorInternalServerError
+		}
+		visible := changelog.Unseen(entries, "", roleIDs, now)
+		if len(visible) == 0 {
+			return s.userService.SelfView(userModel), nil
+		}
+		seenVersion = visible[0].Version
+	}
+
+	seen, _ := changelog.ParseVersion(seenVersion)
+	if lastSeen, err := changelog.ParseVersion(userModel.LastSeenChangelogVersion); err == nil && seen.Compare(lastSeen) <= 0 {
+		return s.userService.SelfView(userModel), nil
+	}
+	set := bson.M{"lastSeenChangelogVersion": seenVersion}
+	if _, err = s.userService.userRepository.UpdateFields(context.Background(), userModel.ID, set, nil); err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+
+	updatedUser, err := s.userService.userRepository.GetByID(context.Background(), userModel.ID)
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	return s.userService.SelfView(updatedUser), nil
+}
+
+func (s ChangelogService) getEntry(entryID primitive.ObjectID) (changelog.Model, error) {
+	entry, err := s.changelogRepository.GetByID(context.Background(), entryID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return changelog.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return changelog.Model{}, constants.ErrorInternalServerError
+	}
+	return entry, nil
+}
+
+// checkEntry checks that no other entry has the version and that the audience roles exist
+func (s ChangelogService) checkEntry(version string, audienceRoleIDs []primitive.ObjectID, exceptID *primitive.ObjectID) error {
+	exists, err := s.changelogRepository.ExistsByVersion(version, exceptID)
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+	if exists {
+		return changelog.ErrVersionExists
+	}
+	for _, roleID := range audienceRoleIDs {
+		exists, err := s.roleService.Exists(roleID.Hex(), auth.CreateAdminAuthContext())
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: audience role %s does not exist", constants.ErrorBadRequest, roleID.Hex())
+		}
+	}
+	return nil
+}
+
+// getUserWithRoles gets a user with the IDs of its active roles, including the provided ones
+func (s ChangelogService) getUserWithRoles(userID primitive.ObjectID) (user.Model, []primitive.ObjectID, error) {
+	userModel, err := s.userService.userRepository.GetByID(context.Background(), userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.Model{}, nil, constants.ErrorNotFound
+	}
+	if err != nil {
+		return user.Model{}, nil, constants.ErrorInternalServerError
+	}
+	roleIDs, err := s.userService.GetRoleIDs(userModel)
+	if err != nil {
+		return user.Model{}, nil, err
+	}
+	return userModel, roleIDs, nil
+}
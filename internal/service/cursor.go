@@ -0,0 +1,17 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+)
+
+// cursorError reports invalid cursors as bad requests
+func cursorError(err error) error {
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+	return err
+}
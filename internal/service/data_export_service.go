@@ -0,0 +1,351 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/dataexport"
+	"github.com/LydiaTrack/ground/pkg/domain/email"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/registry"
+	"github.com/LydiaTrack/ground/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	defaultDataExportExpiresInHour = 48
+	defaultDataExportWorkers       = 2
+	// dataExportQueueSize is the number of exports waiting for a worker, further requests are refused
+	dataExportQueueSize = 100
+)
+
+type DataExportService struct {
+	dataExportRepository DataExportRepository
+	userService          UserService
+	emailService         SimpleEmailService
+	blobStore            storage.BlobStore
+	jobs                 chan dataExportJob
+}
+
+// dataExportJob is an export waiting to be assembled by a worker
+type dataExportJob struct {
+	exportModel dataexport.Model
+	userModel   user.Model
+	token       string
+}
+
+// NewDataExportService creates the service and starts DATA_EXPORT_WORKERS workers assembling the requested exports
+func NewDataExportService(dataExportRepository DataExportRepository, userService UserService, blobStore storage.BlobStore) *DataExportService {
+
+	// Gets the SMTP configuration from environment variables. Exports are optional, so a missing
+	// configuration only fails sending the emails.
+	dataExportSmtp := os.Getenv("EMAIL_TYPE_DATA_EXPORT_SMTP")
+	dataExportPort, err := strconv.Atoi(os.Getenv("EMAIL_TYPE_DATA_EXPORT_PORT"))
+	if err != nil {
+		log.LogWarning("EMAIL_TYPE_DATA_EXPORT_PORT is not set, data export emails cannot be sent")
+	}
+
+	s := &DataExportService{
+		dataExportRepository: dataExportRepository,
+		userService:          userService,
+		emailService: *NewSimpleEmailService(SMTPConfig{
+			Host: dataExportSmtp,
+			Port: dataExportPort,
+		}),
+		blobStore: blobStore,
+		jobs:      make(chan dataExportJob, dataExportQueueSize),
+	}
+	for i := 0; i < getDataExportWorkers(); i++ {
+		go s.work()
+	}
+	return s
+}
+
+type DataExportRepository interface {
+	repository.Repository[dataexport.Model]
+	// GetByTokenHash gets an export by the hash of its download token
+	GetByTokenHash(tokenHash string) (dataexport.Model, error)
+	// Complete records the blob store key of the archive of an export and marks it as ready, or as failed when
+	// the key is empty
+	Complete(id primitive.ObjectID, archiveKey string) error
+	// GetExpired gets the exports whose download link expired before the given time
+	GetExpired(before time.Time) ([]dataexport.Model, error)
+	// GetByUserID gets every export of a user
+	GetByUserID(userID primitive.ObjectID) ([]dataexport.Model, error)
+	// DeleteExpired deletes the exports whose download link expired before the given time
+	DeleteExpired(before time.Time) error
+	// DeleteByUserID deletes every export of a user
+	DeleteByUserID(userID primitive.ObjectID) error
+}
+
+// ExportSelf requests an export of the data of the current user
+func (s DataExportService) ExportSelf(authContext auth.PermissionContext) (dataexport.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfExportPermission) != nil {
+		return dataexport.Model{}, constants.ErrorPermissionDenied
+	}
+
+	return s.requestExport(*authContext.UserID, authContext)
+}
+
+// Export requests an export of the data of a user, the download link is sent to the user
+func (s DataExportService) Export(userID string, authContext auth.PermissionContext) (dataexport.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserExportPermission) != nil {
+		return dataexport.Model{}, constants.ErrorPermissionDenied
+	}
+
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return dataexport.Model{}, constants.ErrorBadRequest
+	}
+
+	return s.requestExport(objID, authContext)
+}
+
+// Download gets a ready export and its archive by its download token
+func (s DataExportService) Download(token string) (dataexport.Model, storage.Blob, error) {
+	exportModel, err := s.dataExportRepository.GetByTokenHash(hashToken(token))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dataexport.Model{}, storage.Blob{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return dataexport.Model{}, storage.Blob{}, constants.ErrorInternalServerError
+	}
+
+	if exportModel.Status != dataexport.StatusReady || exportModel.IsExpired(time.Now()) {
+		return dataexport.Model{}, storage.Blob{}, constants.ErrorNotFound
+	}
+
+	archive, err := s.blobStore.Get(context.Background(), exportModel.ArchiveKey)
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return dataexport.Model{}, storage.Blob{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return dataexport.Model{}, storage.Blob{}, constants.ErrorInternalServerError
+	}
+	return exportModel, archive, nil
+}
+
+// DeleteExpiredExports deletes the exports whose download link expired with their archives
+func (s DataExportService) DeleteExpiredExports() error {
+	now := time.Now()
+	expired, err := s.dataExportRepository.GetExpired(now)
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+	s.deleteArchives(expired)
+
+	if err = s.dataExportRepository.DeleteExpired(now); err != nil {
+		return constants.ErrorInternalServerError
+	}
+	return nil
+}
+
+// CleanupUser deletes the exports of a purged user with their archives
+func (s DataExportService) CleanupUser(userID primitive.ObjectID) error {
+	exports, err := s.dataExportRepository.GetByUserID(userID)
+	if err != nil {
+		return err
+	}
+	s.deleteArchives(exports)
+
+	return s.dataExportRepository.DeleteByUserID(userID)
+}
+
+// deleteArchives deletes the archives of the exports from the blob store, a failure is only logged
+func (s DataExportService) deleteArchives(exports []dataexport.Model) {
+	for _, exportModel := range exports {
+		if exportModel.ArchiveKey == "" {
+			continue
+		}
+		if err := s.blobStore.Delete(context.Background(), exportModel.ArchiveKey); err != nil {
+			log.LogError("Failed to delete the archive of data export %s: %v", exportModel.ID.Hex(), err)
+		}
+	}
+}
+
+// requestExport stores a pending export and queues it for the workers. When the queue is full the export is
+// marked as failed and the request is refused.
+func (s DataExportService) requestExport(userID primitive.ObjectID, authContext auth.PermissionContext) (dataexport.Model, error) {
+	userModel, err := s.userService.userRepository.GetByID(context.Background(), userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dataexport.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return dataexport.Model{}, constants.ErrorInternalServerError
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return dataexport.Model{}, constants.ErrorInternalServerError
+	}
+
+	exportModel, err := dataexport.NewDataExport(
+		dataexport.WithUserID(userID),
+		dataexport.WithRequestedBy(authContext.UserID),
+		dataexport.WithTokenHash(hashToken(token)),
+		dataexport.WithExpiresAt(time.Now().Add(time.Duration(getDataExportExpiresInHour())*time.Hour)),
+	)
+	if err != nil {
+		return dataexport.Model{}, constants.ErrorBadRequest
+	}
+
+	if _, err = s.dataExportRepository.Create(context.Background(), *exportModel); err != nil {
+		return dataexport.Model{}, constants.ErrorInternalServerError
+	}
+
+	select {
+	case s.jobs <- dataExportJob{exportModel: *exportModel, userModel: userModel, token: token}:
+	default:
+		s.fail(*exportModel)
+		return dataexport.Model{}, fmt.Errorf("%w: too many data exports are in progress", constants.ErrorTooManyRequests)
+	}
+
+	return *exportModel, nil
+}
+
+// work assembles the queued exports one at a time
+func (s DataExportService) work() {
+	for job := range s.jobs {
+		s.assemble(job.exportModel, job.userModel, job.token)
+	}
+}
+
+// assemble builds the archive of an export, stores it in the blob store and sends the download link to the user.
+// A failure marks the export as failed.
+func (s DataExportService) assemble(exportModel dataexport.Model, userModel user.Model, token string) {
+	archive, err := s.buildArchive(userModel)
+	if err != nil {
+		log.LogError("Failed to assemble data export %s: %v", exportModel.ID.Hex(), err)
+		s.fail(exportModel)
+		return
+	}
+
+	archiveKey := dataexport.ArchiveKey(exportModel.ID)
+	err = s.blobStore.Put(context.Background(), archiveKey, storage.Blob{ContentType: "application/zip", Data: archive})
+	if err != nil {
+		log.LogError("Failed to store the archive of data export %s: %v", exportModel.ID.Hex(), err)
+		s.fail(exportModel)
+		return
+	}
+
+	if err = s.dataExportRepository.Complete(exportModel.ID, archiveKey); err != nil {
+		log.LogError("Failed to store data export %s: %v", exportModel.ID.Hex(), err)
+		s.deleteArchives([]dataexport.Model{{ID: exportModel.ID, ArchiveKey: archiveKey}})
+		return
+	}
+
+	if err = s.sendDataExportEmail(exportModel, userModel, token); err != nil {
+		log.LogError("Failed to send data export email of %s: %v", exportModel.ID.Hex(), err)
+	}
+}
+
+// fail marks the export as failed
+func (s DataExportService) fail(exportModel dataexport.Model) {
+	if err := s.dataExportRepository.Complete(exportModel.ID, ""); err != nil {
+		log.LogError("Failed to mark data export %s as failed: %v", exportModel.ID.Hex(), err)
+	}
+}
+
+// buildArchive writes the profile and roles of the user and the data of every registered exporter
+// to a ZIP archive, one JSON file each
+func (s DataExportService) buildArchive(userModel user.Model) ([]byte, error) {
+	roles, err := s.userService.userRepository.GetUserRoles(userModel.ActiveRoleIDs(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]interface{}{
+		"profile": userModel,
+		"roles":   roles.Data,
+	}
+	for name, exporter := range registry.GetAllUserDataExporters() {
+		data, err := exporter.ExportUserData(userModel.ID)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	for _, name := range names {
+		fileWriter, err := zipWriter.Create(name + ".json")
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(fileWriter)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err = zipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// sendDataExportEmail sends the download link to the user. DATA_EXPORT_URL is the prefix the token is appended to.
+func (s DataExportService) sendDataExportEmail(exportModel dataexport.Model, userModel user.Model, token string) error {
+	if userModel.ContactInfo.Email == "" {
+		return errors.New("user has no email")
+	}
+
+	link := ""
+	if dataExportURL := os.Getenv("DATA_EXPORT_URL"); dataExportURL != "" {
+		link = dataExportURL + url.QueryEscape(token)
+	}
+
+	templateData := email.TemplateContext{
+		Data: dataexport.EmailTemplateData{
+			Username:  userModel.Username,
+			Token:     token,
+			Link:      link,
+			ExpiresAt: exportModel.ExpiresAt,
+		},
+	}
+
+	return s.emailService.SendEmail(email.SendEmailCommand{
+		To:      userModel.ContactInfo.Email,
+		Subject: "Your data export is ready",
+	}, email.EmailTypeDataExport, templateData)
+}
+
+// getDataExportWorkers reads the number of exports assembled concurrently from DATA_EXPORT_WORKERS
+func getDataExportWorkers() int {
+	workers, err := strconv.Atoi(os.Getenv("DATA_EXPORT_WORKERS"))
+	if err != nil || workers <= 0 {
+		return defaultDataExportWorkers
+	}
+	return workers
+}
+
+// getDataExportExpiresInHour reads the lifespan of the download links from DATA_EXPORT_EXPIRES_IN_HOUR
+func getDataExportExpiresInHour() int {
+	expiresInHour, err := strconv.Atoi(os.Getenv("DATA_EXPORT_EXPIRES_IN_HOUR"))
+	if err != nil || expiresInHour <= 0 {
+		return defaultDataExportExpiresInHour
+	}
+	return expiresInHour
+}
@@ -1,15 +1,20 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"github.com/LydiaTrack/ground/pkg/log"
 	"os"
 	"strconv"
 	"time"
 
+	"github.com/LydiaTrack/ground/internal/permissions"
 	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
 	"github.com/LydiaTrack/ground/pkg/domain/email"
 	"github.com/LydiaTrack/ground/pkg/domain/feedback"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/responses"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -52,6 +57,12 @@ type FeedbackRepository interface {
 	// GetFeedbacks retrieves all feedback records
 	GetFeedbacks() ([]feedback.Model, error)
 
+	// QueryFeedbacksPaginated retrieves a page of the feedback records, the latest first
+	QueryFeedbacksPaginated(page, limit int) (responses.PaginatedResult[feedback.Model], error)
+
+	// QueryFeedbacksCursor retrieves a page of the feedback records with keyset pagination, the latest first
+	QueryFeedbacksCursor(request repository.CursorRequest) (responses.CursorResult[feedback.Model], error)
+
 	// DeleteFeedback deletes a feedback record by ID
 	DeleteFeedback(id primitive.ObjectID) error
 
@@ -63,10 +74,17 @@ type FeedbackRepository interface {
 
 	// GetFeedbacksByUser retrieves all feedback records submitted by a specific user
 	GetFeedbacksByUser(userID primitive.ObjectID) ([]feedback.Model, error)
+
+	// DeleteFeedbacksByUser deletes all feedback records submitted by a specific user
+	DeleteFeedbacksByUser(userID primitive.ObjectID) error
 }
 
 // CreateFeedback creates a new feedback record
-func (s FeedbackService) CreateFeedback(command feedback.CreateFeedbackCommand) (feedback.Model, error) {
+func (s FeedbackService) CreateFeedback(command feedback.CreateFeedbackCommand, authContext auth.PermissionContext) (feedback.Model, error) {
+	if auth.Authorize(context.Background(), authContext, permissions.FeedbackCreatePermission, feedbackResource(command.UserID, "", "")) != nil {
+		return feedback.Model{}, constants.ErrorPermissionDenied
+	}
+
 	f, err := feedback.NewFeedback(
 		feedback.WithUserID(command.UserID),
 		feedback.WithType(command.Type),
@@ -99,7 +117,7 @@ func (s FeedbackService) CreateFeedback(command feedback.CreateFeedbackCommand)
 }
 
 // GetFeedback retrieves a feedback record by ID
-func (s FeedbackService) GetFeedback(id string) (feedback.Model, error) {
+func (s FeedbackService) GetFeedback(id string, authContext auth.PermissionContext) (feedback.Model, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return feedback.Model{}, err
@@ -107,14 +125,22 @@ func (s FeedbackService) GetFeedback(id string) (feedback.Model, error) {
 
 	model, err := s.feedbackRepository.GetFeedback(objID)
 	if err != nil {
-		return feedback.Model{}, err
+		return feedback.Model{}, s.lookupError(err, permissions.FeedbackReadPermission, authContext)
+	}
+
+	if auth.Authorize(context.Background(), authContext, permissions.FeedbackReadPermission, feedbackResource(model.UserID, id, model.Status)) != nil {
+		return feedback.Model{}, constants.ErrorPermissionDenied
 	}
 
 	return model, nil
 }
 
 // ExistsFeedback checks if a feedback record exists by ID
-func (s FeedbackService) ExistsFeedback(id string) (bool, error) {
+func (s FeedbackService) ExistsFeedback(id string, authContext auth.PermissionContext) (bool, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.FeedbackReadPermission) != nil {
+		return false, constants.ErrorPermissionDenied
+	}
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return false, err
@@ -129,7 +155,11 @@ func (s FeedbackService) ExistsFeedback(id string) (bool, error) {
 }
 
 // GetFeedbacks retrieves all feedback records
-func (s FeedbackService) GetFeedbacks() ([]feedback.Model, error) {
+func (s FeedbackService) GetFeedbacks(authContext auth.PermissionContext) ([]feedback.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.FeedbackReadPermission) != nil {
+		return nil, constants.ErrorPermissionDenied
+	}
+
 	feedbacks, err := s.feedbackRepository.GetFeedbacks()
 	if err != nil {
 		return nil, err
@@ -138,8 +168,31 @@ func (s FeedbackService) GetFeedbacks() ([]feedback.Model, error) {
 	return feedbacks, nil
 }
 
+// QueryPaginated retrieves a page of the feedback records, the latest first
+func (s FeedbackService) QueryPaginated(page, limit int, authContext auth.PermissionContext) (responses.PaginatedResult[feedback.Model], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.FeedbackReadPermission) != nil {
+		return responses.PaginatedResult[feedback.Model]{}, constants.ErrorPermissionDenied
+	}
+
+	return s.feedbackRepository.QueryFeedbacksPaginated(page, limit)
+}
+
+// QueryCursor retrieves a page of the feedback records with keyset pagination, the latest first
+func (s FeedbackService) QueryCursor(request repository.CursorRequest, authContext auth.PermissionContext) (responses.CursorResult[feedback.Model], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.FeedbackReadPermission) != nil {
+		return responses.CursorResult[feedback.Model]{}, constants.ErrorPermissionDenied
+	}
+
+	result, err := s.feedbackRepository.QueryFeedbacksCursor(request)
+	return result, cursorError(err)
+}
+
 // DeleteFeedback deletes a feedback record by ID
-func (s FeedbackService) DeleteFeedback(id string) error {
+func (s FeedbackService) DeleteFeedback(id string, authContext auth.PermissionContext) error {
+	if auth.CheckPermission(authContext.Permissions, permissions.FeedbackDeletePermission) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
@@ -149,27 +202,44 @@ func (s FeedbackService) DeleteFeedback(id string) error {
 }
 
 // DeleteOlderThan deletes all feedback records older than a specified date
-func (s FeedbackService) DeleteOlderThan(date time.Time) error {
+func (s FeedbackService) DeleteOlderThan(date time.Time, authContext auth.PermissionContext) error {
+	if auth.CheckPermission(authContext.Permissions, permissions.FeedbackDeletePermission) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
 	return s.feedbackRepository.DeleteOlderThan(date)
 }
 
 // UpdateFeedbackStatus updates the status of a feedback record by ID
-func (s FeedbackService) UpdateFeedbackStatus(id string, status feedback.FeedbackStatus) error {
+func (s FeedbackService) UpdateFeedbackStatus(id string, status feedback.FeedbackStatus, authContext auth.PermissionContext) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
+	model, err := s.feedbackRepository.GetFeedback(objID)
+	if err != nil {
+		return s.lookupError(constants.ErrorNotFound, permissions.FeedbackUpdatePermission, authContext)
+	}
+
+	if auth.Authorize(context.Background(), authContext, permissions.FeedbackUpdatePermission, feedbackResource(model.UserID, id, model.Status)) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
 	return s.feedbackRepository.UpdateFeedbackStatus(objID, status)
 }
 
 // GetFeedbacksByUser retrieves all feedback records submitted by a specific user
-func (s FeedbackService) GetFeedbacksByUser(userID string) ([]feedback.Model, error) {
+func (s FeedbackService) GetFeedbacksByUser(userID string, authContext auth.PermissionContext) ([]feedback.Model, error) {
 	objID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return nil, err
 	}
 
+	if auth.Authorize(context.Background(), authContext, permissions.FeedbackReadPermission, feedbackResource(objID, "", "")) != nil {
+		return nil, constants.ErrorPermissionDenied
+	}
+
 	feedbacks, err := s.feedbackRepository.GetFeedbacksByUser(objID)
 	if err != nil {
 		return nil, err
@@ -178,14 +248,20 @@ func (s FeedbackService) GetFeedbacksByUser(userID string) ([]feedback.Model, er
 	return feedbacks, nil
 }
 
+// CleanupUser deletes the feedback records of a purged user
+func (s FeedbackService) CleanupUser(userID primitive.ObjectID) error {
+	return s.feedbackRepository.DeleteFeedbacksByUser(userID)
+}
+
+// ExportUserData exports the feedback records of a user for the data export
+func (s FeedbackService) ExportUserData(userID primitive.ObjectID) (interface{}, error) {
+	return s.feedbackRepository.GetFeedbacksByUser(userID)
+}
+
 // sendFeedbackEmail sends an email notification when new feedback is submitted
 func (s FeedbackService) sendFeedbackEmail(emailDestination string, feedbackModel feedback.Model) error {
 	// Get the user who submitted the feedback
-	authContext := auth.PermissionContext{
-		Permissions: []auth.Permission{auth.AdminPermission},
-		UserID:      nil,
-	}
-	userModel, err := s.userService.Get(feedbackModel.UserID.Hex(), authContext)
+	userModel, err := s.userService.Get(feedbackModel.UserID.Hex(), auth.CreateAdminAuthContext())
 	if err != nil {
 		return fmt.Errorf("failed to get user: %w", err)
 	}
@@ -218,3 +294,22 @@ func (s FeedbackService) sendFeedbackEmail(emailDestination string, feedbackMode
 
 	return nil
 }
+
+// lookupError is the error of a failed feedback lookup. Callers without the permission could only be allowed by the
+// policies on an existing feedback, so they are denied whether the feedback exists or not.
+func (s FeedbackService) lookupError(err error, permission auth.Permission, authContext auth.PermissionContext) error {
+	if auth.CheckPermission(authContext.Permissions, permission) != nil {
+		return constants.ErrorPermissionDenied
+	}
+	return err
+}
+
+// feedbackResource describes a feedback record for authorization
+func feedbackResource(ownerID primitive.ObjectID, id string, status feedback.FeedbackStatus) auth.Resource {
+	return auth.Resource{
+		Domain:  permissions.FeedbackReadPermission.Domain,
+		ID:      id,
+		OwnerID: &ownerID,
+		Status:  string(status),
+	}
+}
@@ -0,0 +1,454 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/audit"
+	"github.com/LydiaTrack/ground/pkg/domain/group"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/responses"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var groupSearchFields = []string{"name", "description"}
+
+type GroupService struct {
+	groupRepository GroupRepository
+	userService     UserService
+	roleService     RoleService
+	auditService    AuditService
+}
+
+func NewGroupService(groupRepository GroupRepository, userService UserService, roleService RoleService,
+	auditService AuditService) *GroupService {
+	return &GroupService{
+		groupRepository: groupRepository,
+		userService:     userService,
+		roleService:     roleService,
+		auditService:    auditService,
+	}
+}
+
+type GroupRepository interface {
+	repository.Repository[group.Model]
+	// ExistsByName checks if a group exists by name
+	ExistsByName(name string) (bool, error)
+	// CountChildGroups counts the groups that are nested in the group
+	CountChildGroups(groupID primitive.ObjectID) (int64, error)
+	// RemoveParentFromGroups removes the group from the parents of every group nested in it
+	RemoveParentFromGroups(groupID primitive.ObjectID) error
+	// AddMember adds a member to a group
+	AddMember(member group.Member) error
+	// IsMember checks if the user is a direct member of the group
+	IsMember(groupID primitive.ObjectID, userID primitive.ObjectID) (bool, error)
+	// RemoveMember removes a member from a group
+	RemoveMember(groupID primitive.ObjectID, userID primitive.ObjectID) error
+	// RemoveMembers removes every member from a group
+	RemoveMembers(groupID primitive.ObjectID) error
+	// QueryMembersPaginated gets the direct members of a group, paginated
+	QueryMembersPaginated(groupID primitive.ObjectID, page, limit int) (responses.PaginatedResult[group.Member], error)
+	// GetGroupIDsOfUser gets the IDs of the groups the user is a direct member of
+	GetGroupIDsOfUser(userID primitive.ObjectID) ([]primitive.ObjectID, error)
+	// RemoveMemberships removes the user from every group
+	RemoveMemberships(userID primitive.ObjectID) error
+}
+
+// Create creates a group
+func (s GroupService) Create(command group.CreateGroupCommand, authContext auth.PermissionContext) (group.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.GroupCreatePermission) != nil {
+		return group.Model{}, constants.ErrorPermissionDenied
+	}
+
+	groupModel, err := group.NewGroup(
+		group.WithName(command.Name),
+		group.WithDescription(command.Description),
+		group.WithRoleIDs(command.RoleIDs),
+		group.WithParentIDs(command.ParentIDs),
+	)
+	if err != nil {
+		return group.Model{}, constants.ErrorBadRequest
+	}
+
+	if err := groupModel.Validate(); err != nil {
+		return group.Model{}, constants.ErrorBadRequest
+	}
+
+	exists, err := s.groupRepository.ExistsByName(groupModel.Name)
+	if err != nil {
+		return group.Model{}, constants.ErrorInternalServerError
+	}
+	if exists {
+		return group.Model{}, constants.ErrorConflict
+	}
+
+	if err := s.checkRolesExist(groupModel.RoleIDs); err != nil {
+		return group.Model{}, err
+	}
+	if err := s.validateParents(groupModel.ID, groupModel.ParentIDs); err != nil {
+		return group.Model{}, err
+	}
+
+	_, err = s.groupRepository.Create(context.Background(), *groupModel)
+	if err != nil {
+		return group.Model{}, constants.ErrorInternalServerError
+	}
+
+	return s.groupRepository.GetByID(context.Background(), groupModel.ID)
+}
+
+// Get gets a group by ID
+func (s GroupService) Get(id string, authContext auth.PermissionContext) (group.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.GroupReadPermission) != nil {
+		return group.Model{}, constants.ErrorPermissionDenied
+	}
+
+	groupID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return group.Model{}, constants.ErrorBadRequest
+	}
+
+	return s.getGroup(groupID)
+}
+
+// QueryPaginated queries the groups, paginated
+func (s GroupService) QueryPaginated(searchText string, page, limit int, authContext auth.PermissionContext) (responses.PaginatedResult[group.Model], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.GroupReadPermission) != nil {
+		return responses.PaginatedResult[group.Model]{}, constants.ErrorPermissionDenied
+	}
+
+	return s.groupRepository.QueryPaginate(context.Background(), nil, groupSearchFields, searchText, page, limit, nil)
+}
+
+// Update updates a group. Omitted roles and parents are left unchanged, empty ones are cleared.
+func (s GroupService) Update(id string, command group.UpdateGroupCommand, authContext auth.PermissionContext) (group.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.GroupUpdatePermission) != nil {
+		return group.Model{}, constants.ErrorPermissionDenied
+	}
+
+	groupID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return group.Model{}, constants.ErrorBadRequest
+	}
+
+	if err := command.Validate(); err != nil {
+		return group.Model{}, constants.ErrorBadRequest
+	}
+
+	groupModel, err := s.getGroup(groupID)
+	if err != nil {
+		return group.Model{}, err
+	}
+
+	if command.Name != groupModel.Name {
+		exists, err := s.groupRepository.ExistsByName(command.Name)
+		if err != nil {
+			return group.Model{}, constants.ErrorInternalServerError
+		}
+		if exists {
+			return group.Model{}, constants.ErrorConflict
+		}
+	}
+
+	if err := s.checkRolesExist(command.RoleIDs); err != nil {
+		return group.Model{}, err
+	}
+	if err := s.validateParents(groupID, command.ParentIDs); err != nil {
+		return group.Model{}, err
+	}
+
+	_, err = s.groupRepository.Update(context.Background(), groupID, command)
+	if err != nil {
+		return group.Model{}, constants.ErrorInternalServerError
+	}
+
+	return s.getGroup(groupID)
+}
+
+// Delete deletes a group. Its members are removed and the groups nested in it are detached.
+func (s GroupService) Delete(id string, authContext auth.PermissionContext) error {
+	if auth.CheckPermission(authContext.Permissions, permissions.GroupDeletePermission) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	groupID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return constants.ErrorBadRequest
+	}
+
+	if _, err := s.getGroup(groupID); err != nil {
+		return err
+	}
+
+	if err = s.groupRepository.RemoveMembers(groupID); err != nil {
+		return constants.ErrorInternalServerError
+	}
+	if err = s.groupRepository.RemoveParentFromGroups(groupID); err != nil {
+		return constants.ErrorInternalServerError
+	}
+	if _, err = s.groupRepository.Delete(context.Background(), groupID); err != nil {
+		return constants.ErrorInternalServerError
+	}
+
+	s.audit("DELETE", groupID, nil, authContext)
+	return nil
+}
+
+// AddMember adds a user to a group
+func (s GroupService) AddMember(id string, command group.AddGroupMemberCommand, authContext auth.PermissionContext) (group.Member, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.GroupMemberManagePermission) != nil {
+		return group.Member{}, constants.ErrorPermissionDenied
+	}
+
+	groupID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return group.Member{}, constants.ErrorBadRequest
+	}
+
+	if _, err := s.getGroup(groupID); err != nil {
+		return group.Member{}, err
+	}
+
+	exists, err := s.userService.Exists(command.UserID.Hex())
+	if err != nil {
+		return group.Member{}, err
+	}
+	if !exists {
+		return group.Member{}, constants.ErrorNotFound
+	}
+
+	isMember, err := s.groupRepository.IsMember(groupID, command.UserID)
+	if err != nil {
+		return group.Member{}, constants.ErrorInternalServerError
+	}
+	if isMember {
+		return group.Member{}, constants.ErrorConflict
+	}
+
+	member := group.Member{
+		ID:      primitive.NewObjectID(),
+		GroupID: groupID,
+		UserID:  command.UserID,
+		AddedBy: authContext.UserID,
+		AddedAt: time.Now(),
+	}
+	if err = s.groupRepository.AddMember(member); err != nil {
+		return group.Member{}, constants.ErrorInternalServerError
+	}
+
+	s.audit("ADD_MEMBER", groupID, &command.UserID, authContext)
+	return member, nil
+}
+
+// GetMembers gets the direct members of a group, paginated
+func (s GroupService) GetMembers(id string, page, limit int, authContext auth.PermissionContext) (responses.PaginatedResult[group.Member], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.GroupMemberReadPermission) != nil {
+		return responses.PaginatedResult[group.Member]{}, constants.ErrorPermissionDenied
+	}
+
+	groupID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return responses.PaginatedResult[group.Member]{}, constants.ErrorBadRequest
+	}
+
+	if _, err := s.getGroup(groupID); err != nil {
+		return responses.PaginatedResult[group.Member]{}, err
+	}
+
+	result, err := s.groupRepository.QueryMembersPaginated(groupID, page, limit)
+	if err != nil {
+		return responses.PaginatedResult[group.Member]{}, constants.ErrorInternalServerError
+	}
+
+	userIDs := make([]primitive.ObjectID, len(result.Data))
+	for i, member := range result.Data {
+		userIDs[i] = member.UserID
+	}
+	profiles, err := s.userService.GetPublicProfiles(userIDs)
+	if err != nil {
+		return responses.PaginatedResult[group.Member]{}, constants.ErrorInternalServerError
+	}
+	for i, member := range result.Data {
+		if profile, ok := profiles[member.UserID]; ok {
+			result.Data[i].User = &profile
+		}
+	}
+	return result, nil
+}
+
+// RemoveMember removes a user from a group
+func (s GroupService) RemoveMember(id string, userID string, authContext auth.PermissionContext) error {
+	if auth.CheckPermission(authContext.Permissions, permissions.GroupMemberManagePermission) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	groupID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return constants.ErrorBadRequest
+	}
+	memberID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return constants.ErrorBadRequest
+	}
+
+	isMember, err := s.groupRepository.IsMember(groupID, memberID)
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+	if !isMember {
+		return constants.ErrorNotFound
+	}
+
+	if err = s.groupRepository.RemoveMember(groupID, memberID); err != nil {
+		return constants.ErrorInternalServerError
+	}
+
+	s.audit("REMOVE_MEMBER", groupID, &memberID, authContext)
+	return nil
+}
+
+// GetUserRoleIDs retrieves the IDs of the roles the user holds through the groups the user is a member of,
+// including the groups they are nested in
+func (s GroupService) GetUserRoleIDs(userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	groupIDs, err := s.groupRepository.GetGroupIDsOfUser(userID)
+	if err != nil {
+		return nil, constants.ErrorInternalServerError
+	}
+
+	groups, err := s.ResolveGroups(groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var roleIDs []primitive.ObjectID
+	for _, groupModel := range groups {
+		roleIDs = append(roleIDs, groupModel.RoleIDs...)
+	}
+	return roleIDs, nil
+}
+
+// CleanupUser removes a purged user from every group
+func (s GroupService) CleanupUser(userID primitive.ObjectID) error {
+	return s.groupRepository.RemoveMemberships(userID)
+}
+
+// ResolveGroups retrieves the groups with the given IDs together with all the groups they are nested in.
+// Every group is returned once, so cycles cannot cause an endless resolution.
+func (s GroupService) ResolveGroups(groupIDs []primitive.ObjectID) ([]group.Model, error) {
+	visited := make(map[primitive.ObjectID]bool)
+	resolvedGroups := []group.Model{}
+	pending := groupIDs
+	for len(pending) > 0 {
+		var unvisited []primitive.ObjectID
+		for _, id := range pending {
+			if !visited[id] {
+				visited[id] = true
+				unvisited = append(unvisited, id)
+			}
+		}
+		if len(unvisited) == 0 {
+			break
+		}
+
+		groups, err := s.groupRepository.Query(context.Background(), bson.M{"_id": bson.M{"$in": unvisited}}, nil, "")
+		if err != nil {
+			return nil, constants.ErrorInternalServerError
+		}
+
+		pending = nil
+		for _, groupModel := range groups.Data {
+			resolvedGroups = append(resolvedGroups, groupModel)
+			pending = append(pending, groupModel.ParentIDs...)
+		}
+	}
+
+	return resolvedGroups, nil
+}
+
+// validateParents checks that every parent exists and that groupID is not an ancestor of its parents
+func (s GroupService) validateParents(groupID primitive.ObjectID, parentIDs []primitive.ObjectID) error {
+	if len(parentIDs) == 0 {
+		return nil
+	}
+
+	for _, parentID := range parentIDs {
+		if parentID == groupID {
+			return constants.ErrorBadRequest
+		}
+		exists, err := s.groupRepository.ExistsByID(context.Background(), parentID)
+		if err != nil {
+			return constants.ErrorInternalServerError
+		}
+		if !exists {
+			return constants.ErrorNotFound
+		}
+	}
+
+	ancestors, err := s.ResolveGroups(parentIDs)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.ID == groupID {
+			// The group would be nested in itself
+			return constants.ErrorBadRequest
+		}
+	}
+
+	return nil
+}
+
+// getGroup gets a group by ID
+func (s GroupService) getGroup(groupID primitive.ObjectID) (group.Model, error) {
+	groupModel, err := s.groupRepository.GetByID(context.Background(), groupID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return group.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return group.Model{}, constants.ErrorInternalServerError
+	}
+	return groupModel, nil
+}
+
+// checkRolesExist checks that every role exists
+func (s GroupService) checkRolesExist(roleIDs []primitive.ObjectID) error {
+	for _, roleID := range roleIDs {
+		exists, err := s.roleService.Exists(roleID.Hex(), auth.CreateAdminAuthContext())
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return constants.ErrorNotFound
+		}
+	}
+	return nil
+}
+
+// audit records a membership change. A failed record is logged and does not fail the change.
+func (s GroupService) audit(command string, groupID primitive.ObjectID, userID *primitive.ObjectID, authContext auth.PermissionContext) {
+	additionalData := map[string]interface{}{"groupId": groupID.Hex()}
+	if userID != nil {
+		additionalData["userId"] = userID.Hex()
+	}
+	relatedPrincipal := ""
+	if authContext.UserID != nil {
+		relatedPrincipal = authContext.UserID.Hex()
+	}
+
+	_, err := s.auditService.CreateAudit(audit.CreateAuditCommand{
+		Source:           "group",
+		Operation:        audit.Operation{Domain: "group", Command: command},
+		AdditionalData:   additionalData,
+		RelatedPrincipal: relatedPrincipal,
+	}, auth.CreateAdminAuthContext())
+	if err != nil {
+		log.LogError("Failed to audit group change %s: %v", command, err)
+	}
+}
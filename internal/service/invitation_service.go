@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/email"
+	"github.com/LydiaTrack/ground/pkg/domain/invitation"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/responses"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const defaultInvitationExpiresInHour = 72
+
+var invitationSearchFields = []string{"email"}
+
+type InvitationService struct {
+	invitationRepository InvitationRepository
+	userService          UserService
+	roleService          RoleService
+	emailService         SimpleEmailService
+}
+
+func NewInvitationService(invitationRepository InvitationRepository, userService UserService, roleService RoleService) *InvitationService {
+
+	// Gets the SMTP configuration from environment variables. Invitations are optional, so a missing
+	// configuration only fails sending the emails.
+	invitationSmtp := os.Getenv("EMAIL_TYPE_INVITATION_SMTP")
+	invitationPort, err := strconv.Atoi(os.Getenv("EMAIL_TYPE_INVITATION_PORT"))
+	if err != nil {
+		log.LogWarning("EMAIL_TYPE_INVITATION_PORT is not set, invitation emails cannot be sent")
+	}
+
+	return &InvitationService{
+		invitationRepository: invitationRepository,
+		userService:          userService,
+		roleService:          roleService,
+		emailService: *NewSimpleEmailService(SMTPConfig{
+			Host: invitationSmtp,
+			Port: invitationPort,
+		}),
+	}
+}
+
+type InvitationRepository interface {
+	repository.Repository[invitation.Model]
+	// GetByTokenHash gets an invitation by the hash of its token
+	GetByTokenHash(tokenHash string) (invitation.Model, error)
+	// ExistsPendingByEmail checks if there is a pending invitation for the email that has not expired
+	ExistsPendingByEmail(email string) (bool, error)
+	// UpdateStatus sets the status of a pending invitation, it returns false if the invitation is no longer pending
+	UpdateStatus(id primitive.ObjectID, status invitation.Status, acceptedUserID *primitive.ObjectID) (bool, error)
+}
+
+// Create creates an invitation and sends its token to the invitee by email
+func (s InvitationService) Create(command invitation.CreateInvitationCommand, authContext auth.PermissionContext) (invitation.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.InvitationCreatePermission) != nil {
+		return invitation.Model{}, constants.ErrorPermissionDenied
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return invitation.Model{}, constants.ErrorInternalServerError
+	}
+
+	expiresAt := time.Now().Add(time.Duration(getInvitationExpiresInHour()) * time.Hour)
+	if command.ExpiresAt != nil {
+		expiresAt = *command.ExpiresAt
+	}
+
+	invitationModel, err := invitation.NewInvitation(
+		invitation.WithEmail(strings.ToLower(strings.TrimSpace(command.Email))),
+		invitation.WithRoleIDs(command.RoleIDs),
+		invitation.WithTokenHash(hashToken(token)),
+		invitation.WithInvitedBy(authContext.UserID),
+		invitation.WithExpiresAt(expiresAt),
+	)
+	if err != nil {
+		return invitation.Model{}, constants.ErrorBadRequest
+	}
+
+	if err := invitationModel.Validate(); err != nil {
+		return invitation.Model{}, constants.ErrorBadRequest
+	}
+
+	if err := s.roleService.CheckAssignable(invitationModel.RoleIDs, authContext); err != nil {
+		return invitation.Model{}, err
+	}
+
+	exists, err := s.userService.ExistsByEmail(invitationModel.Email, auth.CreateAdminAuthContext())
+	if err != nil {
+		return invitation.Model{}, constants.ErrorInternalServerError
+	}
+	if exists {
+		return invitation.Model{}, constants.ErrorConflict
+	}
+
+	pending, err := s.invitationRepository.ExistsPendingByEmail(invitationModel.Email)
+	if err != nil {
+		return invitation.Model{}, constants.ErrorInternalServerError
+	}
+	if pending {
+		return invitation.Model{}, constants.ErrorConflict
+	}
+
+	_, err = s.invitationRepository.Create(context.Background(), *invitationModel)
+	if err != nil {
+		return invitation.Model{}, constants.ErrorInternalServerError
+	}
+
+	if err := s.sendInvitationEmail(*invitationModel, token); err != nil {
+		log.LogError("Failed to send invitation email to %s: %v", invitationModel.Email, err)
+		// An invitation that never reached the invitee would block inviting the email again
+		if _, err := s.invitationRepository.Delete(context.Background(), invitationModel.ID); err != nil {
+			log.LogError("Failed to delete unsent invitation %s: %v", invitationModel.ID.Hex(), err)
+		}
+		return invitation.Model{}, constants.ErrorInternalServerError
+	}
+
+	return *invitationModel, nil
+}
+
+// Get gets an invitation by ID
+func (s InvitationService) Get(id string, authContext auth.PermissionContext) (invitation.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.InvitationReadPermission) != nil {
+		return invitation.Model{}, constants.ErrorPermissionDenied
+	}
+
+	invitationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return invitation.Model{}, constants.ErrorBadRequest
+	}
+
+	return s.getInvitation(invitationID)
+}
+
+// QueryPaginated queries the invitations, optionally only the ones with the given status
+func (s InvitationService) QueryPaginated(searchText string, status string, page, limit int, authContext auth.PermissionContext) (responses.PaginatedResult[invitation.Model], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.InvitationReadPermission) != nil {
+		return responses.PaginatedResult[invitation.Model]{}, constants.ErrorPermissionDenied
+	}
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = invitation.Status(strings.ToUpper(status))
+	}
+
+	return s.invitationRepository.QueryPaginate(context.Background(), filter, invitationSearchFields, searchText, page, limit, bson.M{"createdDate": -1})
+}
+
+// Revoke revokes a pending invitation, its token can no longer be accepted
+func (s InvitationService) Revoke(id string, authContext auth.PermissionContext) error {
+	if auth.CheckPermission(authContext.Permissions, permissions.InvitationDeletePermission) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	invitationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return constants.ErrorBadRequest
+	}
+
+	if _, err := s.getInvitation(invitationID); err != nil {
+		return err
+	}
+
+	revoked, err := s.invitationRepository.UpdateStatus(invitationID, invitation.StatusRevoked, nil)
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+	if !revoked {
+		return invitation.ErrInvitationNotPending
+	}
+	return nil
+}
+
+// Accept creates the account of the invitee with the email and roles of the invitation.
+// Accepting is not subject to the signup mode.
+func (s InvitationService) Accept(command invitation.AcceptInvitationCommand) (user.Model, error) {
+	if err := command.Validate(); err != nil {
+		return user.Model{}, constants.ErrorBadRequest
+	}
+
+	invitationModel, err := s.invitationRepository.GetByTokenHash(hashToken(command.Token))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+
+	if invitationModel.Status != invitation.StatusPending {
+		return user.Model{}, invitation.ErrInvitationNotPending
+	}
+	if invitationModel.IsExpired(time.Now()) {
+		return user.Model{}, invitation.ErrInvitationExpired
+	}
+
+	if err := s.userService.ValidateSelfProperties(command.Properties); err != nil {
+		return user.Model{}, err
+	}
+
+	// The roles are granted on behalf of the inviter, so the inviter must still be able to grant them
+	assignments, err := s.roleAssignments(invitationModel)
+	if err != nil {
+		return user.Model{}, err
+	}
+
+	// The roles are granted with the account, so an account is never left without them
+	userModel, err := s.userService.Create(user.CreateUserCommand{
+		Username:        command.Username,
+		Password:        command.Password,
+		PersonInfo:      command.PersonInfo,
+		ContactInfo:     user.ContactInfo{Email: invitationModel.Email},
+		Properties:      command.Properties,
+		RoleAssignments: assignments,
+	}, auth.CreateAdminAuthContext())
+	if err != nil {
+		return user.Model{}, err
+	}
+
+	// The account is complete at this point, an invitation left pending can no longer be accepted for its email
+	accepted, err := s.invitationRepository.UpdateStatus(invitationModel.ID, invitation.StatusAccepted, &userModel.ID)
+	if err != nil {
+		log.LogError("Failed to mark invitation %s as accepted by user %s: %v", invitationModel.ID.Hex(), userModel.ID.Hex(), err)
+	} else if !accepted {
+		log.LogError("Invitation %s was accepted or revoked while creating user %s", invitationModel.ID.Hex(), userModel.ID.Hex())
+	}
+	return userModel, nil
+}
+
+// getInvitation gets an invitation by ID
+func (s InvitationService) getInvitation(invitationID primitive.ObjectID) (invitation.Model, error) {
+	invitationModel, err := s.invitationRepository.GetByID(context.Background(), invitationID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return invitation.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return invitation.Model{}, constants.ErrorInternalServerError
+	}
+	return invitationModel, nil
+}
+
+// roleAssignments checks that the inviter can still grant the roles of the invitation, with their current permissions,
+// and returns the assignments of the roles. Invitations without an inviter were created by the system.
+func (s InvitationService) roleAssignments(invitationModel invitation.Model) ([]user.RoleAssignment, error) {
+	if len(invitationModel.RoleIDs) == 0 {
+		return nil, nil
+	}
+
+	inviterContext := auth.CreateAdminAuthContext()
+	if invitationModel.InvitedBy != nil {
+		inviter, err := s.userService.userRepository.GetByID(context.Background(), *invitationModel.InvitedBy)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("%w: the inviter no longer exists", constants.ErrorPermissionDenied)
+		}
+		if err != nil {
+			return nil, constants.ErrorInternalServerError
+		}
+		inviterPermissions, err := s.userService.GetPermissionList(inviter)
+		if err != nil {
+			return nil, constants.ErrorInternalServerError
+		}
+		inviterContext = auth.PermissionContext{Permissions: inviterPermissions, UserID: invitationModel.InvitedBy}
+	}
+	if err := s.roleService.CheckAssignable(invitationModel.RoleIDs, inviterContext); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	assignments := make([]user.RoleAssignment, 0, len(invitationModel.RoleIDs))
+	for _, roleID := range invitationModel.RoleIDs {
+		assignments = append(assignments, user.RoleAssignment{
+			RoleID:    roleID,
+			GrantedBy: invitationModel.InvitedBy,
+			GrantedAt: now,
+			Reason:    "invitation",
+		})
+	}
+	return assignments, nil
+}
+
+// sendInvitationEmail sends the token to the invitee. INVITATION_URL is the prefix the token is appended to.
+func (s InvitationService) sendInvitationEmail(invitationModel invitation.Model, token string) error {
+	link := ""
+	if invitationURL := os.Getenv("INVITATION_URL"); invitationURL != "" {
+		link = invitationURL + url.QueryEscape(token)
+	}
+
+	templateData := email.TemplateContext{
+		Data: invitation.EmailTemplateData{
+			Email:     invitationModel.Email,
+			Token:     token,
+			Link:      link,
+			ExpiresAt: invitationModel.ExpiresAt,
+		},
+	}
+
+	return s.emailService.SendEmail(email.SendEmailCommand{
+		To:      invitationModel.Email,
+		Subject: "You have been invited",
+	}, email.EmailTypeInvitation, templateData)
+}
+
+// getInvitationExpiresInHour reads the default lifespan of the invitations from INVITATION_EXPIRES_IN_HOUR
+func getInvitationExpiresInHour() int {
+	expiresInHour, err := strconv.Atoi(os.Getenv("INVITATION_EXPIRES_IN_HOUR"))
+	if err != nil || expiresInHour <= 0 {
+		return defaultInvitationExpiresInHour
+	}
+	return expiresInHour
+}
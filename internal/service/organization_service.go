@@ -0,0 +1,415 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/organization"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/responses"
+	"github.com/LydiaTrack/ground/pkg/tenant"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var organizationSearchFields = []string{"name", "slug"}
+
+type OrganizationService struct {
+	organizationRepository OrganizationRepository
+	membershipRepository   MembershipRepository
+	userService            UserService
+	roleService            RoleService
+}
+
+func NewOrganizationService(organizationRepository OrganizationRepository, membershipRepository MembershipRepository,
+	userService UserService, roleService RoleService) *OrganizationService {
+	return &OrganizationService{
+		organizationRepository: organizationRepository,
+		membershipRepository:   membershipRepository,
+		userService:            userService,
+		roleService:            roleService,
+	}
+}
+
+type OrganizationRepository interface {
+	repository.Repository[organization.Model]
+	// ExistsBySlug checks if an organization exists by slug
+	ExistsBySlug(slug string) (bool, error)
+}
+
+// MembershipRepository stores the memberships, scoped by the organization in the context
+type MembershipRepository interface {
+	repository.Repository[organization.Membership]
+	// GetMembership gets the membership of the user in the organization of the context
+	GetMembership(ctx context.Context, userID primitive.ObjectID) (organization.Membership, error)
+	// UpdateMemberRoles replaces the roles of the user in the organization of the context
+	UpdateMemberRoles(ctx context.Context, userID primitive.ObjectID, roleIDs []primitive.ObjectID) error
+	// DeleteMembership removes the user from the organization of the context
+	DeleteMembership(ctx context.Context, userID primitive.ObjectID) error
+	// DeleteMemberships removes every member from the organization of the context
+	DeleteMemberships(ctx context.Context) error
+	// GetOrganizationIDsOfUser gets the IDs of the organizations of the context the user is a member of
+	GetOrganizationIDsOfUser(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error)
+	// DeleteMembershipsOfUser removes the user from the organizations of the context
+	DeleteMembershipsOfUser(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// Create creates an organization
+func (s OrganizationService) Create(command organization.CreateOrganizationCommand, authContext auth.PermissionContext) (organization.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.OrganizationCreatePermission) != nil {
+		return organization.Model{}, constants.ErrorPermissionDenied
+	}
+
+	organizationModel, err := organization.NewOrganization(
+		organization.WithName(command.Name),
+		organization.WithSlug(command.Slug),
+	)
+	if err != nil {
+		return organization.Model{}, constants.ErrorBadRequest
+	}
+
+	if err := organizationModel.Validate(); err != nil {
+		return organization.Model{}, constants.ErrorBadRequest
+	}
+
+	exists, err := s.organizationRepository.ExistsBySlug(organizationModel.Slug)
+	if err != nil {
+		return organization.Model{}, constants.ErrorInternalServerError
+	}
+	if exists {
+		return organization.Model{}, constants.ErrorConflict
+	}
+
+	_, err = s.organizationRepository.Create(context.Background(), *organizationModel)
+	if err != nil {
+		return organization.Model{}, constants.ErrorInternalServerError
+	}
+
+	return s.organizationRepository.GetByID(context.Background(), organizationModel.ID)
+}
+
+// Get gets an organization by ID
+func (s OrganizationService) Get(id string, authContext auth.PermissionContext) (organization.Model, error) {
+	organizationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return organization.Model{}, constants.ErrorBadRequest
+	}
+
+	if err := checkTenantAccess(organizationID, permissions.OrganizationReadPermission, authContext); err != nil {
+		return organization.Model{}, err
+	}
+
+	organizationModel, err := s.organizationRepository.GetByID(context.Background(), organizationID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return organization.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return organization.Model{}, constants.ErrorInternalServerError
+	}
+	return organizationModel, nil
+}
+
+// QueryPaginated queries every organization. It is not available with an active organization.
+func (s OrganizationService) QueryPaginated(searchText string, page, limit int, authContext auth.PermissionContext) (responses.PaginatedResult[organization.Model], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.OrganizationReadPermission) != nil || authContext.TenantID != nil {
+		return responses.PaginatedResult[organization.Model]{}, constants.ErrorPermissionDenied
+	}
+
+	return s.organizationRepository.QueryPaginate(context.Background(), nil, organizationSearchFields, searchText, page, limit, nil)
+}
+
+// GetMine gets the organizations the current user is a member of
+func (s OrganizationService) GetMine(authContext auth.PermissionContext) (responses.QueryResult[organization.Model], error) {
+	if authContext.UserID == nil {
+		return responses.QueryResult[organization.Model]{}, constants.ErrorUnauthorized
+	}
+
+	// The organizations of the user are listed across every organization, whichever one is active
+	organizationIDs, err := s.membershipRepository.GetOrganizationIDsOfUser(tenant.Global(context.Background()), *authContext.UserID)
+	if err != nil {
+		return responses.QueryResult[organization.Model]{}, constants.ErrorInternalServerError
+	}
+	if len(organizationIDs) == 0 {
+		return *responses.NewQueryResult(0, []organization.Model{}), nil
+	}
+
+	return s.organizationRepository.Query(context.Background(), bson.M{"_id": bson.M{"$in": organizationIDs}}, nil, "")
+}
+
+// Update updates an organization
+func (s OrganizationService) Update(id string, command organization.UpdateOrganizationCommand, authContext auth.PermissionContext) (organization.Model, error) {
+	organizationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return organization.Model{}, constants.ErrorBadRequest
+	}
+
+	if err := checkTenantAccess(organizationID, permissions.OrganizationUpdatePermission, authContext); err != nil {
+		return organization.Model{}, err
+	}
+
+	if err := command.Validate(); err != nil {
+		return organization.Model{}, constants.ErrorBadRequest
+	}
+
+	if err := s.checkExists(organizationID); err != nil {
+		return organization.Model{}, err
+	}
+
+	_, err = s.organizationRepository.Update(context.Background(), organizationID, command)
+	if err != nil {
+		return organization.Model{}, constants.ErrorInternalServerError
+	}
+
+	return s.organizationRepository.GetByID(context.Background(), organizationID)
+}
+
+// Delete deletes an organization together with its memberships. It is not available with an active organization.
+func (s OrganizationService) Delete(id string, authContext auth.PermissionContext) error {
+	if auth.CheckPermission(authContext.Permissions, permissions.OrganizationDeletePermission) != nil || authContext.TenantID != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	organizationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return constants.ErrorBadRequest
+	}
+
+	if err := s.checkExists(organizationID); err != nil {
+		return err
+	}
+
+	if err = s.membershipRepository.DeleteMemberships(tenant.WithTenant(context.Background(), organizationID)); err != nil {
+		return constants.ErrorInternalServerError
+	}
+
+	_, err = s.organizationRepository.Delete(context.Background(), organizationID)
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+	return nil
+}
+
+// AddMember adds a user to an organization with the given roles
+func (s OrganizationService) AddMember(id string, command organization.AddMemberCommand, authContext auth.PermissionContext) (organization.Membership, error) {
+	organizationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return organization.Membership{}, constants.ErrorBadRequest
+	}
+
+	if err := checkTenantAccess(organizationID, permissions.OrganizationMemberManagePermission, authContext); err != nil {
+		return organization.Membership{}, err
+	}
+
+	if err := s.checkExists(organizationID); err != nil {
+		return organization.Membership{}, err
+	}
+
+	exists, err := s.userService.Exists(command.UserID.Hex())
+	if err != nil {
+		return organization.Membership{}, err
+	}
+	if !exists {
+		return organization.Membership{}, constants.ErrorNotFound
+	}
+
+	if err := s.checkMemberRoles(command.RoleIDs, authContext); err != nil {
+		return organization.Membership{}, err
+	}
+
+	ctx := tenant.WithTenant(context.Background(), organizationID)
+	_, err = s.membershipRepository.GetMembership(ctx, command.UserID)
+	if err == nil {
+		return organization.Membership{}, constants.ErrorConflict
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return organization.Membership{}, constants.ErrorInternalServerError
+	}
+
+	roleIDs := command.RoleIDs
+	if roleIDs == nil {
+		roleIDs = []primitive.ObjectID{}
+	}
+	membership := organization.Membership{
+		ID:             primitive.NewObjectID(),
+		OrganizationID: organizationID,
+		UserID:         command.UserID,
+		RoleIDs:        roleIDs,
+		AddedBy:        authContext.UserID,
+		JoinedAt:       time.Now(),
+	}
+	_, err = s.membershipRepository.Create(ctx, membership)
+	if err != nil {
+		return organization.Membership{}, constants.ErrorInternalServerError
+	}
+
+	return membership, nil
+}
+
+// GetMembers gets the memberships of an organization, paginated
+func (s OrganizationService) GetMembers(id string, page, limit int, authContext auth.PermissionContext) (responses.PaginatedResult[organization.Membership], error) {
+	organizationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return responses.PaginatedResult[organization.Membership]{}, constants.ErrorBadRequest
+	}
+
+	if err := checkTenantAccess(organizationID, permissions.OrganizationMemberReadPermission, authContext); err != nil {
+		return responses.PaginatedResult[organization.Membership]{}, err
+	}
+
+	result, err := s.membershipRepository.QueryPaginate(tenant.WithTenant(context.Background(), organizationID), nil, nil, "", page, limit, nil)
+	if err != nil {
+		return responses.PaginatedResult[organization.Membership]{}, err
+	}
+
+	userIDs := make([]primitive.ObjectID, len(result.Data))
+	for i, membership := range result.Data {
+		userIDs[i] = membership.UserID
+	}
+	profiles, err := s.userService.GetPublicProfiles(userIDs)
+	if err != nil {
+		return responses.PaginatedResult[organization.Membership]{}, constants.ErrorInternalServerError
+	}
+	for i, membership := range result.Data {
+		if profile, ok := profiles[membership.UserID]; ok {
+			result.Data[i].User = &profile
+		}
+	}
+	return result, nil
+}
+
+// UpdateMemberRoles replaces the roles a user holds in an organization
+func (s OrganizationService) UpdateMemberRoles(id string, userID string, command organization.UpdateMemberRolesCommand, authContext auth.PermissionContext) (organization.Membership, error) {
+	organizationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return organization.Membership{}, constants.ErrorBadRequest
+	}
+	memberID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return organization.Membership{}, constants.ErrorBadRequest
+	}
+
+	if err := checkTenantAccess(organizationID, permissions.OrganizationMemberManagePermission, authContext); err != nil {
+		return organization.Membership{}, err
+	}
+
+	if err := s.checkMemberRoles(command.RoleIDs, authContext); err != nil {
+		return organization.Membership{}, err
+	}
+
+	ctx := tenant.WithTenant(context.Background(), organizationID)
+	if _, err := s.getMembership(ctx, memberID); err != nil {
+		return organization.Membership{}, err
+	}
+
+	roleIDs := command.RoleIDs
+	if roleIDs == nil {
+		roleIDs = []primitive.ObjectID{}
+	}
+	if err = s.membershipRepository.UpdateMemberRoles(ctx, memberID, roleIDs); err != nil {
+		return organization.Membership{}, constants.ErrorInternalServerError
+	}
+
+	return s.getMembership(ctx, memberID)
+}
+
+// RemoveMember removes a user from an organization
+func (s OrganizationService) RemoveMember(id string, userID string, authContext auth.PermissionContext) error {
+	organizationID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return constants.ErrorBadRequest
+	}
+	memberID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return constants.ErrorBadRequest
+	}
+
+	if err := checkTenantAccess(organizationID, permissions.OrganizationMemberManagePermission, authContext); err != nil {
+		return err
+	}
+
+	ctx := tenant.WithTenant(context.Background(), organizationID)
+	if _, err := s.getMembership(ctx, memberID); err != nil {
+		return err
+	}
+
+	if err = s.membershipRepository.DeleteMembership(ctx, memberID); err != nil {
+		return constants.ErrorInternalServerError
+	}
+	return nil
+}
+
+// CleanupUser removes a purged user from every organization
+func (s OrganizationService) CleanupUser(userID primitive.ObjectID) error {
+	return s.membershipRepository.DeleteMembershipsOfUser(tenant.Global(context.Background()), userID)
+}
+
+// GetTenantPermissionList retrieves the permissions of the roles a user holds in an organization, the global
+// roles are not included. Users that are not members are denied.
+func (s OrganizationService) GetTenantPermissionList(userModel user.Model, tenantID primitive.ObjectID) ([]auth.Permission, error) {
+	membership, err := s.getMembership(tenant.WithTenant(context.Background(), tenantID), userModel.ID)
+	if errors.Is(err, constants.ErrorNotFound) {
+		return nil, constants.ErrorPermissionDenied
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userRoles, err := s.roleService.ResolveRoles(membership.RoleIDs, auth.CreateAdminAuthContext())
+	if err != nil {
+		return nil, err
+	}
+
+	return role.MergePermissions(userRoles), nil
+}
+
+// getMembership gets the membership of the user in the organization of the context
+func (s OrganizationService) getMembership(ctx context.Context, userID primitive.ObjectID) (organization.Membership, error) {
+	membership, err := s.membershipRepository.GetMembership(ctx, userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return organization.Membership{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return organization.Membership{}, constants.ErrorInternalServerError
+	}
+	return membership, nil
+}
+
+// checkExists checks that the organization exists
+func (s OrganizationService) checkExists(organizationID primitive.ObjectID) error {
+	exists, err := s.organizationRepository.ExistsByID(context.Background(), organizationID)
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+	if !exists {
+		return constants.ErrorNotFound
+	}
+	return nil
+}
+
+// checkMemberRoles checks that the caller can grant the roles of a membership with the permissions they hold in
+// the organization
+func (s OrganizationService) checkMemberRoles(roleIDs []primitive.ObjectID, authContext auth.PermissionContext) error {
+	return s.roleService.CheckAssignable(roleIDs, auth.PermissionContext{
+		Permissions: authContext.TenantScopedPermissions(),
+		UserID:      authContext.UserID,
+	})
+}
+
+// checkTenantAccess checks the permission and, with an active organization, that it is the given organization.
+// Permissions granted in one organization must not be usable in another one.
+func checkTenantAccess(organizationID primitive.ObjectID, permission auth.Permission, authContext auth.PermissionContext) error {
+	if authContext.TenantID != nil && *authContext.TenantID != organizationID {
+		return constants.ErrorPermissionDenied
+	}
+	if auth.CheckPermission(authContext.TenantScopedPermissions(), permission) != nil {
+		return constants.ErrorPermissionDenied
+	}
+	return nil
+}
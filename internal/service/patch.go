@@ -0,0 +1,17 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/mongodb/patch"
+)
+
+// patchError reports the patches that cannot be applied as bad requests
+func patchError(err error) error {
+	if errors.Is(err, patch.ErrInvalidPatch) || errors.Is(err, patch.ErrForbiddenPath) || errors.Is(err, patch.ErrUnsupportedContentType) {
+		return fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+	return err
+}
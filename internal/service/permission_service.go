@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/permission"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"github.com/LydiaTrack/ground/pkg/middlewares"
+	"github.com/LydiaTrack/ground/pkg/registry"
+)
+
+type PermissionService struct {
+	userService UserService
+	roleService RoleService
+}
+
+func NewPermissionService(userService UserService, roleService RoleService) *PermissionService {
+	return &PermissionService{
+		userService: userService,
+		roleService: roleService,
+	}
+}
+
+// GetAll retrieves every permission registered by Ground and the host application
+func (s PermissionService) GetAll(authContext auth.PermissionContext) ([]auth.Permission, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.RoleReadPermission) != nil {
+		return nil, constants.ErrorPermissionDenied
+	}
+
+	return registry.GetAllPermissions(), nil
+}
+
+// GetRoutePermissions retrieves the permissions required by each route registered with the permission middleware
+func (s PermissionService) GetRoutePermissions(authContext auth.PermissionContext) ([]middlewares.RoutePermission, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.RoleReadPermission) != nil {
+		return nil, constants.ErrorPermissionDenied
+	}
+
+	return middlewares.GetRoutePermissions(), nil
+}
+
+// Explain explains why a user is allowed or denied a permission, listing the roles that contribute each
+// effective permission of the user and the rule that decided the outcome
+func (s PermissionService) Explain(command permission.ExplainCommand, authContext auth.PermissionContext) (permission.Explanation, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.PermissionExplainPermission) != nil {
+		return permission.Explanation{}, constants.ErrorPermissionDenied
+	}
+
+	if err := command.Validate(); err != nil {
+		return permission.Explanation{}, constants.ErrorBadRequest
+	}
+
+	exists, err := s.userService.Exists(command.UserID.Hex())
+	if err != nil {
+		return permission.Explanation{}, err
+	}
+	if !exists {
+		return permission.Explanation{}, constants.ErrorNotFound
+	}
+
+	userModel, err := s.userService.Get(command.UserID.Hex(), auth.CreateAdminAuthContext())
+	if err != nil {
+		return permission.Explanation{}, err
+	}
+
+	// The roles the user holds through groups contribute to the permissions as in the permission middleware
+	roleIDs, err := s.userService.GetRoleIDs(userModel)
+	if err != nil {
+		return permission.Explanation{}, err
+	}
+
+	userRoles, err := s.roleService.ResolveRoles(roleIDs, auth.CreateAdminAuthContext())
+	if err != nil {
+		return permission.Explanation{}, err
+	}
+
+	grants := grantsOf(userRoles)
+	subject := auth.PermissionContext{
+		Permissions: role.MergePermissions(userRoles),
+		UserID:      &userModel.ID,
+	}
+	resource := auth.Resource{Domain: command.Permission.Domain}
+	if command.Resource != nil {
+		resource = *command.Resource
+	}
+
+	decision := auth.ExplainAuthorize(context.Background(), subject, command.Permission, resource)
+	decidingRoles := []permission.RoleReference{}
+	if decision.MatchedPermission != nil {
+		for _, grant := range grants {
+			if grant.Permission == *decision.MatchedPermission {
+				decidingRoles = grant.Roles
+				break
+			}
+		}
+	}
+
+	return permission.Explanation{
+		UserID:               userModel.ID,
+		Permission:           command.Permission,
+		Decision:             decision,
+		DecidingRoles:        decidingRoles,
+		EffectivePermissions: grants,
+	}, nil
+}
+
+// grantsOf lists every permission of the roles together with the roles that contain it
+func grantsOf(roles []role.Model) []permission.Grant {
+	grants := []permission.Grant{}
+	indexes := make(map[auth.Permission]int)
+	for _, roleModel := range roles {
+		reference := permission.RoleReference{ID: roleModel.ID, Name: roleModel.Name}
+		for _, rolePermission := range roleModel.Permissions {
+			index, ok := indexes[rolePermission]
+			if !ok {
+				index = len(grants)
+				indexes[rolePermission] = index
+				grants = append(grants, permission.Grant{Permission: rolePermission})
+			}
+			grants[index].Roles = append(grants[index].Roles, reference)
+		}
+	}
+	return grants
+}
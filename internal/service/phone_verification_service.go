@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/phoneverification"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/registry"
+	"github.com/LydiaTrack/ground/pkg/sms"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PhoneVerificationService verifies the phone numbers of the users with one-time codes sent by SMS
+type PhoneVerificationService struct {
+	phoneVerificationRepository PhoneVerificationRepository
+	userService                 UserService
+	smsSender                   sms.SMSSender
+}
+
+func NewPhoneVerificationService(phoneVerificationRepository PhoneVerificationRepository, userService UserService, smsSender sms.SMSSender) *PhoneVerificationService {
+	return &PhoneVerificationService{
+		phoneVerificationRepository: phoneVerificationRepository,
+		userService:                 userService,
+		smsSender:                   smsSender,
+	}
+}
+
+type PhoneVerificationRepository interface {
+	// GetByUserID gets the verification of the phone number of a user
+	GetByUserID(userID primitive.ObjectID) (phoneverification.Model, error)
+	// ReserveSend counts the send of a new code to the phone number of a user and stores the hash of the code, unless
+	// the resend limits are reached. It returns the verification the send was counted for, mongo.ErrNoDocuments if a
+	// limit was reached.
+	ReserveSend(userID primitive.ObjectID, phoneNumber, codeHash string, now time.Time) (phoneverification.Model, error)
+	// ReserveAttempt counts an attempt to enter the code of the verification and returns the verification it was
+	// counted for, mongo.ErrNoDocuments if the attempts were already used up
+	ReserveAttempt(id primitive.ObjectID) (phoneverification.Model, error)
+	// DeleteByUserID deletes the verification of the phone number of a user
+	DeleteByUserID(userID primitive.ObjectID) error
+}
+
+// SendCode sends a new verification code to the phone number of the current user. Resends are limited by
+// phoneverification.ResendCooldown and phoneverification.MaxSendsPerWindow.
+func (s PhoneVerificationService) SendCode(authContext auth.PermissionContext) (phoneverification.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfUpdatePermission) != nil {
+		return phoneverification.Model{}, constants.ErrorPermissionDenied
+	}
+
+	userModel, err := s.userService.userRepository.GetByID(context.Background(), *authContext.UserID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return phoneverification.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return phoneverification.Model{}, constants.ErrorInternalServerError
+	}
+	phoneNumber := userModel.ContactInfo.PhoneE164()
+	if phoneNumber == "" {
+		return phoneverification.Model{}, phoneverification.ErrNoPhoneNumber
+	}
+	if userModel.PhoneVerified {
+		return phoneverification.Model{}, phoneverification.ErrPhoneAlreadyVerified
+	}
+
+	code, err := utils.Generate6DigitCode(false)
+	if err != nil {
+		return phoneverification.Model{}, constants.ErrorInternalServerError
+	}
+	// The send is counted in the update checking the limits and before the message is sent, so neither concurrent
+	// nor failing sends can exceed them
+	now := time.Now()
+	verification, err := s.phoneVerificationRepository.ReserveSend(userModel.ID, phoneNumber, hashPhoneCode(userModel.ID, code), now)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return phoneverification.Model{}, s.resendError(userModel.ID, now)
+	}
+	if err != nil {
+		return phoneverification.Model{}, constants.ErrorInternalServerError
+	}
+
+	message := fmt.Sprintf("Your verification code is %s. It expires in %d minutes.", code, int(phoneverification.CodeLifetime.Minutes()))
+	if err := s.sender().Send(context.Background(), phoneNumber, message); err != nil {
+		log.LogError("Failed to send the phone verification code of user %s: %v", userModel.ID.Hex(), err)
+		return phoneverification.Model{}, constants.ErrorInternalServerError
+	}
+	return verification, nil
+}
+
+// Verify checks the code sent to the phone number of the current user and marks the phone number as verified
+func (s PhoneVerificationService) Verify(command phoneverification.VerifyPhoneCommand, authContext auth.PermissionContext) (user.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfUpdatePermission) != nil {
+		return user.Model{}, constants.ErrorPermissionDenied
+	}
+	if err := command.Validate(); err != nil {
+		return user.Model{}, fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+
+	userID := *authContext.UserID
+	verification, err := s.phoneVerificationRepository.GetByUserID(userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.Model{}, phoneverification.ErrNoPendingVerification
+	}
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	if err := verification.CheckVerifiable(time.Now()); err != nil {
+		return user.Model{}, err
+	}
+
+	userModel, err := s.userService.userRepository.GetByID(context.Background(), userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	if userModel.ContactInfo.PhoneE164() != verification.PhoneNumber {
+		return user.Model{}, phoneverification.ErrPhoneNumberChanged
+	}
+
+	// The attempt is counted before the code is compared, so concurrent requests cannot try more codes than allowed
+	verification, err = s.phoneVerificationRepository.ReserveAttempt(verification.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.Model{}, phoneverification.ErrTooManyAttempts
+	}
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	codeHash := hashPhoneCode(userID, command.Code)
+	if subtle.ConstantTimeCompare([]byte(codeHash), []byte(verification.CodeHash)) != 1 {
+		return user.Model{}, phoneverification.ErrCodeInvalid
+	}
+
+	if _, err = s.userService.userRepository.UpdateFields(context.Background(), userID, bson.M{"phoneVerified": true}, nil); err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	if err = s.phoneVerificationRepository.DeleteByUserID(userID); err != nil {
+		log.LogError("Failed to delete the phone verification of user %s: %v", userID.Hex(), err)
+	}
+
+	updatedUser, err := s.userService.userRepository.GetByID(context.Background(), userID)
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	return s.userService.SelfView(updatedUser), nil
+}
+
+// CleanupUser deletes the pending phone verification of a purged user
+func (s PhoneVerificationService) CleanupUser(userID primitive.ObjectID) error {
+	return s.phoneVerificationRepository.DeleteByUserID(userID)
+}
+
+// resendError tells which resend limit refused a send, the one of a concurrent send if none is reached anymore
+func (s PhoneVerificationService) resendError(userID primitive.ObjectID, now time.Time) error {
+	verification, err := s.phoneVerificationRepository.GetByUserID(userID)
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+	if err := verification.CheckResend(now); err != nil {
+		return err
+	}
+	return phoneverification.ErrResendTooSoon
+}
+
+// sender returns the sender registered by the host application, or the one configured by the environment
+func (s PhoneVerificationService) sender() sms.SMSSender {
+	if sender := registry.GetSMSSender(); sender != nil {
+		return sender
+	}
+	return s.smsSender
+}
+
+// hashPhoneCode hashes a verification code with the ID of its user, so equal codes of different users differ
+func hashPhoneCode(userID primitive.ObjectID, code string) string {
+	return hashToken(userID.Hex() + ":" + code)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"os"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 	"github.com/LydiaTrack/ground/pkg/domain/email"
 	"github.com/LydiaTrack/ground/pkg/domain/resetPassword"
 	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
 	"github.com/LydiaTrack/ground/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -51,6 +53,20 @@ type ResetPasswordRepository interface {
 	DeleteResetPassword(id primitive.ObjectID) error
 	// DeleteResetPasswordByCode deletes a resetPassword by code
 	DeleteResetPasswordByCode(code string) error
+	// DeleteResetPasswordsByEmail deletes every resetPassword of an email
+	DeleteResetPasswordsByEmail(email string) error
+}
+
+// CleanupUser deletes the reset password codes of a purged user
+func (s ResetPasswordService) CleanupUser(userID primitive.ObjectID) error {
+	userModel, err := s.userService.userRepository.GetByID(repository.IncludeDeleted(context.Background()), userID)
+	if err != nil {
+		return err
+	}
+	if userModel.ContactInfo.Email == "" {
+		return nil
+	}
+	return s.resetPasswordRepository.DeleteResetPasswordsByEmail(userModel.ContactInfo.Email)
 }
 
 func (s ResetPasswordService) createResetPassword(cmd resetPassword.SendResetPasswordCodeCommand) (resetPassword.Model, error) {
@@ -2,15 +2,21 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/LydiaTrack/ground/pkg/responses"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/LydiaTrack/ground/internal/permissions"
 	"github.com/LydiaTrack/ground/pkg/auth"
 	"github.com/LydiaTrack/ground/pkg/constants"
 	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"github.com/LydiaTrack/ground/pkg/mongodb/patch"
 	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/utils"
 )
 
 var roleSearchFields = []string{"name", "info"}
@@ -31,6 +37,16 @@ type RoleRepository interface {
 	ExistsByName(name string) bool
 	// GetRoleByName gets a role by name
 	GetRoleByName(name string) (role.Model, error)
+	// CountRoleHolders counts the users, groups and organization memberships that hold the role
+	CountRoleHolders(roleID primitive.ObjectID) (int64, error)
+	// RemoveRoleFromHolders removes the role and its assignment records from every user, group and membership
+	RemoveRoleFromHolders(roleID primitive.ObjectID) error
+	// CountChildRoles counts the roles that inherit from the role
+	CountChildRoles(roleID primitive.ObjectID) (int64, error)
+	// RemoveParentFromRoles removes the role from the parents of every role that inherits from it
+	RemoveParentFromRoles(roleID primitive.ObjectID) error
+	// UpdateFields sets and unsets the fields of a role
+	UpdateFields(ctx context.Context, id interface{}, set bson.M, unset []string) (*mongo.UpdateResult, error)
 }
 
 func (s RoleService) Create(command role.CreateRoleCommand, authContext auth.PermissionContext) (role.Model, error) {
@@ -45,6 +61,7 @@ func (s RoleService) Create(command role.CreateRoleCommand, authContext auth.Per
 		role.WithPermissions(command.Permissions),
 		role.WithTags(command.Tags),
 		role.WithInfo(command.Info),
+		role.WithParentIDs(command.ParentIDs),
 	)
 
 	if err != nil {
@@ -55,6 +72,10 @@ func (s RoleService) Create(command role.CreateRoleCommand, authContext auth.Per
 		return role.Model{}, constants.ErrorBadRequest
 	}
 
+	if err := s.validateParents(roleModel.ID, roleModel.ParentIDs); err != nil {
+		return role.Model{}, err
+	}
+
 	roleExists := s.roleRepository.ExistsByName(roleModel.Name)
 
 	if roleExists {
@@ -111,6 +132,19 @@ func (s RoleService) QueryPaginated(searchText string, page int, limit int, auth
 	return roles, nil
 }
 
+// QueryCursor queries the roles by an optional search text with keyset pagination
+func (s RoleService) QueryCursor(searchText string, request repository.CursorRequest, authContext auth.PermissionContext) (responses.CursorResult[role.Model], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.RoleReadPermission) != nil {
+		return responses.CursorResult[role.Model]{}, constants.ErrorPermissionDenied
+	}
+
+	roles, err := s.roleRepository.QueryCursor(context.Background(), nil, roleSearchFields, searchText, request)
+	if err != nil {
+		return responses.CursorResult[role.Model]{}, cursorError(err)
+	}
+	return roles, nil
+}
+
 // Exists checks if a user exists by ID
 func (s RoleService) Exists(id string, authContext auth.PermissionContext) (bool, error) {
 	if auth.CheckPermission(authContext.Permissions, permissions.RoleReadPermission) != nil {
@@ -130,12 +164,56 @@ func (s RoleService) Exists(id string, authContext auth.PermissionContext) (bool
 	return exists, nil
 }
 
+// Delete deletes a role by ID. It refuses with a conflict if users, groups or organization memberships still hold
+// the role or other roles inherit from it.
 func (s RoleService) Delete(id string, authContext auth.PermissionContext) error {
 	if auth.CheckPermission(authContext.Permissions, permissions.RoleDeletePermission) != nil {
 		return constants.ErrorPermissionDenied
 	}
 
-	_, err := s.roleRepository.Delete(context.Background(), id)
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return constants.ErrorBadRequest
+	}
+
+	holderCount, err := s.roleRepository.CountRoleHolders(objID)
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+	childCount, err := s.roleRepository.CountChildRoles(objID)
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+	if holderCount > 0 || childCount > 0 {
+		return constants.ErrorConflict
+	}
+
+	_, err = s.roleRepository.Delete(context.Background(), objID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteCascade deletes a role by ID after removing it from its holders and from the parents of its child roles
+func (s RoleService) DeleteCascade(id string, authContext auth.PermissionContext) error {
+	if auth.CheckPermission(authContext.Permissions, permissions.RoleDeletePermission) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return constants.ErrorBadRequest
+	}
+
+	if err = s.roleRepository.RemoveRoleFromHolders(objID); err != nil {
+		return constants.ErrorInternalServerError
+	}
+	if err = s.roleRepository.RemoveParentFromRoles(objID); err != nil {
+		return constants.ErrorInternalServerError
+	}
+
+	_, err = s.roleRepository.Delete(context.Background(), objID)
 	if err != nil {
 		return err
 	}
@@ -174,7 +252,34 @@ func (s RoleService) UpdateRole(id string, command role.UpdateRoleCommand, authC
 		return role.Model{}, constants.ErrorNotFound
 	}
 
-	_, err = s.roleRepository.Update(context.Background(), id, command)
+	if err := command.Validate(); err != nil {
+		return role.Model{}, constants.ErrorBadRequest
+	}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return role.Model{}, constants.ErrorBadRequest
+	}
+
+	// The new name must not belong to another role
+	if sameNameRole, err := s.roleRepository.GetRoleByName(command.Name); err == nil && sameNameRole.ID != objID {
+		return role.Model{}, constants.ErrorConflict
+	}
+
+	if err := s.validateParents(objID, command.ParentIDs); err != nil {
+		return role.Model{}, err
+	}
+
+	set, err := utils.GenerateUpdateDocument(command)
+	if err != nil {
+		return role.Model{}, constants.ErrorInternalServerError
+	}
+	// Omitted parents are kept, an empty list removes them in the same update
+	var unset []string
+	if command.ParentIDs != nil && len(command.ParentIDs) == 0 {
+		unset = append(unset, "parentIds")
+	}
+	_, err = s.roleRepository.UpdateFields(versionContext(command.ExpectedVersion), objID, set, unset)
 	if err != nil {
 		return role.Model{}, err
 	}
@@ -185,3 +290,347 @@ func (s RoleService) UpdateRole(id string, command role.UpdateRoleCommand, authC
 	}
 	return roleAfterUpdate, nil
 }
+
+// PatchRole applies a merge patch or a JSON patch to a role, only role.PatchableFields can be changed.
+// The patched role is validated like an updated one before it is written.
+func (s RoleService) PatchRole(id string, command role.PatchRoleCommand, authContext auth.PermissionContext) (role.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.RoleUpdatePermission) != nil {
+		return role.Model{}, constants.ErrorPermissionDenied
+	}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return role.Model{}, constants.ErrorBadRequest
+	}
+
+	currentRole, err := s.roleRepository.GetByID(context.Background(), objID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return role.Model{}, constants.ErrorNotFound
+	}
+	if err != nil {
+		return role.Model{}, constants.ErrorInternalServerError
+	}
+
+	var patchedRole role.Model
+	update, err := patch.Apply(command.ContentType, command.Patch, currentRole, &patchedRole, role.PatchableFields)
+	if err != nil {
+		return role.Model{}, patchError(err)
+	}
+	if update.IsEmpty() {
+		return currentRole, nil
+	}
+
+	if err := (role.UpdateRoleCommand{Name: patchedRole.Name}).Validate(); err != nil {
+		return role.Model{}, fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+	if sameNameRole, err := s.roleRepository.GetRoleByName(patchedRole.Name); err == nil && sameNameRole.ID != objID {
+		return role.Model{}, constants.ErrorConflict
+	}
+	if err := s.validateParents(objID, patchedRole.ParentIDs); err != nil {
+		return role.Model{}, err
+	}
+
+	if _, err := s.roleRepository.UpdateFields(versionContext(command.ExpectedVersion), objID, update.Set, update.Unset); err != nil {
+		return role.Model{}, err
+	}
+
+	return s.roleRepository.GetByID(context.Background(), objID)
+}
+
+// GetEffective retrieves a role together with the permissions it inherits from its ancestors
+func (s RoleService) GetEffective(id string, authContext auth.PermissionContext) (role.EffectiveModel, error) {
+	roleModel, err := s.Get(id, authContext)
+	if err != nil {
+		return role.EffectiveModel{}, err
+	}
+
+	resolvedRoles, err := s.ResolveRoles([]primitive.ObjectID{roleModel.ID}, authContext)
+	if err != nil {
+		return role.EffectiveModel{}, err
+	}
+
+	ancestorIDs := []primitive.ObjectID{}
+	for _, resolvedRole := range resolvedRoles {
+		if resolvedRole.ID != roleModel.ID {
+			ancestorIDs = append(ancestorIDs, resolvedRole.ID)
+		}
+	}
+
+	return role.EffectiveModel{
+		Model:                roleModel,
+		EffectivePermissions: role.MergePermissions(resolvedRoles),
+		AncestorIDs:          ancestorIDs,
+	}, nil
+}
+
+// ResolveRoles retrieves the roles with the given IDs together with all of their ancestors.
+// Every role is returned once, so cycles in the hierarchy cannot cause an endless resolution.
+func (s RoleService) ResolveRoles(roleIDs []primitive.ObjectID, authContext auth.PermissionContext) ([]role.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.RoleReadPermission) != nil {
+		return nil, constants.ErrorPermissionDenied
+	}
+
+	visited := make(map[primitive.ObjectID]bool)
+	resolvedRoles := []role.Model{}
+	pending := roleIDs
+	for len(pending) > 0 {
+		var unvisited []primitive.ObjectID
+		for _, id := range pending {
+			if !visited[id] {
+				visited[id] = true
+				unvisited = append(unvisited, id)
+			}
+		}
+		if len(unvisited) == 0 {
+			break
+		}
+
+		roles, err := s.roleRepository.Query(context.Background(), bson.M{"_id": bson.M{"$in": unvisited}}, nil, "")
+		if err != nil {
+			return nil, constants.ErrorInternalServerError
+		}
+
+		pending = nil
+		for _, roleModel := range roles.Data {
+			resolvedRoles = append(resolvedRoles, roleModel)
+			pending = append(pending, roleModel.ParentIDs...)
+		}
+	}
+
+	return resolvedRoles, nil
+}
+
+// CheckAssignable checks that every role exists and that the caller may grant it, either with the role assign
+// permission or by holding every permission of the role, including the inherited ones
+func (s RoleService) CheckAssignable(roleIDs []primitive.ObjectID, authContext auth.PermissionContext) error {
+	canAssignAny := auth.HasPermission(authContext.Permissions, permissions.RoleAssignPermission)
+	for _, roleID := range roleIDs {
+		resolvedRoles, err := s.ResolveRoles([]primitive.ObjectID{roleID}, auth.CreateAdminAuthContext())
+		if err != nil {
+			return err
+		}
+		if len(resolvedRoles) == 0 || resolvedRoles[0].ID != roleID {
+			return constants.ErrorNotFound
+		}
+		if canAssignAny {
+			continue
+		}
+		for _, permission := range role.MergePermissions(resolvedRoles) {
+			if !auth.HasPermission(authContext.Permissions, permission) {
+				return fmt.Errorf("%w: role %s grants %s/%s", constants.ErrorPermissionDenied, resolvedRoles[0].Name,
+					permission.Domain, permission.Action)
+			}
+		}
+	}
+	return nil
+}
+
+// validateParents checks that every parent exists and that roleID is not an ancestor of its parents
+func (s RoleService) validateParents(roleID primitive.ObjectID, parentIDs []primitive.ObjectID) error {
+	if len(parentIDs) == 0 {
+		return nil
+	}
+
+	for _, parentID := range parentIDs {
+		if parentID == roleID {
+			return constants.ErrorBadRequest
+		}
+		exists, err := s.roleRepository.ExistsByID(context.Background(), parentID)
+		if err != nil {
+			return constants.ErrorInternalServerError
+		}
+		if !exists {
+			return constants.ErrorNotFound
+		}
+	}
+
+	ancestors, err := s.ResolveRoles(parentIDs, auth.CreateAdminAuthContext())
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.ID == roleID {
+			// The role would inherit from itself
+			return constants.ErrorBadRequest
+		}
+	}
+
+	return nil
+}
+
+// Reconcile brings the stored roles in line with the manifest. Missing roles are created and changed ones are updated.
+// When the manifest prunes, roles that are not declared are deleted unless they are kept or still in use.
+func (s RoleService) Reconcile(manifest role.Manifest, authContext auth.PermissionContext) (role.ReconcileResult, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.RoleCreatePermission) != nil ||
+		auth.CheckPermission(authContext.Permissions, permissions.RoleUpdatePermission) != nil {
+		return role.ReconcileResult{}, constants.ErrorPermissionDenied
+	}
+	if manifest.Prune && auth.CheckPermission(authContext.Permissions, permissions.RoleDeletePermission) != nil {
+		return role.ReconcileResult{}, constants.ErrorPermissionDenied
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return role.ReconcileResult{}, constants.ErrorBadRequest
+	}
+
+	result := role.ReconcileResult{}
+	changesByName := make(map[string]*role.RoleChanges)
+	storedRoles := make(map[string]role.Model)
+
+	// Roles are created first, so parents can be referenced regardless of their order in the manifest
+	for _, manifestRole := range manifest.Roles {
+		if !s.roleRepository.ExistsByName(manifestRole.Name) {
+			createdRole, err := s.Create(role.CreateRoleCommand{
+				Name:        manifestRole.Name,
+				Info:        manifestRole.Info,
+				Tags:        manifestRole.Tags,
+				Permissions: manifestRole.Permissions,
+			}, authContext)
+			if err != nil {
+				return result, err
+			}
+			storedRoles[manifestRole.Name] = createdRole
+			result.Created = append(result.Created, manifestRole.Name)
+			continue
+		}
+
+		storedRole, err := s.roleRepository.GetRoleByName(manifestRole.Name)
+		if err != nil {
+			return result, constants.ErrorInternalServerError
+		}
+
+		changes := role.RoleChanges{
+			Name:        manifestRole.Name,
+			InfoChanged: storedRole.Info != manifestRole.Info,
+			TagsChanged: !role.SameStrings(storedRole.Tags, manifestRole.Tags),
+		}
+		changes.AddedPermissions, changes.RemovedPermissions = role.DiffPermissions(storedRole.Permissions, manifestRole.Permissions)
+		if changes.HasChanges() {
+			desiredRole := storedRole
+			desiredRole.Info = manifestRole.Info
+			desiredRole.Tags = manifestRole.Tags
+			desiredRole.Permissions = manifestRole.Permissions
+			updatedRole, err := s.writeRoleState(desiredRole)
+			if err != nil {
+				return result, err
+			}
+			storedRole = updatedRole
+			changesByName[manifestRole.Name] = &changes
+		}
+		storedRoles[manifestRole.Name] = storedRole
+	}
+
+	// Parents are only managed for the roles that declare them, an empty list removes them
+	for _, manifestRole := range manifest.Roles {
+		if manifestRole.Parents == nil {
+			continue
+		}
+		storedRole := storedRoles[manifestRole.Name]
+
+		parentIDs := make([]primitive.ObjectID, 0, len(manifestRole.Parents))
+		parentHexes := make([]string, 0, len(manifestRole.Parents))
+		for _, parentName := range manifestRole.Parents {
+			parentIDs = append(parentIDs, storedRoles[parentName].ID)
+			parentHexes = append(parentHexes, storedRoles[parentName].ID.Hex())
+		}
+		storedHexes := make([]string, 0, len(storedRole.ParentIDs))
+		for _, parentID := range storedRole.ParentIDs {
+			storedHexes = append(storedHexes, parentID.Hex())
+		}
+		if role.SameStrings(storedHexes, parentHexes) {
+			continue
+		}
+
+		if err := s.validateParents(storedRole.ID, parentIDs); err != nil {
+			return result, err
+		}
+		storedRole.ParentIDs = parentIDs
+		if _, err := s.writeRoleState(storedRole); err != nil {
+			return result, err
+		}
+
+		// Newly created roles are already reported as created
+		if _, ok := changesByName[manifestRole.Name]; !ok && !containsString(result.Created, manifestRole.Name) {
+			changesByName[manifestRole.Name] = &role.RoleChanges{Name: manifestRole.Name}
+		}
+		if changes, ok := changesByName[manifestRole.Name]; ok {
+			changes.ParentsChanged = true
+		}
+	}
+
+	for _, manifestRole := range manifest.Roles {
+		if changes, ok := changesByName[manifestRole.Name]; ok {
+			result.Updated = append(result.Updated, *changes)
+		}
+	}
+
+	if !manifest.Prune {
+		return result, nil
+	}
+
+	allRoles, err := s.roleRepository.Query(context.Background(), nil, nil, "")
+	if err != nil {
+		return result, constants.ErrorInternalServerError
+	}
+	for _, storedRole := range allRoles.Data {
+		if _, declared := storedRoles[storedRole.Name]; declared || containsString(manifest.Keep, storedRole.Name) {
+			continue
+		}
+
+		err := s.Delete(storedRole.ID.Hex(), authContext)
+		if errors.Is(err, constants.ErrorConflict) {
+			result.Skipped = append(result.Skipped, storedRole.Name)
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Deleted = append(result.Deleted, storedRole.Name)
+	}
+
+	return result, nil
+}
+
+// writeRoleState writes the info, tags, permissions and parents of a role as they are, the empty ones are removed
+// instead of being skipped like in UpdateRole, so a manifest can clear them
+func (s RoleService) writeRoleState(roleModel role.Model) (role.Model, error) {
+	permissions := roleModel.Permissions
+	if permissions == nil {
+		permissions = []auth.Permission{}
+	}
+	set := bson.M{"permissions": permissions}
+	var unset []string
+	if roleModel.Info != "" {
+		set["info"] = roleModel.Info
+	} else {
+		unset = append(unset, "info")
+	}
+	if len(roleModel.Tags) > 0 {
+		set["tags"] = roleModel.Tags
+	} else {
+		unset = append(unset, "tags")
+	}
+	if len(roleModel.ParentIDs) > 0 {
+		set["parentIds"] = roleModel.ParentIDs
+	} else {
+		unset = append(unset, "parentIds")
+	}
+
+	if _, err := s.roleRepository.UpdateFields(context.Background(), roleModel.ID, set, unset); err != nil {
+		return role.Model{}, constants.ErrorInternalServerError
+	}
+	updatedRole, err := s.roleRepository.GetByID(context.Background(), roleModel.ID)
+	if err != nil {
+		return role.Model{}, constants.ErrorInternalServerError
+	}
+	return updatedRole, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
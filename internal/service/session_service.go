@@ -1,9 +1,11 @@
 package service
 
 import (
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/LydiaTrack/ground/pkg/constants"
 	"github.com/LydiaTrack/ground/pkg/domain/session"
@@ -54,10 +56,11 @@ func (s SessionService) CreateSession(cmd session.CreateSessionCommand) (session
 	}
 	// TODO add a date field to apply TTL
 	sessionInfo := session.InfoModel{
-		ID:           primitive.NewObjectID(),
-		UserID:       userID,
-		ExpireTime:   cmd.ExpireTime,
-		RefreshToken: cmd.RefreshToken,
+		ID:             primitive.NewObjectID(),
+		UserID:         userID,
+		ExpireTime:     cmd.ExpireTime,
+		RefreshToken:   cmd.RefreshToken,
+		OrganizationID: cmd.OrganizationID,
 	}
 	// TODO: Permission check
 	return s.sessionRepository.SaveSession(sessionInfo)
@@ -90,6 +93,24 @@ func (s SessionService) DeleteSessionByUser(userID string) error {
 	return s.sessionRepository.DeleteSessionByUserID(objID)
 }
 
+// CleanupUser deletes the session of a purged user
+func (s SessionService) CleanupUser(userID primitive.ObjectID) error {
+	return s.sessionRepository.DeleteSessionByUserID(userID)
+}
+
+// ExportUserData exports the sessions of a user for the data export, without their refresh tokens
+func (s SessionService) ExportUserData(userID primitive.ObjectID) (interface{}, error) {
+	sessionModel, err := s.sessionRepository.GetUserSession(userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return []session.InfoModel{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sessionModel.RefreshToken = ""
+	return []session.InfoModel{sessionModel}, nil
+}
+
 // DeleteSessionByID is a function that deletes a session by id
 func (s SessionService) DeleteSessionByID(sessionID string) error {
 	objID, err := primitive.ObjectIDFromHex(sessionID)
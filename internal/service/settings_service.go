@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/settings"
+	"github.com/LydiaTrack/ground/pkg/registry"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SettingsService stores the preferences of the users, such as the theme or the notification choices, by the
+// namespaces registered with registry.RegisterUserSettings. They are kept apart from the user and its properties.
+type SettingsService struct {
+	settingsRepository SettingsRepository
+}
+
+func NewSettingsService(settingsRepository SettingsRepository) *SettingsService {
+	return &SettingsService{
+		settingsRepository: settingsRepository,
+	}
+}
+
+type SettingsRepository interface {
+	// GetByUserID gets the settings of a user
+	GetByUserID(userID primitive.ObjectID) (settings.Model, error)
+	// UpdateValues sets and unsets keys of a namespace of the settings of a user, only the given keys are written
+	UpdateValues(userID primitive.ObjectID, namespace string, set map[string]interface{}, unset []string) error
+	// DeleteByUserID deletes the settings of a user
+	DeleteByUserID(userID primitive.ObjectID) error
+}
+
+// GetSelf retrieves a namespace of the settings of the current user
+func (s SettingsService) GetSelf(namespace string, authContext auth.PermissionContext) (settings.Settings, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfGetPermission) != nil {
+		return settings.Settings{}, constants.ErrorPermissionDenied
+	}
+	return s.Get(*authContext.UserID, namespace)
+}
+
+// UpdateSelf sets the given keys of a namespace of the settings of the current user
+func (s SettingsService) UpdateSelf(namespace string, command settings.UpdateSettingsCommand, authContext auth.PermissionContext) (settings.Settings, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfUpdatePermission) != nil {
+		return settings.Settings{}, constants.ErrorPermissionDenied
+	}
+	return s.Update(*authContext.UserID, namespace, command)
+}
+
+// Get retrieves a namespace of the settings of a user, for host applications acting on behalf of the user
+func (s SettingsService) Get(userID primitive.ObjectID, namespace string) (settings.Settings, error) {
+	registered, ok := registry.GetUserSettingsNamespace(namespace)
+	if !ok {
+		return settings.Settings{}, settings.ErrNamespaceNotFound
+	}
+
+	settingsModel, err := s.settingsRepository.GetByUserID(userID)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return settings.Settings{}, constants.ErrorInternalServerError
+	}
+	return settings.Settings{
+		Namespace: namespace,
+		Values:    registered.Resolve(settingsModel.Namespaces[namespace]),
+	}, nil
+}
+
+// Update sets the given keys of a namespace of the settings of a user, for host applications acting on behalf of
+// the user. Null values reset the keys to their defaults.
+func (s SettingsService) Update(userID primitive.ObjectID, namespace string, command settings.UpdateSettingsCommand) (settings.Settings, error) {
+	registered, ok := registry.GetUserSettingsNamespace(namespace)
+	if !ok {
+		return settings.Settings{}, settings.ErrNamespaceNotFound
+	}
+	if err := command.Validate(); err != nil {
+		return settings.Settings{}, fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+	if err := registered.Validate(command.Values); err != nil {
+		return settings.Settings{}, fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+
+	set := map[string]interface{}{}
+	var unset []string
+	for key, value := range command.Values {
+		if value == nil {
+			unset = append(unset, key)
+		} else {
+			set[key] = value
+		}
+	}
+	if err := s.settingsRepository.UpdateValues(userID, namespace, set, unset); err != nil {
+		return settings.Settings{}, constants.ErrorInternalServerError
+	}
+	return s.Get(userID, namespace)
+}
+
+// ExportUserData returns the settings a user has set for the export of the user
+func (s SettingsService) ExportUserData(userID primitive.ObjectID) (interface{}, error) {
+	settingsModel, err := s.settingsRepository.GetByUserID(userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return map[string]map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return settingsModel.Values(), nil
+}
+
+// CleanupUser deletes the settings of a purged user
+func (s SettingsService) CleanupUser(userID primitive.ObjectID) error {
+	return s.settingsRepository.DeleteByUserID(userID)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultUsernameReservationPeriodInHour is the default period a released username stays reserved for its user
+const defaultUsernameReservationPeriodInHour = 720
+
+type IdentityChangeRepository interface {
+	repository.Repository[user.IdentityChange]
+	// IsReserved checks if the value of the field was released by another user than exceptUserID and is still reserved
+	IsReserved(field user.IdentityField, value string, exceptUserID *primitive.ObjectID, now time.Time) (bool, error)
+	// GetByUserID retrieves the changes of a user, the latest first
+	GetByUserID(userID primitive.ObjectID) ([]user.IdentityChange, error)
+	// DeleteByUserID deletes the changes of a user, which releases their reservations
+	DeleteByUserID(userID primitive.ObjectID) error
+}
+
+// GetUsernameReservationPeriod returns the period a released username stays reserved for the user who released it,
+// set by USERNAME_RESERVATION_PERIOD_IN_HOUR. Zero disables the reservations.
+func GetUsernameReservationPeriod() time.Duration {
+	reservationInHour, err := strconv.Atoi(os.Getenv("USERNAME_RESERVATION_PERIOD_IN_HOUR"))
+	if err != nil || reservationInHour < 0 {
+		reservationInHour = defaultUsernameReservationPeriodInHour
+	}
+	return time.Duration(reservationInHour) * time.Hour
+}
+
+// GetIdentityHistory retrieves the username and email changes of a user, the latest first
+func (s UserService) GetIdentityHistory(id string, authContext auth.PermissionContext) ([]user.IdentityChange, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserIdentityHistoryReadPermission) != nil {
+		return nil, constants.ErrorPermissionDenied
+	}
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, constants.ErrorBadRequest
+	}
+	exists, err := s.Exists(id)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, constants.ErrorNotFound
+	}
+	return s.identityHistory(objID)
+}
+
+// ExportIdentityHistory returns the username and email changes of a user for the export of the user
+func (s UserService) ExportIdentityHistory(userID primitive.ObjectID) (interface{}, error) {
+	return s.identityHistory(userID)
+}
+
+// DeleteIdentityHistory deletes the username and email changes of a purged user, which releases their reservations
+func (s UserService) DeleteIdentityHistory(userID primitive.ObjectID) error {
+	if s.identityChangeRepository == nil {
+		return nil
+	}
+	return s.identityChangeRepository.DeleteByUserID(userID)
+}
+
+func (s UserService) identityHistory(userID primitive.ObjectID) ([]user.IdentityChange, error) {
+	if s.identityChangeRepository == nil {
+		return []user.IdentityChange{}, nil
+	}
+	changes, err := s.identityChangeRepository.GetByUserID(userID)
+	if err != nil {
+		return nil, constants.ErrorInternalServerError
+	}
+	if changes == nil {
+		changes = []user.IdentityChange{}
+	}
+	return changes, nil
+}
+
+// isUsernameReserved checks if the username was released by another user than exceptUserID within the reservation
+// period
+func (s UserService) isUsernameReserved(username string, exceptUserID *primitive.ObjectID) bool {
+	if s.identityChangeRepository == nil {
+		return false
+	}
+	reserved, err := s.identityChangeRepository.IsReserved(user.IdentityFieldUsername, username, exceptUserID, time.Now())
+	if err != nil {
+		log.LogError("Failed to check the reservation of username %s: %v", username, err)
+		return false
+	}
+	return reserved
+}
+
+// checkIdentityAvailable checks that the new username and email of a user are neither taken by nor reserved for
+// another user. A user can take back the usernames they released.
+func (s UserService) checkIdentityAvailable(current user.Model, username, email string) error {
+	if username != "" && username != current.Username &&
+		(s.userRepository.ExistsByUsername(username) || s.isUsernameReserved(username, &current.ID)) {
+		return fmt.Errorf("%w: username is already taken", constants.ErrorConflict)
+	}
+	if email != "" && email != current.ContactInfo.Email && s.userRepository.ExistsByEmail(email) {
+		return fmt.Errorf("%w: email is already taken", constants.ErrorConflict)
+	}
+	return nil
+}
+
+// recordIdentityChanges records the username and email changes between two states of a user. The update is already
+// written, so failures are only logged.
+func (s UserService) recordIdentityChanges(before, after user.Model, changedBy *primitive.ObjectID) {
+	if s.identityChangeRepository == nil {
+		return
+	}
+	for _, change := range user.IdentityChanges(before, after, changedBy, time.Now(), GetUsernameReservationPeriod()) {
+		if _, err := s.identityChangeRepository.Create(context.Background(), change); err != nil {
+			log.LogError("Failed to record the %s change of user %s: %v", change.Field, before.ID.Hex(), err)
+		}
+	}
+}
+
+// commandEmail returns the email an update command sets, empty if it keeps the email
+func commandEmail(command user.UpdateUserCommand) string {
+	if command.ContactInfo == nil {
+		return ""
+	}
+	return command.ContactInfo.Email
+}
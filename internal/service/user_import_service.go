@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/invitation"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// UserImportService creates and updates users in bulk and exports them
+type UserImportService struct {
+	userService       UserService
+	roleService       RoleService
+	invitationService InvitationService
+}
+
+func NewUserImportService(userService UserService, roleService RoleService, invitationService InvitationService) *UserImportService {
+	return &UserImportService{
+		userService:       userService,
+		roleService:       roleService,
+		invitationService: invitationService,
+	}
+}
+
+// importRun holds the state shared by the rows of an import
+type importRun struct {
+	options        user.ImportOptions
+	authContext    auth.PermissionContext
+	defaultRoleIDs []primitive.ObjectID
+	roleIDs        map[string]primitive.ObjectID
+	seen           map[string]int
+}
+
+// Import creates or updates a user for every row, matching existing users by email or username.
+// New users get the default roles once per import in addition to the roles of their row.
+// A failing row is reported and does not stop the import.
+func (s UserImportService) Import(format user.ImportFormat, reader io.Reader, options user.ImportOptions, authContext auth.PermissionContext) (user.ImportReport, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserImportPermission) != nil {
+		return user.ImportReport{}, constants.ErrorPermissionDenied
+	}
+
+	if options.MatchBy == "" {
+		options.MatchBy = user.ImportMatchByEmail
+	}
+	if options.MatchBy != user.ImportMatchByEmail && options.MatchBy != user.ImportMatchByUsername {
+		return user.ImportReport{}, constants.ErrorBadRequest
+	}
+
+	rows, err := user.DecodeImportRows(format, reader)
+	if err != nil {
+		return user.ImportReport{}, fmt.Errorf("%w: %w", constants.ErrorBadRequest, err)
+	}
+
+	defaultRoleIDs, err := s.userService.getDefaultRoleIDs()
+	if err != nil {
+		return user.ImportReport{}, constants.ErrorInternalServerError
+	}
+
+	run := &importRun{
+		options:        options,
+		authContext:    authContext,
+		defaultRoleIDs: defaultRoleIDs,
+		roleIDs:        map[string]primitive.ObjectID{},
+		seen:           map[string]int{},
+	}
+	report := user.ImportReport{DryRun: options.DryRun, Rows: []user.ImportRowResult{}}
+	for _, row := range rows {
+		report.Add(s.importRow(run, row))
+	}
+
+	log.Log("Imported users, created: %d, updated: %d, invited: %d, failed: %d, dry run: %v",
+		report.Created, report.Updated, report.Invited, report.Failed, options.DryRun)
+	return report, nil
+}
+
+// importRow imports a single row
+func (s UserImportService) importRow(run *importRun, row user.ImportRow) user.ImportRowResult {
+	record := row.Record
+	record.Email = strings.ToLower(strings.TrimSpace(record.Email))
+	result := user.ImportRowResult{Row: row.Number, Username: record.Username, Email: record.Email}
+	fail := func(err error) user.ImportRowResult {
+		result.Action = user.ImportActionFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	if row.Error != nil {
+		return fail(row.Error)
+	}
+
+	matchValue := record.Email
+	if run.options.MatchBy == user.ImportMatchByUsername {
+		matchValue = record.Username
+	}
+	if matchValue == "" {
+		return fail(fmt.Errorf("%s is required", run.options.MatchBy))
+	}
+	if previous, ok := run.seen[matchValue]; ok {
+		return fail(fmt.Errorf("duplicate of row %d", previous))
+	}
+	run.seen[matchValue] = row.Number
+
+	roleIDs, err := s.resolveRoles(run, record.Roles)
+	if err != nil {
+		return fail(err)
+	}
+
+	existing, err := s.findExisting(run.options.MatchBy, matchValue)
+	if err != nil {
+		return fail(err)
+	}
+	if existing != nil {
+		result.UserID = &existing.ID
+		result.Action = user.ImportActionUpdated
+		if err := s.updateUser(run, *existing, record, roleIDs); err != nil {
+			return fail(err)
+		}
+		return result
+	}
+
+	if record.Password == "" && run.options.Invite {
+		result.Action = user.ImportActionInvited
+		if err := s.inviteUser(run, record, roleIDs); err != nil {
+			return fail(err)
+		}
+		return result
+	}
+
+	result.Action = user.ImportActionCreated
+	userID, err := s.createUser(run, record, roleIDs)
+	if err != nil {
+		return fail(err)
+	}
+	result.UserID = userID
+	return result
+}
+
+// createUser creates the user of a row with the default roles and the roles of the row, the roles are written with
+// the user so a created user always has them
+func (s UserImportService) createUser(run *importRun, record user.ImportRecord, roleIDs []primitive.ObjectID) (*primitive.ObjectID, error) {
+	allRoleIDs := append(append([]primitive.ObjectID{}, run.defaultRoleIDs...), roleIDs...)
+	userModel, err := user.NewUser(
+		user.WithUsername(record.Username),
+		user.WithPassword(record.Password),
+		user.WithContactInfo(user.ContactInfo{Email: record.Email}),
+		user.WithPersonInfo(importPersonInfo(record)),
+		user.WithProperties(record.Properties),
+		user.WithRoleAssignments(importAssignments(run, allRoleIDs)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err = userModel.Validate(); err != nil {
+		return nil, err
+	}
+	if err = validateProperties(userModel.Properties); err != nil {
+		return nil, err
+	}
+	if s.userService.userRepository.ExistsByUsernameAndEmail(userModel.Username, userModel.ContactInfo.Email) ||
+		s.userService.isUsernameReserved(userModel.Username, nil) {
+		return nil, errors.New("username or email is already taken")
+	}
+	if run.options.DryRun {
+		return nil, nil
+	}
+
+	if err = hashUserPassword(userModel); err != nil {
+		return nil, err
+	}
+	if _, err = s.userService.userRepository.Create(context.Background(), *userModel); err != nil {
+		return nil, err
+	}
+	if s.userService.userStatsService != nil {
+		if err = s.userService.userStatsService.CreateUserStats(userModel.ID, userModel.Username); err != nil {
+			log.Log("Warning: Failed to create stats for user %s: %v", userModel.ID.Hex(), err)
+		}
+	}
+	return &userModel.ID, nil
+}
+
+// updateUser updates the profile of an existing user with a row and adds the roles of the row
+func (s UserImportService) updateUser(run *importRun, existing user.Model, record user.ImportRecord, roleIDs []primitive.ObjectID) error {
+	updated := existing
+	if record.Username != "" {
+		updated.Username = record.Username
+	}
+	if record.Email != "" {
+		updated.ContactInfo.Email = record.Email
+	}
+	if personInfo := importPersonInfo(record); personInfo != nil {
+		updated.PersonInfo = personInfo
+	}
+	if record.Properties != nil {
+		updated.Properties = record.Properties
+	}
+	if record.Password != "" {
+		updated.Password = record.Password
+	}
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+	if record.Properties != nil {
+		if err := validateProperties(record.Properties); err != nil {
+			return err
+		}
+	}
+
+	if err := s.userService.checkIdentityAvailable(existing, updated.Username, updated.ContactInfo.Email); err != nil {
+		return err
+	}
+	if run.options.DryRun {
+		return nil
+	}
+
+	updateCmd := user.UpdateUserCommand{
+		Username:    updated.Username,
+		PersonInfo:  updated.PersonInfo,
+		ContactInfo: &updated.ContactInfo,
+		Properties:  record.Properties,
+	}
+	if _, err := s.userService.userRepository.Update(context.Background(), existing.ID, updateCmd); err != nil {
+		return err
+	}
+	s.userService.recordIdentityChanges(existing, updated, run.authContext.UserID)
+	if record.Password != "" {
+		if err := hashUserPassword(&updated); err != nil {
+			return err
+		}
+		if err := s.userService.userRepository.UpdateUserPassword(existing.ID, updated.Password); err != nil {
+			return err
+		}
+	}
+	return s.assignRoles(run, existing.ID, roleIDs)
+}
+
+// inviteUser invites the email of a row with the roles of the row instead of creating the user
+func (s UserImportService) inviteUser(run *importRun, record user.ImportRecord, roleIDs []primitive.ObjectID) error {
+	if record.Email == "" {
+		return errors.New("email is required to invite")
+	}
+	if run.options.DryRun {
+		invitationModel, err := invitation.NewInvitation(invitation.WithEmail(record.Email), invitation.WithRoleIDs(roleIDs))
+		if err != nil {
+			return err
+		}
+		return invitationModel.Validate()
+	}
+
+	_, err := s.invitationService.Create(invitation.CreateInvitationCommand{
+		Email:   record.Email,
+		RoleIDs: roleIDs,
+	}, run.authContext)
+	return err
+}
+
+// assignRoles grants the roles to an imported user
+func (s UserImportService) assignRoles(run *importRun, userID primitive.ObjectID, roleIDs []primitive.ObjectID) error {
+	for _, assignment := range importAssignments(run, roleIDs) {
+		if err := s.userService.userRepository.AddRole(userID, assignment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importAssignments returns the assignments of the roles granted by an import, each role is assigned once
+func importAssignments(run *importRun, roleIDs []primitive.ObjectID) []user.RoleAssignment {
+	assignments := make([]user.RoleAssignment, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		if slices.ContainsFunc(assignments, func(assignment user.RoleAssignment) bool { return assignment.RoleID == roleID }) {
+			continue
+		}
+		assignments = append(assignments, user.RoleAssignment{
+			RoleID:    roleID,
+			GrantedBy: run.authContext.UserID,
+			GrantedAt: time.Now(),
+			Reason:    "import",
+		})
+	}
+	return assignments
+}
+
+// resolveRoles resolves role names to IDs, each name is looked up once per import
+func (s UserImportService) resolveRoles(run *importRun, roleNames []string) ([]primitive.ObjectID, error) {
+	roleIDs := make([]primitive.ObjectID, 0, len(roleNames))
+	for _, roleName := range roleNames {
+		roleID, ok := run.roleIDs[roleName]
+		if !ok {
+			roleModel, err := s.roleService.GetByName(roleName, auth.CreateAdminAuthContext())
+			if err != nil {
+				return nil, fmt.Errorf("role %q does not exist", roleName)
+			}
+			roleID = roleModel.ID
+			run.roleIDs[roleName] = roleID
+		}
+		roleIDs = append(roleIDs, roleID)
+	}
+	return roleIDs, nil
+}
+
+// findExisting finds the user matching the row, nil if there is none
+func (s UserImportService) findExisting(matchBy user.ImportMatchField, value string) (*user.Model, error) {
+	var userModel user.Model
+	var err error
+	if matchBy == user.ImportMatchByUsername {
+		userModel, err = s.userService.userRepository.GetByUsername(value)
+	} else {
+		userModel, err = s.userService.userRepository.GetByEmail(value)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &userModel, nil
+}
+
+// Export streams every user with the selected fields to the writer, one CSV row or JSON line per user
+func (s UserImportService) Export(format user.ImportFormat, fields []string, writer io.Writer, authContext auth.PermissionContext) error {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserBulkExportPermission) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	if len(fields) == 0 {
+		fields = user.DefaultExportFields
+	}
+	if err := user.ValidateExportFields(fields); err != nil {
+		return fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+
+	switch format {
+	case user.ImportFormatCSV:
+		csvWriter := csv.NewWriter(writer)
+		if err := csvWriter.Write(fields); err != nil {
+			return err
+		}
+		err := s.userService.userRepository.ForEach(context.Background(), bson.M{}, func(userModel user.Model) error {
+			return csvWriter.Write(userModel.ExportStrings(fields))
+		})
+		csvWriter.Flush()
+		if err != nil {
+			return err
+		}
+		return csvWriter.Error()
+	case user.ImportFormatNDJSON:
+		encoder := json.NewEncoder(writer)
+		return s.userService.userRepository.ForEach(context.Background(), bson.M{}, func(userModel user.Model) error {
+			return encoder.Encode(userModel.ExportValues(fields))
+		})
+	default:
+		return constants.ErrorBadRequest
+	}
+}
+
+// importPersonInfo returns the person info of a row, nil if the row has no names
+func importPersonInfo(record user.ImportRecord) *user.PersonInfo {
+	if record.FirstName == "" && record.LastName == "" {
+		return nil
+	}
+	return &user.PersonInfo{FirstName: record.FirstName, LastName: record.LastName}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/registry"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// validateProperties validates the custom properties of a user against the registered schema. Missing properties
+// are validated as an empty object so the required keys are enforced.
+func validateProperties(properties map[string]interface{}) error {
+	schema := registry.GetUserPropertiesSchema().Schema
+	if schema == nil {
+		return nil
+	}
+	var value interface{} = map[string]interface{}{}
+	if properties != nil {
+		value = properties
+	}
+	if err := schema.Validate(value); err != nil {
+		return fmt.Errorf("properties: %w", err)
+	}
+	return nil
+}
+
+// propertiesError validates the custom properties of a user and wraps the validation errors as bad requests
+func propertiesError(properties map[string]interface{}) error {
+	if err := validateProperties(properties); err != nil {
+		return fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+	return nil
+}
+
+// checkSelfPropertyKeys refuses the admin-only keys in the properties a user sets on their own account
+func checkSelfPropertyKeys(properties map[string]interface{}) error {
+	schema := registry.GetUserPropertiesSchema()
+	var adminKeys []string
+	for key := range properties {
+		if schema.VisibilityOf(key) == registry.PropertyVisibilityAdmin {
+			adminKeys = append(adminKeys, key)
+		}
+	}
+	if len(adminKeys) == 0 {
+		return nil
+	}
+	sort.Strings(adminKeys)
+	return fmt.Errorf("%w: properties %s can only be changed by admins", constants.ErrorBadRequest, strings.Join(adminKeys, ", "))
+}
+
+// mergeSelfProperties keeps the stored admin-only keys when a user replaces the properties of their own account
+func mergeSelfProperties(stored, updated map[string]interface{}) map[string]interface{} {
+	schema := registry.GetUserPropertiesSchema()
+	merged := make(map[string]interface{}, len(updated))
+	for key, value := range stored {
+		if schema.VisibilityOf(key) == registry.PropertyVisibilityAdmin {
+			merged[key] = value
+		}
+	}
+	for key, value := range updated {
+		merged[key] = value
+	}
+	return merged
+}
+
+// filterProperties returns a copy of the user with only the properties of the given visibilities
+func filterProperties(userModel user.Model, visibilities ...registry.PropertyVisibility) user.Model {
+	if userModel.Properties == nil {
+		return userModel
+	}
+	schema := registry.GetUserPropertiesSchema()
+	properties := make(map[string]interface{}, len(userModel.Properties))
+	for key, value := range userModel.Properties {
+		visibility := schema.VisibilityOf(key)
+		for _, visible := range visibilities {
+			if visibility == visible {
+				properties[key] = value
+				break
+			}
+		}
+	}
+	userModel.Properties = properties
+	return userModel
+}
+
+// ValidateSelfProperties validates the properties a user sets on their own account, such as on sign up.
+// Admin-only keys are refused.
+func (s UserService) ValidateSelfProperties(properties map[string]interface{}) error {
+	if err := checkSelfPropertyKeys(properties); err != nil {
+		return err
+	}
+	return propertiesError(properties)
+}
+
+// SelfView returns the user as the user sees their own account, without the admin-only properties
+func (s UserService) SelfView(userModel user.Model) user.Model {
+	return filterProperties(userModel, registry.PropertyVisibilityPublic, registry.PropertyVisibilitySelf)
+}
+
+// PublicView returns the user as the other users see it, only with the public properties
+func (s UserService) PublicView(userModel user.Model) user.PublicProfile {
+	userModel = filterProperties(userModel, registry.PropertyVisibilityPublic)
+	profile := user.PublicProfile{
+		ID:         userModel.ID,
+		Username:   userModel.Username,
+		Avatar:     userModel.Avatar,
+		Properties: userModel.Properties,
+	}
+	if userModel.PersonInfo != nil {
+		profile.FirstName = userModel.PersonInfo.FirstName
+		profile.LastName = userModel.PersonInfo.LastName
+	}
+	return profile
+}
+
+// GetPublicProfiles returns the public profiles of the given users by their IDs. Deleted users are left out.
+func (s UserService) GetPublicProfiles(userIDs []primitive.ObjectID) (map[primitive.ObjectID]user.PublicProfile, error) {
+	profiles := make(map[primitive.ObjectID]user.PublicProfile, len(userIDs))
+	if len(userIDs) == 0 {
+		return profiles, nil
+	}
+	users, err := s.userRepository.Query(context.Background(), bson.M{"_id": bson.M{"$in": userIDs}}, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, userModel := range users.Data {
+		profiles[userModel.ID] = s.PublicView(userModel)
+	}
+	return profiles, nil
+}
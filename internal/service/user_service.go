@@ -2,6 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/LydiaTrack/ground/internal/permissions"
 	"github.com/LydiaTrack/ground/pkg/auth"
@@ -9,41 +14,66 @@ import (
 	"github.com/LydiaTrack/ground/pkg/domain/role"
 	"github.com/LydiaTrack/ground/pkg/domain/user"
 	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/mongodb/patch"
+	"github.com/LydiaTrack/ground/pkg/mongodb/query"
 	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
 	"github.com/LydiaTrack/ground/pkg/registry"
 	"github.com/LydiaTrack/ground/pkg/responses"
+	"github.com/LydiaTrack/ground/pkg/storage"
+	"github.com/LydiaTrack/ground/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var userSearchFields = []string{"username", "contactInfo.email"}
 
+// defaultUserDeletionGracePeriodInHour is the default period a deleted user can be restored in
+const defaultUserDeletionGracePeriodInHour = 720
+
 type UserService struct {
-	userRepository   UserRepository
-	roleService      RoleService
-	userStatsService *UserStatsService
+	userRepository           UserRepository
+	roleService              RoleService
+	userStatsService         *UserStatsService
+	identityChangeRepository IdentityChangeRepository
+	blobStore                storage.BlobStore
 }
 
-func NewUserService(userRepository UserRepository, roleService RoleService, userStatsService *UserStatsService) *UserService {
+// NewUserService creates a UserService, the username and email changes are not recorded without an
+// identityChangeRepository. The blobStore holds the uploaded avatars, they cannot be set on a user without it.
+func NewUserService(userRepository UserRepository, roleService RoleService, userStatsService *UserStatsService, identityChangeRepository IdentityChangeRepository, blobStore storage.BlobStore) *UserService {
 	return &UserService{
-		userRepository:   userRepository,
-		roleService:      roleService,
-		userStatsService: userStatsService,
+		userRepository:           userRepository,
+		roleService:              roleService,
+		userStatsService:         userStatsService,
+		identityChangeRepository: identityChangeRepository,
+		blobStore:                blobStore,
 	}
 }
 
 type UserRepository interface {
 	repository.Repository[user.Model]
+	UpdateFields(ctx context.Context, id interface{}, set bson.M, unset []string) (*mongo.UpdateResult, error)
+	QuerySorted(ctx context.Context, filter interface{}, searchFields []string, searchText string, sort interface{}) (responses.QueryResult[user.Model], error)
 	ExistsByUsernameAndEmail(username, email string) bool
 	ExistsByUsername(username string) bool
 	ExistsByEmail(email string) bool
 	GetByUsername(username string) (user.Model, error)
 	GetByEmail(email string) (user.Model, error)
-	AddRole(userID, roleID primitive.ObjectID) error
+	AddRole(userID primitive.ObjectID, assignment user.RoleAssignment) error
 	RemoveRole(userID, roleID primitive.ObjectID) error
+	RemoveExpiredRoleAssignments(now time.Time) (int64, error)
 	GetUserRoles(roleIds []primitive.ObjectID) (responses.QueryResult[role.Model], error)
 	UpdateUserPassword(id primitive.ObjectID, password string) error
+	UpdateStatus(userID primitive.ObjectID, status user.AccountStatus) error
+	MarkDeleted(ctx context.Context, id interface{}, deletedBy *primitive.ObjectID) (*mongo.UpdateResult, error)
+	Restore(ctx context.Context, id interface{}) (*mongo.UpdateResult, error)
+	GetDeletedBefore(before time.Time) ([]user.Model, error)
+	SetDeletionSchedule(userID primitive.ObjectID, scheduledAt *time.Time) error
+	ReplaceInlineAvatar(userID primitive.ObjectID, inlineAvatar string, avatar string, avatarID string) (bool, error)
+	GetDeletionsScheduledBefore(before time.Time) ([]user.Model, error)
+	ForEach(ctx context.Context, filter interface{}, fn func(user.Model) error) error
 }
 
 // Create creates a new user
@@ -60,13 +90,21 @@ func (s UserService) Create(command user.CreateUserCommand, authContext auth.Per
 		user.WithProperties(command.Properties),
 		user.WithAvatar(command.Avatar),
 		user.WithOAuthInfo(command.OAuthInfo),
+		user.WithRoleAssignments(command.RoleAssignments),
 	)
 
 	if err := userModel.Validate(); err != nil {
 		return user.Model{}, constants.ErrorBadRequest
 	}
+	if err := propertiesError(userModel.Properties); err != nil {
+		return user.Model{}, err
+	}
+	if userModel.AvatarID, err = s.uploadedAvatarID(userModel.ID, userModel.Avatar); err != nil {
+		return user.Model{}, err
+	}
 
-	if s.userRepository.ExistsByUsernameAndEmail(userModel.Username, userModel.ContactInfo.Email) {
+	if s.userRepository.ExistsByUsernameAndEmail(userModel.Username, userModel.ContactInfo.Email) ||
+		s.isUsernameReserved(userModel.Username, nil) {
 		return user.Model{}, constants.ErrorConflict
 	}
 
@@ -151,22 +189,53 @@ func (s UserService) InitializeDefaultRolesForAllUsers() error {
 }
 
 // Query users by an optional search text
-func (s UserService) Query(searchText string, authContext auth.PermissionContext) (responses.QueryResult[user.Model], error) {
+func (s UserService) Query(searchText string, userQuery query.Query, authContext auth.PermissionContext) (responses.QueryResult[user.Model], error) {
 	if auth.CheckPermission(authContext.Permissions, permissions.UserReadPermission) != nil {
 		return responses.QueryResult[user.Model]{}, constants.ErrorPermissionDenied
 	}
 
-	return s.userRepository.Query(context.Background(), nil, userSearchFields, searchText)
+	return s.userRepository.QuerySorted(context.Background(), userQuery.FilterOrEmpty(), userSearchFields, searchText, userQuery.SortOrNil())
 }
 
 // QueryPaginated query users by an optional search text with pagination
-func (s UserService) QueryPaginated(searchText string, page, limit int, authContext auth.PermissionContext) (responses.PaginatedResult[user.Model], error) {
+func (s UserService) QueryPaginated(searchText string, userQuery query.Query, page, limit int, authContext auth.PermissionContext) (responses.PaginatedResult[user.Model], error) {
 	if auth.CheckPermission(authContext.Permissions, permissions.UserReadPermission) != nil {
 		return responses.PaginatedResult[user.Model]{}, constants.ErrorPermissionDenied
 	}
 
 	ctx := context.Background()
-	return s.userRepository.QueryPaginate(ctx, nil, userSearchFields, searchText, page, limit, nil)
+	return s.userRepository.QueryPaginate(ctx, userQuery.FilterOrEmpty(), userSearchFields, searchText, page, limit, userQuery.SortOrNil())
+}
+
+// QueryCursor query users by an optional search text with keyset pagination, in the order of the query if it has a sort
+func (s UserService) QueryCursor(searchText string, userQuery query.Query, request repository.CursorRequest, authContext auth.PermissionContext) (responses.CursorResult[user.Model], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserReadPermission) != nil {
+		return responses.CursorResult[user.Model]{}, constants.ErrorPermissionDenied
+	}
+
+	if len(userQuery.Sort) > 0 {
+		request.Sort = userQuery.Sort
+	}
+	result, err := s.userRepository.QueryCursor(context.Background(), userQuery.FilterOrEmpty(), userSearchFields, searchText, request)
+	return result, cursorError(err)
+}
+
+// QueryByRolePaginated query the users that have the role assigned by an optional search text with pagination
+func (s UserService) QueryByRolePaginated(roleID primitive.ObjectID, searchText string, page, limit int, authContext auth.PermissionContext) (responses.PaginatedResult[user.Model], error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserReadPermission) != nil {
+		return responses.PaginatedResult[user.Model]{}, constants.ErrorPermissionDenied
+	}
+
+	exists, err := s.roleService.Exists(roleID.Hex(), authContext)
+	if err != nil {
+		return responses.PaginatedResult[user.Model]{}, err
+	}
+	if !exists {
+		return responses.PaginatedResult[user.Model]{}, constants.ErrorNotFound
+	}
+
+	filter := bson.M{"roleIds": roleID}
+	return s.userRepository.QueryPaginate(context.Background(), filter, userSearchFields, searchText, page, limit, nil)
 }
 
 // GetByUsername retrieves a user by username
@@ -227,13 +296,13 @@ func (s UserService) Exists(id string) (bool, error) {
 	return exists, nil
 }
 
-// ExistsByUsername checks if a user exists by username
+// ExistsByUsername checks if a user exists by username or the username is reserved for the user who released it
 func (s UserService) ExistsByUsername(username string, authContext auth.PermissionContext) (bool, error) {
 	if auth.CheckPermission(authContext.Permissions, permissions.UserReadPermission) != nil {
 		return false, constants.ErrorPermissionDenied
 	}
 
-	exists := s.userRepository.ExistsByUsername(username)
+	exists := s.userRepository.ExistsByUsername(username) || s.isUsernameReserved(username, nil)
 	return exists, nil
 }
 
@@ -254,11 +323,12 @@ func (s UserService) ExistsByEmailAndUsername(email string, username string, aut
 	}
 
 	existsByEmail := s.userRepository.ExistsByEmail(email)
-	existsByUsername := s.userRepository.ExistsByUsername(username)
+	existsByUsername := s.userRepository.ExistsByUsername(username) || s.isUsernameReserved(username, nil)
 	return existsByEmail || existsByUsername, nil
 }
 
-// Delete deletes a user by ID
+// Delete marks a user as deleted. The user can be restored within the deletion grace period,
+// after that it is purged with its data by PurgeDeletedUsers.
 func (s UserService) Delete(command user.DeleteUserCommand, authContext auth.PermissionContext) error {
 	if auth.CheckPermission(authContext.Permissions, permissions.UserDeletePermission) != nil {
 		return constants.ErrorPermissionDenied
@@ -273,7 +343,7 @@ func (s UserService) Delete(command user.DeleteUserCommand, authContext auth.Per
 		return constants.ErrorNotFound
 	}
 
-	_, err = s.userRepository.Delete(context.Background(), command.ID)
+	_, err = s.userRepository.MarkDeleted(context.Background(), command.ID, authContext.UserID)
 	if err != nil {
 		return constants.ErrorInternalServerError
 	}
@@ -281,61 +351,272 @@ func (s UserService) Delete(command user.DeleteUserCommand, authContext auth.Per
 	return nil
 }
 
+// Restore restores a deleted user if its deletion grace period has not passed yet
+func (s UserService) Restore(id string, authContext auth.PermissionContext) (user.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserRestorePermission) != nil {
+		return user.Model{}, constants.ErrorPermissionDenied
+	}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return user.Model{}, constants.ErrorBadRequest
+	}
+
+	ctx := repository.IncludeDeleted(context.Background())
+	userModel, err := s.userRepository.GetByID(ctx, objID)
+	if err != nil || userModel.DeletedAt == nil {
+		return user.Model{}, constants.ErrorNotFound
+	}
+	if time.Since(*userModel.DeletedAt) > GetUserDeletionGracePeriod() {
+		return user.Model{}, constants.ErrorNotFound
+	}
+
+	if _, err = s.userRepository.Restore(context.Background(), objID); err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+
+	restoredUser, err := s.userRepository.GetByID(context.Background(), objID)
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	return restoredUser, nil
+}
+
+// PurgeDeletedUsers hard deletes the users whose deletion grace period has passed,
+// together with the data the registered cleanup hooks own for them
+func (s UserService) PurgeDeletedUsers() error {
+	users, err := s.userRepository.GetDeletedBefore(time.Now().Add(-GetUserDeletionGracePeriod()))
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+
+	if purged := s.purgeUsers(users); purged > 0 {
+		log.Log("Purged %d deleted users", purged)
+	}
+	return nil
+}
+
+// PurgeScheduledDeletions hard deletes the users whose self-service deletion cooling-off period has passed,
+// together with the data the registered cleanup hooks own for them
+func (s UserService) PurgeScheduledDeletions() error {
+	users, err := s.userRepository.GetDeletionsScheduledBefore(time.Now())
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+
+	if purged := s.purgeUsers(users); purged > 0 {
+		log.Log("Purged %d users whose deletion was scheduled", purged)
+	}
+	return nil
+}
+
+// CancelScheduledDeletion cancels the self-service deletion of a user in its cooling-off period
+func (s UserService) CancelScheduledDeletion(userID primitive.ObjectID) error {
+	if err := s.userRepository.SetDeletionSchedule(userID, nil); err != nil {
+		return constants.ErrorInternalServerError
+	}
+	return nil
+}
+
+// SetOAuthProvider records the provider of the OAuth account of a user, for the accounts linked before it was stored
+func (s UserService) SetOAuthProvider(userID primitive.ObjectID, provider string) error {
+	if _, err := s.userRepository.UpdateFields(context.Background(), userID, bson.M{"OAuthInfo.provider": provider}, nil); err != nil {
+		return constants.ErrorInternalServerError
+	}
+	return nil
+}
+
+// purgeUsers runs the cleanup hooks for the users and hard deletes them, returning the number of purged users.
+// A user whose cleanup fails is kept, so it is retried on the next run.
+func (s UserService) purgeUsers(users []user.Model) int {
+	ctx := repository.IncludeDeleted(context.Background())
+	purged := 0
+	for _, userModel := range users {
+		if err := registry.RunUserCleanupHooks(userModel.ID); err != nil {
+			log.LogError("Failed to clean up the data of user %s: %v", userModel.ID.Hex(), err)
+			continue
+		}
+		if _, err := s.userRepository.Delete(ctx, userModel.ID); err != nil {
+			log.LogError("Failed to purge user %s: %v", userModel.ID.Hex(), err)
+			continue
+		}
+		purged++
+	}
+	return purged
+}
+
+// GetUserDeletionGracePeriod reads the period a deleted user can be restored in from USER_DELETION_GRACE_PERIOD_IN_HOUR
+func GetUserDeletionGracePeriod() time.Duration {
+	gracePeriodInHour, err := strconv.Atoi(os.Getenv("USER_DELETION_GRACE_PERIOD_IN_HOUR"))
+	if err != nil || gracePeriodInHour <= 0 {
+		gracePeriodInHour = defaultUserDeletionGracePeriodInHour
+	}
+	return time.Duration(gracePeriodInHour) * time.Hour
+}
+
 // Update updates a user
 func (s UserService) Update(id string, command user.UpdateUserCommand, authContext auth.PermissionContext) (user.Model, error) {
 	if auth.CheckPermission(authContext.Permissions, permissions.UserUpdatePermission) != nil {
 		return user.Model{}, constants.ErrorPermissionDenied
 	}
 
-	exists, err := s.Exists(id)
+	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return user.Model{}, constants.ErrorInternalServerError
+		return user.Model{}, constants.ErrorBadRequest
 	}
-	if !exists {
+
+	currentUser, err := s.userRepository.GetByID(context.Background(), objID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
 		return user.Model{}, constants.ErrorNotFound
 	}
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
 
 	if err := command.Validate(); err != nil {
 		return user.Model{}, constants.ErrorBadRequest
 	}
+	if command.Properties != nil {
+		if err := propertiesError(command.Properties); err != nil {
+			return user.Model{}, err
+		}
+	}
+	if err := s.checkIdentityAvailable(currentUser, command.Username, commandEmail(command)); err != nil {
+		return user.Model{}, err
+	}
+	set, unset, err := s.updateFields(currentUser, command)
+	if err != nil {
+		return user.Model{}, err
+	}
+
+	_, err = s.userRepository.UpdateFields(versionContext(command.ExpectedVersion), objID, set, unset)
+	if errors.Is(err, constants.ErrorVersionConflict) {
+		return user.Model{}, err
+	}
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+
+	updatedUser, err := s.userRepository.GetByID(context.Background(), objID)
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	updatedUser = s.resetPhoneVerification(currentUser, updatedUser)
+	s.recordIdentityChanges(currentUser, updatedUser, authContext.UserID)
+	s.releaseAvatar(currentUser, updatedUser)
+
+	return updatedUser, nil
+}
+
+// Patch applies a merge patch or a JSON patch to a user, only user.PatchableFields can be changed.
+// The patched user is validated like an updated one before it is written.
+func (s UserService) Patch(id string, command user.PatchUserCommand, authContext auth.PermissionContext) (user.Model, error) {
+	if auth.CheckPermission(authContext.Permissions, permissions.UserUpdatePermission) != nil {
+		return user.Model{}, constants.ErrorPermissionDenied
+	}
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return user.Model{}, constants.ErrorBadRequest
 	}
 
-	_, err = s.userRepository.Update(context.Background(), objID, command)
+	currentUser, err := s.userRepository.GetByID(context.Background(), objID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.Model{}, constants.ErrorNotFound
+	}
 	if err != nil {
 		return user.Model{}, constants.ErrorInternalServerError
 	}
 
-	updatedUser, err := s.userRepository.GetByID(context.Background(), objID)
+	var patchedUser user.Model
+	update, err := patch.Apply(command.ContentType, command.Patch, currentUser, &patchedUser, user.PatchableFields)
+	if err != nil {
+		return user.Model{}, patchError(err)
+	}
+	if update.IsEmpty() {
+		return currentUser, nil
+	}
+
+	validation := user.UpdateUserCommand{Username: patchedUser.Username, Avatar: patchedUser.Avatar, PersonInfo: patchedUser.PersonInfo, ContactInfo: &patchedUser.ContactInfo}
+	if err := validation.Validate(); err != nil {
+		return user.Model{}, fmt.Errorf("%w: %v", constants.ErrorBadRequest, err)
+	}
+	if update.Touches("properties") {
+		if err := propertiesError(patchedUser.Properties); err != nil {
+			return user.Model{}, err
+		}
+	}
+	if err := s.checkIdentityAvailable(currentUser, patchedUser.Username, patchedUser.ContactInfo.Email); err != nil {
+		return user.Model{}, err
+	}
+	if update.Touches("avatar") {
+		avatarSet, avatarUnset, err := s.avatarFields(objID, patchedUser.Avatar)
+		if err != nil {
+			return user.Model{}, err
+		}
+		for key, value := range avatarSet {
+			update.Set[key] = value
+		}
+		update.Unset = append(update.Unset, avatarUnset...)
+	}
+
+	_, err = s.userRepository.UpdateFields(versionContext(command.ExpectedVersion), objID, update.Set, update.Unset)
+	if errors.Is(err, constants.ErrorVersionConflict) {
+		return user.Model{}, err
+	}
 	if err != nil {
 		return user.Model{}, constants.ErrorInternalServerError
 	}
 
+	updatedUser, err := s.userRepository.GetByID(context.Background(), objID)
+	if err != nil {
+		return user.Model{}, constants.ErrorInternalServerError
+	}
+	updatedUser = s.resetPhoneVerification(currentUser, updatedUser)
+	s.recordIdentityChanges(currentUser, updatedUser, authContext.UserID)
+	s.releaseAvatar(currentUser, updatedUser)
 	return updatedUser, nil
 }
 
-// UpdateSelf updates a user's own information
+// UpdateSelf updates a user's own information, the admin-only properties are neither changed nor returned
 func (s UserService) UpdateSelf(command user.UpdateUserCommand, authContext auth.PermissionContext) (user.Model, error) {
 	if auth.CheckPermission(authContext.Permissions, permissions.UserSelfUpdatePermission) != nil {
 		return user.Model{}, constants.ErrorPermissionDenied
 	}
 
-	exists, err := s.Exists(authContext.UserID.Hex())
+	currentUser, err := s.userRepository.GetByID(context.Background(), *authContext.UserID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return user.Model{}, constants.ErrorNotFound
+	}
 	if err != nil {
 		return user.Model{}, constants.ErrorInternalServerError
 	}
-	if !exists {
-		return user.Model{}, constants.ErrorNotFound
-	}
 
 	if err := command.Validate(); err != nil {
 		return user.Model{}, constants.ErrorBadRequest
 	}
+	if command.Properties != nil {
+		// Admin-only keys cannot be set by the user but are kept when the properties are replaced
+		if err := checkSelfPropertyKeys(command.Properties); err != nil {
+			return user.Model{}, err
+		}
+		command.Properties = mergeSelfProperties(currentUser.Properties, command.Properties)
+		if err := propertiesError(command.Properties); err != nil {
+			return user.Model{}, err
+		}
+	}
+	if err := s.checkIdentityAvailable(currentUser, command.Username, commandEmail(command)); err != nil {
+		return user.Model{}, err
+	}
+	set, unset, err := s.updateFields(currentUser, command)
+	if err != nil {
+		return user.Model{}, err
+	}
 
-	_, err = s.userRepository.Update(context.Background(), *authContext.UserID, command)
+	_, err = s.userRepository.UpdateFields(versionContext(command.ExpectedVersion), *authContext.UserID, set, unset)
+	if errors.Is(err, constants.ErrorVersionConflict) {
+		return user.Model{}, err
+	}
 	if err != nil {
 		return user.Model{}, constants.ErrorInternalServerError
 	}
@@ -344,8 +625,47 @@ func (s UserService) UpdateSelf(command user.UpdateUserCommand, authContext auth
 	if err != nil {
 		return user.Model{}, constants.ErrorInternalServerError
 	}
+	updatedUser = s.resetPhoneVerification(currentUser, updatedUser)
+	s.recordIdentityChanges(currentUser, updatedUser, authContext.UserID)
+	s.releaseAvatar(currentUser, updatedUser)
 
-	return updatedUser, nil
+	return s.SelfView(updatedUser), nil
+}
+
+// updateFields returns the fields an update command writes to a user, a new avatar is written with its ID
+func (s UserService) updateFields(currentUser user.Model, command user.UpdateUserCommand) (bson.M, []string, error) {
+	set, err := utils.GenerateUpdateDocument(command)
+	if err != nil {
+		return nil, nil, constants.ErrorInternalServerError
+	}
+	if command.Avatar == "" || command.Avatar == currentUser.Avatar {
+		return set, nil, nil
+	}
+	avatarSet, unset, err := s.avatarFields(currentUser.ID, command.Avatar)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range avatarSet {
+		set[key] = value
+	}
+	return set, unset, nil
+}
+
+// resetPhoneVerification clears the phone verification of the updated user if its phone number has changed
+func (s UserService) resetPhoneVerification(before, after user.Model) user.Model {
+	if !after.PhoneVerified || before.ContactInfo.PhoneE164() == after.ContactInfo.PhoneE164() {
+		return after
+	}
+	if _, err := s.userRepository.UpdateFields(context.Background(), after.ID, bson.M{"phoneVerified": false}, nil); err != nil {
+		log.LogError("Failed to reset the phone verification of user %s: %v", after.ID.Hex(), err)
+		return after
+	}
+	updatedUser, err := s.userRepository.GetByID(context.Background(), after.ID)
+	if err != nil {
+		after.PhoneVerified = false
+		return after
+	}
+	return updatedUser
 }
 
 // UpdatePassword updates a user's password
@@ -495,6 +815,10 @@ func (s UserService) AddRole(command user.AddRoleToUserCommand, authContext auth
 		return constants.ErrorPermissionDenied
 	}
 
+	if err := command.Validate(); err != nil {
+		return constants.ErrorBadRequest
+	}
+
 	exists, err := s.roleService.Exists(command.RoleID.Hex(), authContext)
 	if err != nil {
 		return constants.ErrorInternalServerError
@@ -503,7 +827,14 @@ func (s UserService) AddRole(command user.AddRoleToUserCommand, authContext auth
 		return constants.ErrorNotFound
 	}
 
-	err = s.userRepository.AddRole(command.UserID, command.RoleID)
+	assignment := user.RoleAssignment{
+		RoleID:    command.RoleID,
+		GrantedBy: authContext.UserID,
+		GrantedAt: time.Now(),
+		ExpiresAt: command.ExpiresAt,
+		Reason:    command.Reason,
+	}
+	err = s.userRepository.AddRole(command.UserID, assignment)
 	if err != nil {
 		return constants.ErrorInternalServerError
 	}
@@ -533,19 +864,46 @@ func (s UserService) RemoveRole(command user.RemoveRoleFromUserCommand, authCont
 	return nil
 }
 
-// GetPermissionList retrieves permissions for a user
+// GetPermissionList retrieves permissions for a user, including the permissions inherited from parent roles
+// and the roles the user holds indirectly, e.g. through groups
 func (s UserService) GetPermissionList(userModel user.Model) ([]auth.Permission, error) {
-	userRoles, err := s.GetRoles(*userModel.RoleIDs, auth.CreateAdminAuthContext())
+	roleIDs, err := s.GetRoleIDs(userModel)
 	if err != nil {
 		return nil, err
 	}
 
-	var permissionList []auth.Permission
-	for _, roleModel := range userRoles.Data {
-		permissionList = append(permissionList, roleModel.Permissions...)
+	userRoles, err := s.roleService.ResolveRoles(roleIDs, auth.CreateAdminAuthContext())
+	if err != nil {
+		return nil, err
 	}
 
-	return permissionList, nil
+	return role.MergePermissions(userRoles), nil
+}
+
+// GetRoleIDs retrieves the IDs of the roles the user holds, directly or through the registered user role providers
+func (s UserService) GetRoleIDs(userModel user.Model) ([]primitive.ObjectID, error) {
+	// Expired assignments are ignored even before they are swept
+	roleIDs := userModel.ActiveRoleIDs(time.Now())
+
+	providedRoleIDs, err := registry.GetAllUserRoleIDs(userModel.ID)
+	if err != nil {
+		return nil, constants.ErrorInternalServerError
+	}
+
+	return append(roleIDs, providedRoleIDs...), nil
+}
+
+// RemoveExpiredRoleAssignments removes the expired role assignments from all users
+func (s UserService) RemoveExpiredRoleAssignments() error {
+	removed, err := s.userRepository.RemoveExpiredRoleAssignments(time.Now())
+	if err != nil {
+		return constants.ErrorInternalServerError
+	}
+
+	if removed > 0 {
+		log.Log("Removed the expired role assignments of %d users", removed)
+	}
+	return nil
 }
 
 // addDefaultRoles adds default roles to a user
@@ -556,8 +914,13 @@ func (s UserService) addDefaultRoles(userID primitive.ObjectID, authContext auth
 	}
 
 	for _, id := range defaultRoleIDs {
-
-		if err = s.userRepository.AddRole(userID, id); err != nil {
+		assignment := user.RoleAssignment{
+			RoleID:    id,
+			GrantedBy: authContext.UserID,
+			GrantedAt: time.Now(),
+			Reason:    "default role",
+		}
+		if err = s.userRepository.AddRole(userID, assignment); err != nil {
 			return err
 		}
 	}
@@ -1,11 +1,16 @@
 package service
 
 import (
+	"context"
+	"errors"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
 	"github.com/LydiaTrack/ground/pkg/auth"
 	"github.com/LydiaTrack/ground/pkg/constants"
 	"github.com/LydiaTrack/ground/pkg/domain/user"
 	"github.com/LydiaTrack/ground/pkg/log"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type UserStatsRepository interface {
@@ -16,6 +21,7 @@ type UserStatsRepository interface {
 	IncrementInt64Field(statsID primitive.ObjectID, fieldName string, increment int64) error
 	UpdateField(statsID primitive.ObjectID, fieldName string, value interface{}) error
 	UpdateFields(statsID primitive.ObjectID, fields map[string]interface{}) error
+	DeleteStatsByUserID(userID primitive.ObjectID) error
 }
 
 type UserStatsService struct {
@@ -40,11 +46,15 @@ func (s *UserStatsService) CreateUserStats(userID primitive.ObjectID, username s
 
 // GetUserStats retrieves a user's stats
 func (s *UserStatsService) GetUserStats(userID primitive.ObjectID, authContext auth.PermissionContext) (user.StatsDocument, error) {
-	// Check if the requesting user is the same as the user ID in the stats or has admin permission
-	if authContext.UserID != nil && *authContext.UserID != userID && !auth.HasPermission(authContext.Permissions, auth.AdminPermission) {
+	if auth.Authorize(context.Background(), authContext, permissions.UserStatsReadPermission, statsResource(userID)) != nil {
 		return user.StatsDocument{}, constants.ErrorPermissionDenied
 	}
 
+	return s.getStats(userID)
+}
+
+// getStats retrieves a user's stats for the updates, which only require the update permission
+func (s *UserStatsService) getStats(userID primitive.ObjectID) (user.StatsDocument, error) {
 	stats, err := s.userStatsRepository.GetStatsByUserID(userID)
 	if err != nil {
 		return user.StatsDocument{}, constants.ErrorInternalServerError
@@ -54,9 +64,8 @@ func (s *UserStatsService) GetUserStats(userID primitive.ObjectID, authContext a
 
 // UpdateUserStats updates a user's stats
 func (s *UserStatsService) UpdateUserStats(stats user.StatsDocument, authContext auth.PermissionContext) error {
-	// Check if the requesting user is the same as the user ID in the stats or has admin permission
 	core := stats.GetCoreFields()
-	if authContext.UserID != nil && *authContext.UserID != core.UserID && !auth.HasPermission(authContext.Permissions, auth.AdminPermission) {
+	if auth.Authorize(context.Background(), authContext, permissions.UserStatsUpdatePermission, statsResource(core.UserID)) != nil {
 		return constants.ErrorPermissionDenied
 	}
 
@@ -71,7 +80,11 @@ func (s *UserStatsService) UpdateUserStats(stats user.StatsDocument, authContext
 
 // RecordLogin increments login count and updates last login date
 func (s *UserStatsService) RecordLogin(userID primitive.ObjectID, authContext auth.PermissionContext) error {
-	stats, err := s.GetUserStats(userID, authContext)
+	if auth.Authorize(context.Background(), authContext, permissions.UserStatsUpdatePermission, statsResource(userID)) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	stats, err := s.getStats(userID)
 	if err != nil {
 		return err
 	}
@@ -85,7 +98,11 @@ func (s *UserStatsService) RecordLogin(userID primitive.ObjectID, authContext au
 
 // IncrementField increments a numeric field in the user's stats
 func (s *UserStatsService) IncrementField(userID primitive.ObjectID, fieldName string, increment int, authContext auth.PermissionContext) error {
-	stats, err := s.GetUserStats(userID, authContext)
+	if auth.Authorize(context.Background(), authContext, permissions.UserStatsUpdatePermission, statsResource(userID)) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	stats, err := s.getStats(userID)
 	if err != nil {
 		return err
 	}
@@ -96,7 +113,11 @@ func (s *UserStatsService) IncrementField(userID primitive.ObjectID, fieldName s
 
 // IncrementInt64Field increments a numeric int64 field in the user's stats
 func (s *UserStatsService) IncrementInt64Field(userID primitive.ObjectID, fieldName string, increment int64, authContext auth.PermissionContext) error {
-	stats, err := s.GetUserStats(userID, authContext)
+	if auth.Authorize(context.Background(), authContext, permissions.UserStatsUpdatePermission, statsResource(userID)) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	stats, err := s.getStats(userID)
 	if err != nil {
 		return err
 	}
@@ -107,7 +128,11 @@ func (s *UserStatsService) IncrementInt64Field(userID primitive.ObjectID, fieldN
 
 // UpdateField updates a specific field in the user's stats
 func (s *UserStatsService) UpdateField(userID primitive.ObjectID, fieldName string, value interface{}, authContext auth.PermissionContext) error {
-	stats, err := s.GetUserStats(userID, authContext)
+	if auth.Authorize(context.Background(), authContext, permissions.UserStatsUpdatePermission, statsResource(userID)) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	stats, err := s.getStats(userID)
 	if err != nil {
 		return err
 	}
@@ -118,7 +143,11 @@ func (s *UserStatsService) UpdateField(userID primitive.ObjectID, fieldName stri
 
 // UpdateFields updates multiple fields in the user's stats
 func (s *UserStatsService) UpdateFields(userID primitive.ObjectID, fields map[string]interface{}, authContext auth.PermissionContext) error {
-	stats, err := s.GetUserStats(userID, authContext)
+	if auth.Authorize(context.Background(), authContext, permissions.UserStatsUpdatePermission, statsResource(userID)) != nil {
+		return constants.ErrorPermissionDenied
+	}
+
+	stats, err := s.getStats(userID)
 	if err != nil {
 		return err
 	}
@@ -126,3 +155,26 @@ func (s *UserStatsService) UpdateFields(userID primitive.ObjectID, fields map[st
 	core := stats.GetCoreFields()
 	return s.userStatsRepository.UpdateFields(core.ID, fields)
 }
+
+// statsResource describes the stats document of a user for authorization
+func statsResource(userID primitive.ObjectID) auth.Resource {
+	return auth.Resource{
+		Domain:  permissions.UserStatsReadPermission.Domain,
+		ID:      userID.Hex(),
+		OwnerID: &userID,
+	}
+}
+
+// CleanupUser deletes the stats of a purged user
+func (s *UserStatsService) CleanupUser(userID primitive.ObjectID) error {
+	return s.userStatsRepository.DeleteStatsByUserID(userID)
+}
+
+// ExportUserData exports the stats of a user for the data export
+func (s *UserStatsService) ExportUserData(userID primitive.ObjectID) (interface{}, error) {
+	stats, err := s.userStatsRepository.GetStatsByUserID(userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	return stats, err
+}
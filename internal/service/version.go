@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+)
+
+// versionContext returns the context of an update guarded by the expected version, unguarded if it is nil
+func versionContext(expectedVersion *int) context.Context {
+	if expectedVersion == nil {
+		return context.Background()
+	}
+	return repository.WithExpectedVersion(context.Background(), *expectedVersion)
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// deletionSessionRepository accepts the session revocation of the deleted users
+type deletionSessionRepository struct {
+	service.SessionRepository
+}
+
+func (deletionSessionRepository) DeleteSessionByUserID(primitive.ObjectID) error {
+	return nil
+}
+
+// mockOAuthReauthenticator accepts a single token of the provider account
+type mockOAuthReauthenticator struct {
+	token string
+}
+
+func (m mockOAuthReauthenticator) ReauthenticateOAuth(userModel user.Model, token string) error {
+	if userModel.OAuthInfo == nil || token == "" || token != m.token {
+		return constants.ErrorUnauthorized
+	}
+	return nil
+}
+
+func TestAccountSelfDeletion(t *testing.T) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Error hashing password: %v", err)
+	}
+	passwordUser := user.Model{ID: primitive.NewObjectID(), Username: "password-user", Password: string(hashedPassword)}
+	oauthUser := user.Model{ID: primitive.NewObjectID(), Username: "oauth-user",
+		OAuthInfo: &user.OAuthInfo{Provider: "google", ProviderID: "google-oauth-user"}}
+	noCredentialUser := user.Model{ID: primitive.NewObjectID(), Username: "no-credential-user"}
+
+	repo := NewMockUserRepository(passwordUser, oauthUser, noCredentialUser)
+	userService := service.NewUserService(repo, service.RoleService{}, nil, nil, nil)
+	sessionService := service.NewSessionService(deletionSessionRepository{}, *userService)
+	deletionService := service.NewAccountDeletionService(*userService, *sessionService, mockOAuthReauthenticator{token: "fresh-id-token"})
+
+	selfContext := func(userID primitive.ObjectID) auth.PermissionContext {
+		return auth.PermissionContext{Permissions: []auth.Permission{permissions.UserSelfDeletePermission}, UserID: &userID}
+	}
+
+	testCases := []struct {
+		name    string
+		userID  primitive.ObjectID
+		command user.DeleteSelfCommand
+		wantErr error
+	}{
+		{"password user with the password", passwordUser.ID, user.DeleteSelfCommand{Password: "secret123"}, nil},
+		{"password user with a wrong password", passwordUser.ID, user.DeleteSelfCommand{Password: "wrong"}, constants.ErrorUnauthorized},
+		{"password user with an OAuth token only", passwordUser.ID, user.DeleteSelfCommand{OAuthToken: "fresh-id-token"}, constants.ErrorUnauthorized},
+		{"OAuth user with a fresh token", oauthUser.ID, user.DeleteSelfCommand{OAuthToken: "fresh-id-token"}, nil},
+		{"OAuth user without a token", oauthUser.ID, user.DeleteSelfCommand{}, constants.ErrorUnauthorized},
+		{"OAuth user with an invalid token", oauthUser.ID, user.DeleteSelfCommand{OAuthToken: "stale-id-token"}, constants.ErrorUnauthorized},
+		{"user without credentials", noCredentialUser.ID, user.DeleteSelfCommand{}, constants.ErrorUnauthorized},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := deletionService.DeleteSelf(tc.command, selfContext(tc.userID))
+			if tc.wantErr == nil && err != nil {
+				t.Fatalf("Expected the deletion to be scheduled, got %v", err)
+			}
+			if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Expected %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	if repo.users[oauthUser.ID].DeletionScheduledAt == nil {
+		t.Errorf("Expected the deletion of the OAuth user to be scheduled")
+	}
+}
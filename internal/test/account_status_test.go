@@ -0,0 +1,57 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+)
+
+func TestCheckAccountStatus(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		status   *user.AccountStatus
+		expected error
+	}{
+		{"users without a status are active", nil, nil},
+		{"reactivated", &user.AccountStatus{State: user.AccountStateActive}, nil},
+		{"suspended", &user.AccountStatus{State: user.AccountStateSuspended, Until: &future}, constants.ErrorAccountSuspended},
+		{"suspension ended", &user.AccountStatus{State: user.AccountStateSuspended, Until: &past}, nil},
+		{"banned", &user.AccountStatus{State: user.AccountStateBanned}, constants.ErrorAccountBanned},
+		{"deactivated", &user.AccountStatus{State: user.AccountStateDeactivated}, constants.ErrorAccountDeactivated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := user.Model{Status: tt.status}.CheckAccountStatus(now)
+			if tt.expected == nil && err != nil {
+				t.Errorf("Expected the account to be active, got %v", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestChangeStatusCommandValidate(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+
+	if err := (user.ChangeStatusCommand{State: user.AccountStateSuspended}).Validate(); err == nil {
+		t.Error("Expected a suspension without an end date to be invalid")
+	}
+	if err := (user.ChangeStatusCommand{State: user.AccountStateSuspended, Until: &future}).Validate(); err != nil {
+		t.Errorf("Expected the suspension to be valid, got %v", err)
+	}
+	if err := (user.ChangeStatusCommand{State: user.AccountStateBanned, Until: &future}).Validate(); err == nil {
+		t.Error("Expected a ban with an end date to be invalid")
+	}
+	if err := (user.ChangeStatusCommand{State: "FROZEN"}).Validate(); err == nil {
+		t.Error("Expected an unknown state to be invalid")
+	}
+}
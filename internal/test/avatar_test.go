@@ -0,0 +1,230 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"path/filepath"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/internal/utils"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/mongodb/patch"
+	"github.com/LydiaTrack/ground/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAvatar(t *testing.T) {
+	t.Run("DecodeImage", testDecodeAvatarImage)
+	t.Run("SquareThumbnail", testSquareThumbnail)
+	t.Run("InlineAvatar", testInlineAvatar)
+	t.Run("AvatarSize", testAvatarSize)
+	t.Run("ValidateUserAvatar", testValidateUserAvatar)
+	t.Run("MigrateInlineAvatars", testMigrateInlineAvatars)
+	t.Run("UpdateUserAvatar", testUpdateUserAvatar)
+}
+
+// racingUserRepository replaces the avatar of a user once it is read for the migration, like a concurrent upload
+type racingUserRepository struct {
+	*MockUserRepository
+	racingUserID primitive.ObjectID
+}
+
+func (r racingUserRepository) ForEach(ctx context.Context, filter interface{}, fn func(user.Model) error) error {
+	return r.MockUserRepository.ForEach(ctx, filter, func(userModel user.Model) error {
+		if userModel.ID == r.racingUserID {
+			uploaded := r.users[userModel.ID]
+			uploaded.Avatar, uploaded.AvatarID = "http://localhost:8080/avatars/uploaded", "uploaded"
+			r.users[userModel.ID] = uploaded
+		}
+		return fn(userModel)
+	})
+}
+
+// encodeTestImage encodes a width x height PNG whose left half is red and right half is blue
+func encodeTestImage(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x < width/2 {
+				img.SetRGBA(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.SetRGBA(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		t.Fatalf("Error encoding the test image: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func testDecodeAvatarImage(t *testing.T) {
+	if _, err := utils.DecodeImage(encodeTestImage(t, 40, 20), user.MaxAvatarDimension); err != nil {
+		t.Fatalf("Expected the PNG to be decoded, got %v", err)
+	}
+	if _, err := utils.DecodeImage([]byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), user.MaxAvatarDimension); !errors.Is(err, utils.ErrUnsupportedImage) {
+		t.Fatalf("Expected the SVG to be refused, got %v", err)
+	}
+	if _, err := utils.DecodeImage(encodeTestImage(t, 40, 20), 32); !errors.Is(err, utils.ErrInvalidImage) {
+		t.Fatalf("Expected the image larger than the maximum dimension to be refused, got %v", err)
+	}
+	truncated := encodeTestImage(t, 40, 20)
+	if _, err := utils.DecodeImage(truncated[:len(truncated)/2], user.MaxAvatarDimension); !errors.Is(err, utils.ErrInvalidImage) {
+		t.Fatalf("Expected the truncated image to be refused, got %v", err)
+	}
+}
+
+func testSquareThumbnail(t *testing.T) {
+	img, err := utils.DecodeImage(encodeTestImage(t, 300, 100), user.MaxAvatarDimension)
+	if err != nil {
+		t.Fatalf("Error decoding the test image: %v", err)
+	}
+
+	for _, size := range []int{64, 256} {
+		thumbnail := utils.SquareThumbnail(img, size)
+		if thumbnail.Bounds().Dx() != size || thumbnail.Bounds().Dy() != size {
+			t.Fatalf("Expected a %dx%d thumbnail, got %v", size, size, thumbnail.Bounds())
+		}
+		// The centered square is split in the middle between red and blue
+		if left := thumbnail.RGBAAt(0, size/2); left.R != 255 || left.B != 0 {
+			t.Errorf("Expected the left edge to be red, got %v", left)
+		}
+		if right := thumbnail.RGBAAt(size-1, size/2); right.B != 255 || right.R != 0 {
+			t.Errorf("Expected the right edge to be blue, got %v", right)
+		}
+	}
+}
+
+func testInlineAvatar(t *testing.T) {
+	data := encodeTestImage(t, 8, 8)
+	inline := "data:image/png;base64," + base64.StdEncoding.EncodeToString(data)
+	if !utils.IsInlineAvatar(inline) || utils.IsInlineAvatar("https://example.com/avatar.png") {
+		t.Fatalf("Expected only the data URL to be an inline avatar")
+	}
+	decoded, err := utils.DecodeInlineAvatar(inline)
+	if err != nil || !bytes.Equal(decoded, data) {
+		t.Fatalf("Expected the inline avatar to be decoded, got %v", err)
+	}
+}
+
+func testAvatarSize(t *testing.T) {
+	for requested, expected := range map[int]int{0: 256, 32: 64, 64: 64, 100: 128, 256: 256, 1024: 256} {
+		if size := user.AvatarSize(requested); size != expected {
+			t.Errorf("AvatarSize(%d) = %d, want %d", requested, size, expected)
+		}
+	}
+}
+
+func testValidateUserAvatar(t *testing.T) {
+	t.Setenv("AVATAR_BASE_URL", "http://localhost:8080/")
+	for _, avatar := range []string{"https://example.com/a.png", "http://localhost:8080/avatars/66f1c2a0b1c2d3e4f5a6b7c8"} {
+		if err := utils.ValidateUserAvatar(avatar); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", avatar, err)
+		}
+	}
+	for _, avatar := range []string{"http://example.com/a.png", "data:image/png;base64,AAAA", "http://localhost:8080/avatars/",
+		"http://localhost:8080/avatars/66f1c2", "http://localhost:8080/avatars/../secret"} {
+		if err := utils.ValidateUserAvatar(avatar); err == nil {
+			t.Errorf("Expected %q to be invalid", avatar)
+		}
+	}
+}
+
+func testMigrateInlineAvatars(t *testing.T) {
+	log.InitLogging()
+	dir := t.TempDir()
+	blobStore, err := storage.NewLocalBlobStore(dir)
+	if err != nil {
+		t.Fatalf("Error creating blob store: %v", err)
+	}
+
+	inline := "data:image/png;base64," + base64.StdEncoding.EncodeToString(encodeTestImage(t, 8, 8))
+	migratedUser := user.Model{ID: primitive.NewObjectID(), Avatar: inline}
+	invalidUser := user.Model{ID: primitive.NewObjectID(), Avatar: "data:image/png;base64,AAAA"}
+	racingUser := user.Model{ID: primitive.NewObjectID(), Avatar: inline}
+	repo := racingUserRepository{MockUserRepository: NewMockUserRepository(migratedUser, invalidUser, racingUser), racingUserID: racingUser.ID}
+	userService := service.NewUserService(repo, service.RoleService{}, nil, nil, nil)
+	avatarService := service.NewAvatarService(*userService, blobStore)
+
+	if err = avatarService.MigrateInlineAvatars(); err != nil {
+		t.Fatalf("Error migrating avatars: %v", err)
+	}
+
+	migrated := repo.users[migratedUser.ID]
+	if migrated.AvatarID == "" || migrated.Avatar != utils.AvatarURL(migrated.AvatarID) {
+		t.Errorf("Expected the inline avatar to be migrated, got %q", migrated.Avatar)
+	}
+	if invalid := repo.users[invalidUser.ID]; invalid.Avatar != "" {
+		t.Errorf("Expected the invalid inline avatar to be removed, got %q", invalid.Avatar)
+	}
+	if racing := repo.users[racingUser.ID]; racing.AvatarID != "uploaded" {
+		t.Errorf("Expected the concurrently uploaded avatar to be kept, got %q", racing.AvatarID)
+	}
+
+	// Only the sizes of the migrated avatar are left in the blob store
+	stored, err := filepath.Glob(filepath.Join(dir, "avatars", "*", "*"))
+	if err != nil || len(stored) != len(user.AvatarSizes) {
+		t.Errorf("Expected only the %d sizes of the migrated avatar to be stored, got %v", len(user.AvatarSizes), stored)
+	}
+	for _, path := range stored {
+		if filepath.Base(filepath.Dir(path)) != migrated.AvatarID {
+			t.Errorf("Expected the avatar stored for the concurrently updated user to be deleted, got %s", path)
+		}
+	}
+}
+
+func testUpdateUserAvatar(t *testing.T) {
+	log.InitLogging()
+	t.Setenv("AVATAR_BASE_URL", "http://localhost:8080")
+	dir := t.TempDir()
+	blobStore, err := storage.NewLocalBlobStore(dir)
+	if err != nil {
+		t.Fatalf("Error creating blob store: %v", err)
+	}
+	owner := user.Model{ID: primitive.NewObjectID(), Username: "owner"}
+	other := user.Model{ID: primitive.NewObjectID(), Username: "other"}
+	repo := NewMockUserRepository(owner, other)
+	userService := service.NewUserService(repo, service.RoleService{}, nil, nil, blobStore)
+	avatarService := service.NewAvatarService(*userService, blobStore)
+	authContext := auth.PermissionContext{Permissions: []auth.Permission{permissions.UserUpdatePermission}}
+
+	uploaded, err := avatarService.Upload(owner.ID.Hex(), encodeTestImage(t, 8, 8), authContext)
+	if err != nil {
+		t.Fatalf("Error uploading avatar: %v", err)
+	}
+	patchAvatar := func(userID primitive.ObjectID, avatar string) (user.Model, error) {
+		return userService.Patch(userID.Hex(), user.PatchUserCommand{
+			ContentType: patch.MergePatchContentType,
+			Patch:       []byte(`{"avatar":"` + avatar + `"}`),
+		}, authContext)
+	}
+
+	if _, err = patchAvatar(other.ID, uploaded.Avatar); !errors.Is(err, constants.ErrorBadRequest) {
+		t.Errorf("Expected the avatar of another user to be refused, got %v", err)
+	}
+	if _, err = patchAvatar(owner.ID, utils.AvatarURL(primitive.NewObjectID().Hex())); !errors.Is(err, constants.ErrorBadRequest) {
+		t.Errorf("Expected an avatar that was not uploaded to be refused, got %v", err)
+	}
+
+	updated, err := patchAvatar(owner.ID, "https://example.com/a.png")
+	if err != nil {
+		t.Fatalf("Error updating avatar: %v", err)
+	}
+	if updated.AvatarID != "" {
+		t.Errorf("Expected the ID of the replaced avatar to be removed, got %q", updated.AvatarID)
+	}
+	if stored, _ := filepath.Glob(filepath.Join(dir, "avatars", "*", "*")); len(stored) != 0 {
+		t.Errorf("Expected the replaced avatar to be deleted, got %v", stored)
+	}
+}
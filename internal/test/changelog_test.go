@@ -0,0 +1,103 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/changelog"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestChangelog(t *testing.T) {
+	t.Run("ParseVersion", testParseChangelogVersion)
+	t.Run("CompareVersions", testCompareChangelogVersions)
+	t.Run("Unseen", testUnseenChangelog)
+}
+
+func testParseChangelogVersion(t *testing.T) {
+	valid := map[string]string{
+		"1.2.3":              "1.2.3",
+		"v1.2.3":             "1.2.3",
+		"2.0.0-beta.1":       "2.0.0-beta.1",
+		"1.0.0-rc.1+build.5": "1.0.0-rc.1",
+	}
+	for version, expected := range valid {
+		parsed, err := changelog.ParseVersion(version)
+		if err != nil {
+			t.Errorf("Expected %s to be valid, got %v", version, err)
+			continue
+		}
+		if parsed.String() != expected {
+			t.Errorf("Expected %s to be %s, got %s", version, expected, parsed.String())
+		}
+	}
+
+	for _, version := range []string{"", "1.2", "1.2.3.4", "01.2.3", "1.2.x", "1.2.3-", "1.2.3-01", "1.2.3-beta..1"} {
+		if _, err := changelog.ParseVersion(version); err == nil {
+			t.Errorf("Expected %q to be invalid", version)
+		}
+	}
+}
+
+func testCompareChangelogVersions(t *testing.T) {
+	// Ordered by precedence as in the semantic versioning specification
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.2.0", "1.10.0", "2.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		lower, _ := changelog.ParseVersion(ordered[i-1])
+		higher, _ := changelog.ParseVersion(ordered[i])
+		if lower.Compare(higher) != -1 || higher.Compare(lower) != 1 {
+			t.Errorf("Expected %s to be lower than %s", ordered[i-1], ordered[i])
+		}
+	}
+	a, _ := changelog.ParseVersion("v1.0.0+build")
+	b, _ := changelog.ParseVersion("1.0.0")
+	if a.Compare(b) != 0 {
+		t.Errorf("Expected the build metadata to be ignored")
+	}
+}
+
+func testUnseenChangelog(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	adminRoleID, userRoleID := primitive.NewObjectID(), primitive.NewObjectID()
+	entry := func(version string, publishDate time.Time, audience ...primitive.ObjectID) changelog.Model {
+		return changelog.Model{ID: primitive.NewObjectID(), Version: version, Title: version, PublishDate: publishDate, AudienceRoleIDs: audience}
+	}
+	entries := []changelog.Model{
+		entry("1.2.0", now.Add(-time.Hour)),
+		entry("1.10.0", now.Add(-time.Minute)),
+		entry("1.9.0", now.Add(-2*time.Hour), adminRoleID),
+		entry("2.0.0", now.Add(time.Hour)),
+		entry("1.0.0", now.Add(-24*time.Hour)),
+	}
+
+	unseen := changelog.Unseen(entries, "1.1.0", []primitive.ObjectID{userRoleID}, now)
+	if versions := changelogVersions(unseen); versions != "1.10.0,1.2.0" {
+		t.Errorf("Expected the published entries after 1.1.0 for the user, got %s", versions)
+	}
+
+	unseen = changelog.Unseen(entries, "1.1.0", []primitive.ObjectID{adminRoleID}, now)
+	if versions := changelogVersions(unseen); versions != "1.10.0,1.9.0,1.2.0" {
+		t.Errorf("Expected the entries of the admin audience too, got %s", versions)
+	}
+
+	unseen = changelog.Unseen(entries, "", nil, now)
+	if versions := changelogVersions(unseen); versions != "1.10.0,1.2.0,1.0.0" {
+		t.Errorf("Expected every published entry without a last seen version, got %s", versions)
+	}
+
+	if unseen = changelog.Unseen(entries, "1.10.0", nil, now); len(unseen) != 0 {
+		t.Errorf("Expected no unseen entries after the newest one, got %s", changelogVersions(unseen))
+	}
+}
+
+func changelogVersions(entries []changelog.Model) string {
+	versions := ""
+	for i, entry := range entries {
+		if i > 0 {
+			versions += ","
+		}
+		versions += entry.Version
+	}
+	return versions
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/dataexport"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"github.com/LydiaTrack/ground/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MockDataExportRepository is an in-memory implementation of DataExportRepository, safe for the export workers
+type MockDataExportRepository struct {
+	service.DataExportRepository
+	mu      sync.Mutex
+	exports map[primitive.ObjectID]dataexport.Model
+}
+
+func NewMockDataExportRepository() *MockDataExportRepository {
+	return &MockDataExportRepository{exports: make(map[primitive.ObjectID]dataexport.Model)}
+}
+
+func (m *MockDataExportRepository) Create(_ context.Context, entity dataexport.Model) (*mongo.InsertOneResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.exports[entity.ID] = entity
+	return &mongo.InsertOneResult{InsertedID: entity.ID}, nil
+}
+
+func (m *MockDataExportRepository) Complete(id primitive.ObjectID, archiveKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exportModel := m.exports[id]
+	exportModel.Status = dataexport.StatusFailed
+	if archiveKey != "" {
+		exportModel.Status = dataexport.StatusReady
+		exportModel.ArchiveKey = archiveKey
+	}
+	m.exports[id] = exportModel
+	return nil
+}
+
+func (m *MockDataExportRepository) GetByUserID(userID primitive.ObjectID) ([]dataexport.Model, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var exports []dataexport.Model
+	for _, exportModel := range m.exports {
+		if exportModel.UserID == userID {
+			exports = append(exports, exportModel)
+		}
+	}
+	return exports, nil
+}
+
+func (m *MockDataExportRepository) DeleteByUserID(userID primitive.ObjectID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, exportModel := range m.exports {
+		if exportModel.UserID == userID {
+			delete(m.exports, id)
+		}
+	}
+	return nil
+}
+
+func (m *MockDataExportRepository) get(id primitive.ObjectID) dataexport.Model {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.exports[id]
+}
+
+func TestDataExportArchive(t *testing.T) {
+	log.InitLogging()
+	blobStore, err := storage.NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error creating blob store: %v", err)
+	}
+
+	userModel := user.Model{ID: primitive.NewObjectID(), Username: "data-export-user"}
+	repo := NewMockDataExportRepository()
+	userService := service.NewUserService(NewMockUserRepository(userModel), service.RoleService{}, nil, nil, nil)
+	exportService := service.NewDataExportService(repo, *userService, blobStore)
+
+	exportModel, err := exportService.ExportSelf(auth.PermissionContext{
+		Permissions: []auth.Permission{permissions.UserSelfExportPermission},
+		UserID:      &userModel.ID,
+	})
+	if err != nil {
+		t.Fatalf("Error requesting export: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for repo.get(exportModel.ID).Status == dataexport.StatusPending && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	completed := repo.get(exportModel.ID)
+	if completed.Status != dataexport.StatusReady || completed.ArchiveKey != dataexport.ArchiveKey(exportModel.ID) {
+		t.Fatalf("Expected a ready export with its archive key, got %s %q", completed.Status, completed.ArchiveKey)
+	}
+	archive, err := blobStore.Get(context.Background(), completed.ArchiveKey)
+	if err != nil || archive.ContentType != "application/zip" || len(archive.Data) == 0 {
+		t.Fatalf("Expected the archive in the blob store, got %v", err)
+	}
+
+	// Purging the user deletes the archives with the exports
+	if err = exportService.CleanupUser(userModel.ID); err != nil {
+		t.Fatalf("Error cleaning up exports: %v", err)
+	}
+	if _, err = blobStore.Get(context.Background(), completed.ArchiveKey); !errors.Is(err, storage.ErrBlobNotFound) {
+		t.Errorf("Expected the archive to be deleted, got %v", err)
+	}
+}
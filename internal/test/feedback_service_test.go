@@ -8,6 +8,7 @@ import (
 
 	"github.com/LydiaTrack/ground/internal/templates"
 	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
 	"github.com/LydiaTrack/ground/pkg/registry"
 
 	"github.com/LydiaTrack/ground/internal/repository"
@@ -16,6 +17,7 @@ import (
 	"github.com/LydiaTrack/ground/pkg/domain/user"
 	"github.com/LydiaTrack/ground/pkg/test_support"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 var (
@@ -28,7 +30,7 @@ func initializeFeedbackService() {
 	if !initializedFeedback {
 		test_support.TestWithMongo()
 		roleService := service.NewRoleService(repository.GetRoleMongoRepository())
-		usrService = *service.NewUserService(repository.GetUserMongoRepository(repository.GetRoleMongoRepository()), *roleService, nil)
+		usrService = *service.NewUserService(repository.GetUserMongoRepository(repository.GetRoleMongoRepository()), *roleService, nil, nil, nil)
 		repo := repository.GetFeedbackRepository()
 		feedbackService = *service.NewFeedbackService(repo, usrService)
 		registerFeedbackEmailTemplate()
@@ -127,8 +129,14 @@ func testCreateFeedback(t *testing.T) {
 		Type:    feedback.FeatureRequest,
 	}
 
+	// The user submits feedback on their own behalf
+	ownerContext := auth.PermissionContext{
+		Permissions: []auth.Permission{},
+		UserID:      &userModel.ID,
+	}
+
 	// Create the feedback
-	createdFeedback, err := feedbackService.CreateFeedback(command)
+	createdFeedback, err := feedbackService.CreateFeedback(command, ownerContext)
 	if err != nil {
 		t.Errorf("Error creating feedback: %s", err)
 	}
@@ -138,7 +146,7 @@ func testCreateFeedback(t *testing.T) {
 	}
 
 	// Check if the feedback exists
-	exists, err := feedbackService.ExistsFeedback(createdFeedback.ID.Hex())
+	exists, err := feedbackService.ExistsFeedback(createdFeedback.ID.Hex(), auth.CreateAdminAuthContext())
 	if err != nil {
 		t.Errorf("Error checking if feedback exists: %s", err)
 	}
@@ -148,7 +156,7 @@ func testCreateFeedback(t *testing.T) {
 	}
 
 	// Retrieve the feedback and verify
-	retrievedFeedback, err := feedbackService.GetFeedback(createdFeedback.ID.Hex())
+	retrievedFeedback, err := feedbackService.GetFeedback(createdFeedback.ID.Hex(), ownerContext)
 	if err != nil {
 		t.Errorf("Error retrieving feedback: %s", err)
 	}
@@ -174,18 +182,23 @@ func testGetFeedbackByUser(t *testing.T) {
 		Type:    feedback.General,
 	}
 
-	_, err := feedbackService.CreateFeedback(command1)
+	ownerContext := auth.PermissionContext{
+		Permissions: []auth.Permission{},
+		UserID:      &userID,
+	}
+
+	_, err := feedbackService.CreateFeedback(command1, ownerContext)
 	if err != nil {
 		t.Errorf("Error creating first feedback: %s", err)
 	}
 
-	_, err = feedbackService.CreateFeedback(command2)
+	_, err = feedbackService.CreateFeedback(command2, ownerContext)
 	if err != nil {
 		t.Errorf("Error creating second feedback: %s", err)
 	}
 
 	// Retrieve feedbacks by user
-	feedbacks, err := feedbackService.GetFeedbacksByUser(userID.Hex())
+	feedbacks, err := feedbackService.GetFeedbacksByUser(userID.Hex(), ownerContext)
 	if err != nil {
 		t.Errorf("Error retrieving feedbacks by user: %s", err)
 	}
@@ -197,4 +210,54 @@ func testGetFeedbackByUser(t *testing.T) {
 	if feedbacks[0].UserID != userID || feedbacks[1].UserID != userID {
 		t.Errorf("Feedback entries do not match the expected user ID")
 	}
+
+	// Another user without feedback permissions must not see them
+	otherUserID := primitive.NewObjectID()
+	_, err = feedbackService.GetFeedbacksByUser(userID.Hex(), auth.PermissionContext{
+		Permissions: []auth.Permission{},
+		UserID:      &otherUserID,
+	})
+	if err != constants.ErrorPermissionDenied {
+		t.Errorf("Expected ErrorPermissionDenied, got: %v", err)
+	}
+}
+
+// singleFeedbackRepository only knows a single feedback record
+type singleFeedbackRepository struct {
+	service.FeedbackRepository
+	feedback feedback.Model
+}
+
+func (r singleFeedbackRepository) GetFeedback(id primitive.ObjectID) (feedback.Model, error) {
+	if id != r.feedback.ID {
+		return feedback.Model{}, mongo.ErrNoDocuments
+	}
+	return r.feedback, nil
+}
+
+func TestFeedbackExistenceHidden(t *testing.T) {
+	t.Setenv("EMAIL_TYPE_FEEDBACK_PORT", "587")
+	ownerID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+	existing := feedback.Model{ID: primitive.NewObjectID(), UserID: ownerID}
+	feedbackService := service.NewFeedbackService(singleFeedbackRepository{feedback: existing}, service.UserService{})
+	otherContext := auth.PermissionContext{Permissions: []auth.Permission{}, UserID: &otherID}
+	missingID := primitive.NewObjectID().Hex()
+
+	// Another user is denied the same way whether the feedback exists or not
+	for _, id := range []string{existing.ID.Hex(), missingID} {
+		if _, err := feedbackService.GetFeedback(id, otherContext); err != constants.ErrorPermissionDenied {
+			t.Errorf("Expected ErrorPermissionDenied reading %s, got %v", id, err)
+		}
+		if err := feedbackService.UpdateFeedbackStatus(id, feedback.Resolved, otherContext); err != constants.ErrorPermissionDenied {
+			t.Errorf("Expected ErrorPermissionDenied updating %s, got %v", id, err)
+		}
+	}
+
+	// The owner still reads its feedback, an admin learns that a feedback does not exist
+	if _, err := feedbackService.GetFeedback(existing.ID.Hex(), auth.PermissionContext{UserID: &ownerID}); err != nil {
+		t.Errorf("Expected the owner to read the feedback, got %v", err)
+	}
+	if err := feedbackService.UpdateFeedbackStatus(missingID, feedback.Resolved, auth.CreateAdminAuthContext()); err != constants.ErrorNotFound {
+		t.Errorf("Expected ErrorNotFound for an admin, got %v", err)
+	}
 }
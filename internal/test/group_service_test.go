@@ -0,0 +1,62 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/group"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGroupNesting(t *testing.T) {
+	repo := NewMockGroupRepository()
+	groupService := service.NewGroupService(repo, service.UserService{}, service.RoleService{}, service.AuditService{})
+	adminContext := auth.CreateAdminAuthContext()
+
+	// Engineering > Backend > Platform
+	readerRoleID, writerRoleID := primitive.NewObjectID(), primitive.NewObjectID()
+	engineering := group.Model{ID: primitive.NewObjectID(), Name: "Engineering", RoleIDs: []primitive.ObjectID{readerRoleID}}
+	backend := group.Model{ID: primitive.NewObjectID(), Name: "Backend", ParentIDs: []primitive.ObjectID{engineering.ID}}
+	platform := group.Model{ID: primitive.NewObjectID(), Name: "Platform", RoleIDs: []primitive.ObjectID{writerRoleID},
+		ParentIDs: []primitive.ObjectID{backend.ID}}
+	repo.groups[engineering.ID] = engineering
+	repo.groups[backend.ID] = backend
+	repo.groups[platform.ID] = platform
+
+	t.Run("members hold the roles of the ancestor groups", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		repo.members = append(repo.members, group.Member{ID: primitive.NewObjectID(), GroupID: platform.ID, UserID: userID})
+
+		roleIDs, err := groupService.GetUserRoleIDs(userID)
+		if err != nil {
+			t.Fatalf("Error getting role IDs: %v", err)
+		}
+		if len(roleIDs) != 2 || !containsObjectID(roleIDs, readerRoleID) || !containsObjectID(roleIDs, writerRoleID) {
+			t.Errorf("Expected the reader and writer roles, got %v", roleIDs)
+		}
+	})
+
+	t.Run("nesting a group in its descendant is rejected", func(t *testing.T) {
+		_, err := groupService.Update(engineering.ID.Hex(), group.UpdateGroupCommand{
+			Name:      engineering.Name,
+			ParentIDs: []primitive.ObjectID{platform.ID},
+		}, adminContext)
+		if !errors.Is(err, constants.ErrorBadRequest) {
+			t.Errorf("Expected bad request for a cycle, got %v", err)
+		}
+	})
+
+	t.Run("nesting a group in itself is rejected", func(t *testing.T) {
+		_, err := groupService.Update(backend.ID.Hex(), group.UpdateGroupCommand{
+			Name:      backend.Name,
+			ParentIDs: []primitive.ObjectID{backend.ID},
+		}, adminContext)
+		if !errors.Is(err, constants.ErrorBadRequest) {
+			t.Errorf("Expected bad request for a self reference, got %v", err)
+		}
+	})
+
+}
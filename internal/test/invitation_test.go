@@ -0,0 +1,147 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/invitation"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MockInvitationRepository is an in-memory implementation of the InvitationRepository methods accepting uses
+type MockInvitationRepository struct {
+	service.InvitationRepository
+	invitations map[primitive.ObjectID]invitation.Model
+}
+
+func (m *MockInvitationRepository) GetByTokenHash(tokenHash string) (invitation.Model, error) {
+	for _, invitationModel := range m.invitations {
+		if invitationModel.TokenHash == tokenHash {
+			return invitationModel, nil
+		}
+	}
+	return invitation.Model{}, mongo.ErrNoDocuments
+}
+
+func (m *MockInvitationRepository) UpdateStatus(id primitive.ObjectID, status invitation.Status, acceptedUserID *primitive.ObjectID) (bool, error) {
+	invitationModel, ok := m.invitations[id]
+	if !ok || invitationModel.Status != invitation.StatusPending {
+		return false, nil
+	}
+	invitationModel.Status = status
+	invitationModel.AcceptedUserID = acceptedUserID
+	m.invitations[id] = invitationModel
+	return true, nil
+}
+
+// hashInvitationToken hashes the token like the invitation service stores it
+func hashInvitationToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestInvitationRoles(t *testing.T) {
+	log.InitLogging()
+	roleService := service.NewRoleService(NewMockRoleRepository())
+	adminRole, err := roleService.Create(role.CreateRoleCommand{Name: "Admin", Permissions: []auth.Permission{auth.AdminPermission}}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error creating role: %v", err)
+	}
+	editorRole, err := roleService.Create(role.CreateRoleCommand{Name: "Editor", Permissions: []auth.Permission{
+		permissions.InvitationCreatePermission, {Domain: "report", Action: "EDIT"},
+	}}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error creating role: %v", err)
+	}
+
+	// The inviter only holds the editor role
+	inviter := user.Model{ID: primitive.NewObjectID(), Username: "inviter", RoleIDs: &[]primitive.ObjectID{editorRole.ID}}
+	userRepository := NewMockUserRepository(inviter)
+	userService := service.NewUserService(userRepository, *roleService, nil, nil, nil)
+	invitationRepository := &MockInvitationRepository{invitations: make(map[primitive.ObjectID]invitation.Model)}
+	invitationService := service.NewInvitationService(invitationRepository, *userService, *roleService)
+	inviterContext := auth.PermissionContext{Permissions: editorRole.Permissions, UserID: &inviter.ID}
+
+	t.Run("CheckAssignable", func(t *testing.T) {
+		if err := roleService.CheckAssignable([]primitive.ObjectID{editorRole.ID}, inviterContext); err != nil {
+			t.Errorf("Expected the role of the caller to be assignable, got %v", err)
+		}
+		if err := roleService.CheckAssignable([]primitive.ObjectID{adminRole.ID}, inviterContext); !errors.Is(err, constants.ErrorPermissionDenied) {
+			t.Errorf("Expected the role with more permissions to be refused, got %v", err)
+		}
+		assignContext := auth.PermissionContext{Permissions: []auth.Permission{permissions.RoleAssignPermission}}
+		if err := roleService.CheckAssignable([]primitive.ObjectID{adminRole.ID}, assignContext); err != nil {
+			t.Errorf("Expected the role assign permission to allow any role, got %v", err)
+		}
+		if err := roleService.CheckAssignable([]primitive.ObjectID{primitive.NewObjectID()}, assignContext); !errors.Is(err, constants.ErrorNotFound) {
+			t.Errorf("Expected a missing role to be not found, got %v", err)
+		}
+	})
+
+	t.Run("CreateRefusesRolesBeyondTheInviter", func(t *testing.T) {
+		_, err := invitationService.Create(invitation.CreateInvitationCommand{
+			Email:   "invitee@example.com",
+			RoleIDs: []primitive.ObjectID{adminRole.ID},
+		}, inviterContext)
+		if !errors.Is(err, constants.ErrorPermissionDenied) {
+			t.Errorf("Expected the admin role to be refused, got %v", err)
+		}
+	})
+
+	addInvitation := func(token string, roleIDs ...primitive.ObjectID) invitation.Model {
+		invitationModel, err := invitation.NewInvitation(
+			invitation.WithEmail(token+"@example.com"),
+			invitation.WithRoleIDs(roleIDs),
+			invitation.WithTokenHash(hashInvitationToken(token)),
+			invitation.WithInvitedBy(&inviter.ID),
+			invitation.WithExpiresAt(time.Now().Add(time.Hour)),
+		)
+		if err != nil {
+			t.Fatalf("Error creating invitation: %v", err)
+		}
+		invitationRepository.invitations[invitationModel.ID] = *invitationModel
+		return *invitationModel
+	}
+
+	t.Run("AcceptChecksTheInviter", func(t *testing.T) {
+		addInvitation("stale-admin-invite", adminRole.ID)
+		_, err := invitationService.Accept(invitation.AcceptInvitationCommand{
+			Token: "stale-admin-invite", Username: "stale-admin", Password: "secret123",
+		})
+		if !errors.Is(err, constants.ErrorPermissionDenied) {
+			t.Fatalf("Expected the admin role to be refused, got %v", err)
+		}
+		if userRepository.ExistsByUsernameAndEmail("stale-admin", "") {
+			t.Errorf("Expected no account to be created")
+		}
+	})
+
+	t.Run("AcceptGrantsTheRolesWithTheAccount", func(t *testing.T) {
+		invitationModel := addInvitation("editor-invite", editorRole.ID)
+		userModel, err := invitationService.Accept(invitation.AcceptInvitationCommand{
+			Token: "editor-invite", Username: "editor", Password: "secret123",
+		})
+		if err != nil {
+			t.Fatalf("Error accepting invitation: %v", err)
+		}
+		stored := userRepository.users[userModel.ID]
+		if len(stored.RoleAssignments) != 1 || stored.RoleAssignments[0].RoleID != editorRole.ID ||
+			*stored.RoleAssignments[0].GrantedBy != inviter.ID {
+			t.Errorf("Expected the editor role granted by the inviter, got %+v", stored.RoleAssignments)
+		}
+		if accepted := invitationRepository.invitations[invitationModel.ID]; accepted.Status != invitation.StatusAccepted {
+			t.Errorf("Expected the invitation to be accepted, got %s", accepted.Status)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/LydiaTrack/ground/pkg/domain/group"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/responses"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MockGroupRepository is an in-memory implementation of GroupRepository for testing
+type MockGroupRepository struct {
+	groups  map[primitive.ObjectID]group.Model
+	members []group.Member
+}
+
+func NewMockGroupRepository() *MockGroupRepository {
+	return &MockGroupRepository{
+		groups: make(map[primitive.ObjectID]group.Model),
+	}
+}
+
+func (m *MockGroupRepository) Create(_ context.Context, entity group.Model) (*mongo.InsertOneResult, error) {
+	m.groups[entity.ID] = entity
+	return &mongo.InsertOneResult{InsertedID: entity.ID}, nil
+}
+
+func (m *MockGroupRepository) GetByID(_ context.Context, id interface{}) (group.Model, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return group.Model{}, err
+	}
+	groupModel, ok := m.groups[objID]
+	if !ok {
+		return group.Model{}, mongo.ErrNoDocuments
+	}
+	return groupModel, nil
+}
+
+func (m *MockGroupRepository) Update(_ context.Context, id interface{}, update interface{}) (*mongo.UpdateResult, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	cmd, ok := update.(group.UpdateGroupCommand)
+	if !ok {
+		return nil, errors.New("unsupported update")
+	}
+	groupModel := m.groups[objID]
+	groupModel.Name = cmd.Name
+	groupModel.Description = cmd.Description
+	if cmd.RoleIDs != nil {
+		groupModel.RoleIDs = cmd.RoleIDs
+	}
+	if cmd.ParentIDs != nil {
+		groupModel.ParentIDs = cmd.ParentIDs
+	}
+	m.groups[objID] = groupModel
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (m *MockGroupRepository) Delete(_ context.Context, id interface{}) (*mongo.DeleteResult, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	delete(m.groups, objID)
+	return &mongo.DeleteResult{DeletedCount: 1}, nil
+}
+
+func (m *MockGroupRepository) Exists(_ context.Context, _ interface{}) (bool, error) {
+	return len(m.groups) > 0, nil
+}
+
+func (m *MockGroupRepository) ExistsByID(_ context.Context, id interface{}) (bool, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return false, err
+	}
+	_, ok := m.groups[objID]
+	return ok, nil
+}
+
+func (m *MockGroupRepository) Query(_ context.Context, filter interface{}, _ []string, _ string) (responses.QueryResult[group.Model], error) {
+	var result []group.Model
+	ids := idsFromInFilter(filter)
+	for _, groupModel := range m.groups {
+		if ids == nil || ids[groupModel.ID] {
+			result = append(result, groupModel)
+		}
+	}
+	return *responses.NewQueryResult(len(result), result), nil
+}
+
+func (m *MockGroupRepository) QueryPaginate(ctx context.Context, filter interface{}, searchFields []string, searchText string, page, limit int, _ interface{}) (responses.PaginatedResult[group.Model], error) {
+	result, err := m.Query(ctx, filter, searchFields, searchText)
+	if err != nil {
+		return responses.PaginatedResult[group.Model]{}, err
+	}
+	return responses.PaginatedResult[group.Model]{Data: result.Data, TotalElements: int64(result.TotalElements), Page: page, Limit: limit}, nil
+}
+
+func (m *MockGroupRepository) QueryCursor(ctx context.Context, filter interface{}, searchFields []string, searchText string, request repository.CursorRequest) (responses.CursorResult[group.Model], error) {
+	result, err := m.Query(ctx, filter, searchFields, searchText)
+	if err != nil {
+		return responses.CursorResult[group.Model]{}, err
+	}
+	return responses.CursorResult[group.Model]{Data: result.Data, Limit: request.Limit}, nil
+}
+
+func (m *MockGroupRepository) ExistsByName(name string) (bool, error) {
+	for _, groupModel := range m.groups {
+		if groupModel.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockGroupRepository) CountChildGroups(groupID primitive.ObjectID) (int64, error) {
+	var count int64
+	for _, groupModel := range m.groups {
+		if containsObjectID(groupModel.ParentIDs, groupID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockGroupRepository) RemoveParentFromGroups(groupID primitive.ObjectID) error {
+	for id, groupModel := range m.groups {
+		groupModel.ParentIDs = removeObjectID(groupModel.ParentIDs, groupID)
+		m.groups[id] = groupModel
+	}
+	return nil
+}
+
+func (m *MockGroupRepository) AddMember(member group.Member) error {
+	m.members = append(m.members, member)
+	return nil
+}
+
+func (m *MockGroupRepository) IsMember(groupID primitive.ObjectID, userID primitive.ObjectID) (bool, error) {
+	for _, member := range m.members {
+		if member.GroupID == groupID && member.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockGroupRepository) RemoveMember(groupID primitive.ObjectID, userID primitive.ObjectID) error {
+	var members []group.Member
+	for _, member := range m.members {
+		if member.GroupID != groupID || member.UserID != userID {
+			members = append(members, member)
+		}
+	}
+	m.members = members
+	return nil
+}
+
+func (m *MockGroupRepository) RemoveMembers(groupID primitive.ObjectID) error {
+	var members []group.Member
+	for _, member := range m.members {
+		if member.GroupID != groupID {
+			members = append(members, member)
+		}
+	}
+	m.members = members
+	return nil
+}
+
+func (m *MockGroupRepository) RemoveMemberships(userID primitive.ObjectID) error {
+	var members []group.Member
+	for _, member := range m.members {
+		if member.UserID != userID {
+			members = append(members, member)
+		}
+	}
+	m.members = members
+	return nil
+}
+
+func (m *MockGroupRepository) QueryMembersPaginated(groupID primitive.ObjectID, page, limit int) (responses.PaginatedResult[group.Member], error) {
+	var members []group.Member
+	for _, member := range m.members {
+		if member.GroupID == groupID {
+			members = append(members, member)
+		}
+	}
+	return responses.PaginatedResult[group.Member]{Data: members, TotalElements: int64(len(members)), Page: page, Limit: limit}, nil
+}
+
+func (m *MockGroupRepository) GetGroupIDsOfUser(userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	var groupIDs []primitive.ObjectID
+	for _, member := range m.members {
+		if member.UserID == userID {
+			groupIDs = append(groupIDs, member.GroupID)
+		}
+	}
+	return groupIDs, nil
+}
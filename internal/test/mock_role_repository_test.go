@@ -0,0 +1,281 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/responses"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MockRoleRepository is an in-memory implementation of RoleRepository for testing
+type MockRoleRepository struct {
+	roles     map[primitive.ObjectID]role.Model
+	userRoles map[primitive.ObjectID][]primitive.ObjectID
+}
+
+func NewMockRoleRepository() *MockRoleRepository {
+	return &MockRoleRepository{
+		roles:     make(map[primitive.ObjectID]role.Model),
+		userRoles: make(map[primitive.ObjectID][]primitive.ObjectID),
+	}
+}
+
+func (m *MockRoleRepository) Create(_ context.Context, entity role.Model) (*mongo.InsertOneResult, error) {
+	m.roles[entity.ID] = entity
+	return &mongo.InsertOneResult{InsertedID: entity.ID}, nil
+}
+
+func (m *MockRoleRepository) GetByID(_ context.Context, id interface{}) (role.Model, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return role.Model{}, err
+	}
+	roleModel, ok := m.roles[objID]
+	if !ok {
+		return role.Model{}, mongo.ErrNoDocuments
+	}
+	return roleModel, nil
+}
+
+// checkVersion applies the version guard of the context like BaseRepository: a missing role matches nothing and a role
+// with another version than the expected one is a conflict
+func (m *MockRoleRepository) checkVersion(ctx context.Context, objID primitive.ObjectID) (bool, error) {
+	roleModel, ok := m.roles[objID]
+	if !ok {
+		return false, nil
+	}
+	if version, guarded := repository.ExpectedVersion(ctx); guarded && roleModel.Version != version {
+		return false, constants.ErrorVersionConflict
+	}
+	return true, nil
+}
+
+func (m *MockRoleRepository) Update(ctx context.Context, id interface{}, update interface{}) (*mongo.UpdateResult, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	cmd, ok := update.(role.UpdateRoleCommand)
+	if !ok {
+		return nil, errors.New("unsupported update")
+	}
+	if matched, err := m.checkVersion(ctx, objID); !matched {
+		return &mongo.UpdateResult{}, err
+	}
+	roleModel := m.roles[objID]
+	roleModel.Version++
+	roleModel.Name = cmd.Name
+	roleModel.Info = cmd.Info
+	roleModel.Tags = cmd.Tags
+	roleModel.Permissions = cmd.Permissions
+	if cmd.ParentIDs != nil {
+		roleModel.ParentIDs = cmd.ParentIDs
+	}
+	m.roles[objID] = roleModel
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (m *MockRoleRepository) UpdateFields(ctx context.Context, id interface{}, set bson.M, unset []string) (*mongo.UpdateResult, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	if matched, err := m.checkVersion(ctx, objID); !matched {
+		return &mongo.UpdateResult{}, err
+	}
+	roleModel := m.roles[objID]
+	roleModel.Version++
+
+	data, err := bson.Marshal(roleModel)
+	if err != nil {
+		return nil, err
+	}
+	var document bson.M
+	if err := bson.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	for path, value := range set {
+		setPath(document, strings.Split(path, "."), value)
+	}
+	for _, path := range unset {
+		setPath(document, strings.Split(path, "."), nil)
+	}
+
+	if data, err = bson.Marshal(document); err != nil {
+		return nil, err
+	}
+	var updatedRole role.Model
+	if err := bson.Unmarshal(data, &updatedRole); err != nil {
+		return nil, err
+	}
+	m.roles[objID] = updatedRole
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+// setPath sets the value at the dotted path of the document, a nil value removes it
+func setPath(document bson.M, path []string, value interface{}) {
+	if len(path) == 1 {
+		if value == nil {
+			delete(document, path[0])
+		} else {
+			document[path[0]] = value
+		}
+		return
+	}
+	child, ok := document[path[0]].(bson.M)
+	if !ok {
+		child = bson.M{}
+		document[path[0]] = child
+	}
+	setPath(child, path[1:], value)
+}
+
+func (m *MockRoleRepository) Delete(_ context.Context, id interface{}) (*mongo.DeleteResult, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	delete(m.roles, objID)
+	return &mongo.DeleteResult{DeletedCount: 1}, nil
+}
+
+func (m *MockRoleRepository) Exists(_ context.Context, _ interface{}) (bool, error) {
+	return len(m.roles) > 0, nil
+}
+
+func (m *MockRoleRepository) ExistsByID(_ context.Context, id interface{}) (bool, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return false, err
+	}
+	_, ok := m.roles[objID]
+	return ok, nil
+}
+
+func (m *MockRoleRepository) Query(_ context.Context, filter interface{}, _ []string, _ string) (responses.QueryResult[role.Model], error) {
+	var result []role.Model
+	ids := idsFromInFilter(filter)
+	for _, roleModel := range m.roles {
+		if ids == nil || ids[roleModel.ID] {
+			result = append(result, roleModel)
+		}
+	}
+	return *responses.NewQueryResult(len(result), result), nil
+}
+
+func (m *MockRoleRepository) QueryPaginate(ctx context.Context, filter interface{}, searchFields []string, searchText string, page, limit int, _ interface{}) (responses.PaginatedResult[role.Model], error) {
+	result, err := m.Query(ctx, filter, searchFields, searchText)
+	if err != nil {
+		return responses.PaginatedResult[role.Model]{}, err
+	}
+	return responses.PaginatedResult[role.Model]{Data: result.Data, TotalElements: int64(result.TotalElements), Page: page, Limit: limit}, nil
+}
+
+func (m *MockRoleRepository) QueryCursor(ctx context.Context, filter interface{}, searchFields []string, searchText string, request repository.CursorRequest) (responses.CursorResult[role.Model], error) {
+	result, err := m.Query(ctx, filter, searchFields, searchText)
+	if err != nil {
+		return responses.CursorResult[role.Model]{}, err
+	}
+	return responses.CursorResult[role.Model]{Data: result.Data, Limit: request.Limit}, nil
+}
+
+func (m *MockRoleRepository) ExistsByName(name string) bool {
+	for _, roleModel := range m.roles {
+		if roleModel.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MockRoleRepository) GetRoleByName(name string) (role.Model, error) {
+	for _, roleModel := range m.roles {
+		if roleModel.Name == name {
+			return roleModel, nil
+		}
+	}
+	return role.Model{}, mongo.ErrNoDocuments
+}
+
+// idsFromInFilter extracts the IDs of an {"_id": {"$in": [...]}} filter, nil means no ID restriction
+func idsFromInFilter(filter interface{}) map[primitive.ObjectID]bool {
+	filterMap, ok := filter.(bson.M)
+	if !ok {
+		return nil
+	}
+	idFilter, ok := filterMap["_id"].(bson.M)
+	if !ok {
+		return nil
+	}
+	inIDs, ok := idFilter["$in"].([]primitive.ObjectID)
+	if !ok {
+		return nil
+	}
+	ids := make(map[primitive.ObjectID]bool)
+	for _, id := range inIDs {
+		ids[id] = true
+	}
+	return ids
+}
+
+func (m *MockRoleRepository) CountRoleHolders(roleID primitive.ObjectID) (int64, error) {
+	var count int64
+	for _, roleIDs := range m.userRoles {
+		if containsObjectID(roleIDs, roleID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockRoleRepository) RemoveRoleFromHolders(roleID primitive.ObjectID) error {
+	for userID, roleIDs := range m.userRoles {
+		m.userRoles[userID] = removeObjectID(roleIDs, roleID)
+	}
+	return nil
+}
+
+func (m *MockRoleRepository) CountChildRoles(roleID primitive.ObjectID) (int64, error) {
+	var count int64
+	for _, roleModel := range m.roles {
+		if containsObjectID(roleModel.ParentIDs, roleID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockRoleRepository) RemoveParentFromRoles(roleID primitive.ObjectID) error {
+	for id, roleModel := range m.roles {
+		roleModel.ParentIDs = removeObjectID(roleModel.ParentIDs, roleID)
+		m.roles[id] = roleModel
+	}
+	return nil
+}
+
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func removeObjectID(ids []primitive.ObjectID, id primitive.ObjectID) []primitive.ObjectID {
+	var result []primitive.ObjectID
+	for _, candidate := range ids {
+		if candidate != id {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package test
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/responses"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MockUserRepository is an in-memory implementation of the UserRepository methods the service tests use.
+// The other methods are not implemented and panic.
+type MockUserRepository struct {
+	service.UserRepository
+	users map[primitive.ObjectID]user.Model
+	// cursorRequests records the keyset pagination requests
+	cursorRequests []repository.CursorRequest
+}
+
+func NewMockUserRepository(users ...user.Model) *MockUserRepository {
+	m := &MockUserRepository{users: make(map[primitive.ObjectID]user.Model)}
+	for _, userModel := range users {
+		m.users[userModel.ID] = userModel
+	}
+	return m
+}
+
+func (m *MockUserRepository) GetByID(_ context.Context, id interface{}) (user.Model, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return user.Model{}, err
+	}
+	userModel, ok := m.users[objID]
+	if !ok {
+		return user.Model{}, mongo.ErrNoDocuments
+	}
+	return userModel, nil
+}
+
+func (m *MockUserRepository) Create(_ context.Context, entity user.Model) (*mongo.InsertOneResult, error) {
+	m.users[entity.ID] = entity
+	return &mongo.InsertOneResult{InsertedID: entity.ID}, nil
+}
+
+func (m *MockUserRepository) ExistsByUsernameAndEmail(username string, email string) bool {
+	for _, userModel := range m.users {
+		if userModel.Username == username || (email != "" && userModel.ContactInfo.Email == email) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MockUserRepository) GetByEmail(email string) (user.Model, error) {
+	for _, userModel := range m.users {
+		if userModel.ContactInfo.Email == email {
+			return userModel, nil
+		}
+	}
+	return user.Model{}, mongo.ErrNoDocuments
+}
+
+func (m *MockUserRepository) ExistsByID(_ context.Context, id interface{}) (bool, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return false, err
+	}
+	_, ok := m.users[objID]
+	return ok, nil
+}
+
+// Query supports the filters on a list of user IDs and on an avatar ID and returns all the users otherwise
+func (m *MockUserRepository) Query(_ context.Context, filter interface{}, _ []string, _ string) (responses.QueryResult[user.Model], error) {
+	var ids []primitive.ObjectID
+	var avatarID string
+	if filterMap, ok := filter.(bson.M); ok {
+		if idFilter, ok := filterMap["_id"].(bson.M); ok {
+			ids, _ = idFilter["$in"].([]primitive.ObjectID)
+		}
+		avatarID, _ = filterMap["avatarId"].(string)
+	}
+	users := []user.Model{}
+	for _, userModel := range m.users {
+		if (ids == nil || slices.Contains(ids, userModel.ID)) && (avatarID == "" || userModel.AvatarID == avatarID) {
+			users = append(users, userModel)
+		}
+	}
+	return *responses.NewQueryResult(len(users), users), nil
+}
+
+func (m *MockUserRepository) QueryCursor(_ context.Context, _ interface{}, _ []string, _ string, request repository.CursorRequest) (responses.CursorResult[user.Model], error) {
+	m.cursorRequests = append(m.cursorRequests, request)
+	return responses.CursorResult[user.Model]{Data: []user.Model{}, Limit: request.Limit}, nil
+}
+
+func (m *MockUserRepository) GetUserRoles(_ []primitive.ObjectID) (responses.QueryResult[role.Model], error) {
+	return *responses.NewQueryResult(0, []role.Model{}), nil
+}
+
+func (m *MockUserRepository) SetDeletionSchedule(userID primitive.ObjectID, scheduledAt *time.Time) error {
+	userModel, ok := m.users[userID]
+	if !ok {
+		return mongo.ErrNoDocuments
+	}
+	userModel.DeletionScheduledAt = scheduledAt
+	m.users[userID] = userModel
+	return nil
+}
+
+// UpdateFields sets and unsets the top-level fields of a user by their BSON names
+func (m *MockUserRepository) UpdateFields(_ context.Context, id interface{}, set bson.M, unset []string) (*mongo.UpdateResult, error) {
+	objID, err := utils.ToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	userModel, ok := m.users[objID]
+	if !ok {
+		return &mongo.UpdateResult{}, nil
+	}
+	data, err := bson.Marshal(userModel)
+	if err != nil {
+		return nil, err
+	}
+	var document bson.M
+	if err = bson.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	for key, value := range set {
+		document[key] = value
+	}
+	for _, key := range unset {
+		delete(document, key)
+	}
+	if data, err = bson.Marshal(document); err != nil {
+		return nil, err
+	}
+	var updated user.Model
+	if err = bson.Unmarshal(data, &updated); err != nil {
+		return nil, err
+	}
+	m.users[objID] = updated
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+// ForEach calls fn with every user, the filter is ignored
+func (m *MockUserRepository) ForEach(_ context.Context, _ interface{}, fn func(user.Model) error) error {
+	for _, userModel := range m.users {
+		if err := fn(userModel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockUserRepository) ReplaceInlineAvatar(userID primitive.ObjectID, inlineAvatar string, avatar string, avatarID string) (bool, error) {
+	userModel, ok := m.users[userID]
+	if !ok || userModel.Avatar != inlineAvatar {
+		return false, nil
+	}
+	userModel.Avatar, userModel.AvatarID = avatar, avatarID
+	m.users[userID] = userModel
+	return true, nil
+}
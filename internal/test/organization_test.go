@@ -0,0 +1,104 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/organization"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/tenant"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MockOrganizationRepository is an in-memory implementation of the OrganizationRepository methods the members use
+type MockOrganizationRepository struct {
+	service.OrganizationRepository
+	organizationIDs map[primitive.ObjectID]bool
+}
+
+func (m *MockOrganizationRepository) ExistsByID(_ context.Context, id interface{}) (bool, error) {
+	objID, ok := id.(primitive.ObjectID)
+	return ok && m.organizationIDs[objID], nil
+}
+
+// MockMembershipRepository is an in-memory implementation of the MembershipRepository methods the members use,
+// scoped by the organization of the context
+type MockMembershipRepository struct {
+	service.MembershipRepository
+	memberships []organization.Membership
+}
+
+func (m *MockMembershipRepository) Create(_ context.Context, entity organization.Membership) (*mongo.InsertOneResult, error) {
+	m.memberships = append(m.memberships, entity)
+	return &mongo.InsertOneResult{InsertedID: entity.ID}, nil
+}
+
+func (m *MockMembershipRepository) GetMembership(ctx context.Context, userID primitive.ObjectID) (organization.Membership, error) {
+	scope, _ := tenant.FromContext(ctx)
+	for _, membership := range m.memberships {
+		if membership.OrganizationID == scope.TenantID && membership.UserID == userID {
+			return membership, nil
+		}
+	}
+	return organization.Membership{}, mongo.ErrNoDocuments
+}
+
+func TestOrganizationMemberRoles(t *testing.T) {
+	roleService := service.NewRoleService(NewMockRoleRepository())
+	adminRole, err := roleService.Create(role.CreateRoleCommand{Name: "Admin", Permissions: []auth.Permission{auth.AdminPermission}}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error creating role: %v", err)
+	}
+	memberRole, err := roleService.Create(role.CreateRoleCommand{Name: "Member", Permissions: []auth.Permission{
+		permissions.OrganizationMemberReadPermission,
+	}}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error creating role: %v", err)
+	}
+
+	// The member also holds the admin role globally, which must not leak into the organization permissions
+	member := user.Model{ID: primitive.NewObjectID(), Username: "member", RoleIDs: &[]primitive.ObjectID{adminRole.ID}}
+	userService := service.NewUserService(NewMockUserRepository(member), *roleService, nil, nil, nil)
+	organizationID := primitive.NewObjectID()
+	organizationService := service.NewOrganizationService(
+		&MockOrganizationRepository{organizationIDs: map[primitive.ObjectID]bool{organizationID: true}},
+		&MockMembershipRepository{}, *userService, *roleService)
+
+	// The manager can manage the members only in the organization
+	managerContext := auth.PermissionContext{
+		UserID:   &primitive.ObjectID{},
+		TenantID: &organizationID,
+		TenantPermissions: []auth.Permission{
+			permissions.OrganizationMemberManagePermission, permissions.OrganizationMemberReadPermission,
+		},
+	}
+
+	_, err = organizationService.AddMember(organizationID.Hex(), organization.AddMemberCommand{
+		UserID: member.ID, RoleIDs: []primitive.ObjectID{adminRole.ID},
+	}, managerContext)
+	if !errors.Is(err, constants.ErrorPermissionDenied) {
+		t.Fatalf("Expected the admin role to be refused on the membership, got %v", err)
+	}
+
+	if _, err = organizationService.AddMember(organizationID.Hex(), organization.AddMemberCommand{
+		UserID: member.ID, RoleIDs: []primitive.ObjectID{memberRole.ID},
+	}, managerContext); err != nil {
+		t.Fatalf("Expected the role within the permissions of the manager to be granted, got %v", err)
+	}
+
+	tenantPermissions, err := organizationService.GetTenantPermissionList(member, organizationID)
+	if err != nil {
+		t.Fatalf("Error resolving the organization permissions: %v", err)
+	}
+	if !reflect.DeepEqual(tenantPermissions, memberRole.Permissions) {
+		t.Errorf("Expected only the permissions of the membership roles, got %v", tenantPermissions)
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/group"
+	"github.com/LydiaTrack/ground/pkg/domain/permission"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/registry"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPermissionExplain(t *testing.T) {
+	adminContext := auth.CreateAdminAuthContext()
+	roleService := service.NewRoleService(NewMockRoleRepository())
+
+	reviewer, err := roleService.Create(role.CreateRoleCommand{
+		Name:        "Reviewer",
+		Permissions: []auth.Permission{{Domain: "report", Action: "REVIEW"}},
+	}, adminContext)
+	if err != nil {
+		t.Fatalf("Error creating role: %v", err)
+	}
+
+	// The user holds the reviewer role only through the Auditors group
+	userModel := user.Model{ID: primitive.NewObjectID(), Username: "explain-group-member"}
+	groupRepository := NewMockGroupRepository()
+	auditors := group.Model{ID: primitive.NewObjectID(), Name: "Auditors", RoleIDs: []primitive.ObjectID{reviewer.ID}}
+	groupRepository.groups[auditors.ID] = auditors
+	groupRepository.members = append(groupRepository.members, group.Member{ID: primitive.NewObjectID(), GroupID: auditors.ID, UserID: userModel.ID})
+	groupService := service.NewGroupService(groupRepository, service.UserService{}, service.RoleService{}, service.AuditService{})
+	registry.RegisterUserRoleProvider(groupService)
+	t.Cleanup(func() {
+		registry.UnregisterUserRoleProvider(groupService)
+	})
+
+	userRepository := NewMockUserRepository(userModel)
+	userService := service.NewUserService(userRepository, *roleService, nil, nil, nil)
+	permissionService := service.NewPermissionService(*userService, *roleService)
+
+	explanation, err := permissionService.Explain(permission.ExplainCommand{
+		UserID:     userModel.ID,
+		Permission: auth.Permission{Domain: "report", Action: "REVIEW"},
+	}, adminContext)
+	if err != nil {
+		t.Fatalf("Error explaining permission: %v", err)
+	}
+	if !explanation.Allowed || explanation.Rule != auth.MatchRuleExact {
+		t.Errorf("Expected the group permission to allow the request, got %+v", explanation.Decision)
+	}
+	if len(explanation.DecidingRoles) != 1 || explanation.DecidingRoles[0].ID != reviewer.ID {
+		t.Errorf("Expected the reviewer role to decide, got %v", explanation.DecidingRoles)
+	}
+}
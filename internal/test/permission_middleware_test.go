@@ -0,0 +1,95 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	testArticleReadPermission   = auth.Permission{Domain: "article", Action: "READ"}
+	testArticleUpdatePermission = auth.Permission{Domain: "article", Action: "UPDATE"}
+)
+
+func TestPermissionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	group := r.Group("/articles")
+	// The auth context normally comes from the JWT, here it is given by the test
+	group.Use(func(c *gin.Context) {
+		auth.SetAuthContext(c, auth.PermissionContext{Permissions: []auth.Permission{testArticleReadPermission}})
+	})
+	ok := func(c *gin.Context) {
+		if _, exists := auth.GetAuthContext(c); !exists {
+			t.Errorf("Expected the auth context to be available to the handler")
+		}
+		c.Status(http.StatusOK)
+	}
+	middlewares.Handle(group, http.MethodGet, "", middlewares.All(testArticleReadPermission), ok)
+	middlewares.Handle(group, http.MethodPut, "/:id", middlewares.All(testArticleReadPermission, testArticleUpdatePermission), ok)
+	middlewares.Handle(group, http.MethodPost, "/:id", middlewares.Any(testArticleReadPermission, testArticleUpdatePermission), ok)
+
+	cases := []struct {
+		method   string
+		path     string
+		expected int
+	}{
+		{http.MethodGet, "/articles", http.StatusOK},
+		{http.MethodPut, "/articles/1", http.StatusForbidden},
+		{http.MethodPost, "/articles/1", http.StatusOK},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.expected {
+			t.Errorf("%s %s: expected status %d, got %d", tc.method, tc.path, tc.expected, w.Code)
+		}
+	}
+
+	found := false
+	for _, route := range middlewares.GetRoutePermissions() {
+		if route.Method == http.MethodPut && route.Path == "/articles/:id" {
+			found = len(route.Permissions) == 2 && route.Mode == middlewares.RequirementAll
+		}
+	}
+	if !found {
+		t.Errorf("Expected the route permission table to contain PUT /articles/:id")
+	}
+}
+
+func TestTenantScopedPermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	group := r.Group("/projects")
+	// The update permission is only held in the active organization
+	group.Use(func(c *gin.Context) {
+		auth.SetAuthContext(c, auth.PermissionContext{
+			Permissions:       []auth.Permission{testArticleReadPermission},
+			TenantPermissions: []auth.Permission{testArticleUpdatePermission},
+		})
+	})
+	ok := func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	}
+	middlewares.Handle(group, http.MethodPut, "", middlewares.All(testArticleUpdatePermission), ok)
+	middlewares.Handle(group, http.MethodPut, "/:id", middlewares.All(testArticleReadPermission, testArticleUpdatePermission).InTenant(), ok)
+
+	cases := []struct {
+		path     string
+		expected int
+	}{
+		{"/projects", http.StatusForbidden},
+		{"/projects/1", http.StatusOK},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, tc.path, nil))
+		if w.Code != tc.expected {
+			t.Errorf("PUT %s: expected status %d, got %d", tc.path, tc.expected, w.Code)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/phoneverification"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/sms"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestPhoneVerification(t *testing.T) {
+	t.Run("E164", testPhoneE164)
+	t.Run("ResendLimits", testPhoneVerificationResendLimits)
+	t.Run("Verifiable", testPhoneVerificationVerifiable)
+	t.Run("Attempts", testPhoneVerificationAttempts)
+	t.Run("SendLimits", testPhoneVerificationSendLimits)
+}
+
+// MockPhoneVerificationRepository is an in-memory implementation of PhoneVerificationRepository
+type MockPhoneVerificationRepository struct {
+	verifications map[primitive.ObjectID]phoneverification.Model
+}
+
+func NewMockPhoneVerificationRepository() *MockPhoneVerificationRepository {
+	return &MockPhoneVerificationRepository{verifications: make(map[primitive.ObjectID]phoneverification.Model)}
+}
+
+func (m *MockPhoneVerificationRepository) GetByUserID(userID primitive.ObjectID) (phoneverification.Model, error) {
+	for _, verification := range m.verifications {
+		if verification.UserID == userID {
+			return verification, nil
+		}
+	}
+	return phoneverification.Model{}, mongo.ErrNoDocuments
+}
+
+func (m *MockPhoneVerificationRepository) ReserveSend(userID primitive.ObjectID, phoneNumber, codeHash string, now time.Time) (phoneverification.Model, error) {
+	verification, err := m.GetByUserID(userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		verification = phoneverification.NewVerification(userID, now)
+	}
+	if verification.CheckResend(now) != nil {
+		return phoneverification.Model{}, mongo.ErrNoDocuments
+	}
+	verification.Renew(phoneNumber, codeHash, now)
+	m.verifications[verification.ID] = verification
+	return verification, nil
+}
+
+func (m *MockPhoneVerificationRepository) ReserveAttempt(id primitive.ObjectID) (phoneverification.Model, error) {
+	verification, ok := m.verifications[id]
+	if !ok || verification.Attempts >= phoneverification.MaxAttempts {
+		return phoneverification.Model{}, mongo.ErrNoDocuments
+	}
+	verification.Attempts++
+	m.verifications[id] = verification
+	return verification, nil
+}
+
+func (m *MockPhoneVerificationRepository) DeleteByUserID(userID primitive.ObjectID) error {
+	for id, verification := range m.verifications {
+		if verification.UserID == userID {
+			delete(m.verifications, id)
+		}
+	}
+	return nil
+}
+
+// staleVerificationRepository reads the verifications as they were before the attempts of concurrent requests
+type staleVerificationRepository struct {
+	*MockPhoneVerificationRepository
+	stale phoneverification.Model
+}
+
+func (r staleVerificationRepository) GetByUserID(primitive.ObjectID) (phoneverification.Model, error) {
+	return r.stale, nil
+}
+
+func testPhoneE164(t *testing.T) {
+	valid := map[string]user.PhoneNumber{
+		"+905321234567": {CountryCode: "+90", AreaCode: "0532", Number: "123 45 67"},
+		"+442071234567": {CountryCode: "0044", AreaCode: "20", Number: "7123-4567"},
+		"+15551234567":  {CountryCode: "1", AreaCode: "(555)", Number: "123.4567"},
+	}
+	for expected, phone := range valid {
+		e164, err := phone.E164()
+		if err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", phone, err)
+			continue
+		}
+		if e164 != expected {
+			t.Errorf("Expected %+v to be %s, got %s", phone, expected, e164)
+		}
+	}
+
+	invalid := []user.PhoneNumber{
+		{CountryCode: "+0", AreaCode: "532", Number: "1234567"},
+		{CountryCode: "1234", AreaCode: "532", Number: "1234567"},
+		{CountryCode: "90", AreaCode: "532", Number: "12a4567"},
+		{CountryCode: "90", AreaCode: "532", Number: "12345678901234"},
+	}
+	for _, phone := range invalid {
+		if _, err := phone.E164(); err == nil {
+			t.Errorf("Expected %+v to be invalid", phone)
+		}
+	}
+
+	if (user.ContactInfo{}).PhoneE164() != "" {
+		t.Errorf("Expected no phone number without a phone")
+	}
+}
+
+func testPhoneVerificationResendLimits(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	verification := phoneverification.NewVerification(primitive.NewObjectID(), now)
+	if err := verification.CheckResend(now); err != nil {
+		t.Fatalf("Expected the first code to be sent, got %v", err)
+	}
+
+	for i := 0; i < phoneverification.MaxSendsPerWindow; i++ {
+		if err := verification.CheckResend(now); err != nil {
+			t.Fatalf("Expected code %d to be sent, got %v", i+1, err)
+		}
+		verification.Renew("+905321234567", "hash", now)
+		if err := verification.CheckResend(now.Add(time.Second)); !errors.Is(err, phoneverification.ErrResendTooSoon) {
+			t.Fatalf("Expected the resend cooldown, got %v", err)
+		}
+		now = now.Add(phoneverification.ResendCooldown)
+	}
+	if err := verification.CheckResend(now); !errors.Is(err, phoneverification.ErrTooManyCodes) {
+		t.Fatalf("Expected the send limit, got %v", err)
+	}
+
+	later := verification.WindowStart.Add(phoneverification.SendWindow)
+	if err := verification.CheckResend(later); err != nil {
+		t.Fatalf("Expected a code to be sent in the next window, got %v", err)
+	}
+	verification.Renew("+905321234567", "hash", later)
+	if verification.SentCount != 1 || !verification.WindowStart.Equal(later) {
+		t.Errorf("Expected the send window to restart, got %d sends since %v", verification.SentCount, verification.WindowStart)
+	}
+}
+
+func testPhoneVerificationVerifiable(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	verification := phoneverification.NewVerification(primitive.NewObjectID(), now)
+	if err := verification.CheckVerifiable(now); !errors.Is(err, phoneverification.ErrNoPendingVerification) {
+		t.Errorf("Expected no pending code, got %v", err)
+	}
+
+	verification.Renew("+905321234567", "hash", now)
+	if err := verification.CheckVerifiable(now.Add(time.Minute)); err != nil {
+		t.Errorf("Expected the code to be verifiable, got %v", err)
+	}
+	if err := verification.CheckVerifiable(now.Add(phoneverification.CodeLifetime)); !errors.Is(err, phoneverification.ErrCodeExpired) {
+		t.Errorf("Expected the code to expire, got %v", err)
+	}
+
+	verification.Attempts = phoneverification.MaxAttempts
+	if err := verification.CheckVerifiable(now); !errors.Is(err, phoneverification.ErrTooManyAttempts) {
+		t.Errorf("Expected the code to be locked, got %v", err)
+	}
+	verification.Renew("+905321234567", "other", now.Add(phoneverification.ResendCooldown))
+	if verification.Attempts != 0 {
+		t.Errorf("Expected a new code to reset the attempts")
+	}
+}
+
+func testPhoneVerificationAttempts(t *testing.T) {
+	userModel := user.Model{ID: primitive.NewObjectID(), Username: "phone-user", ContactInfo: user.ContactInfo{
+		PhoneNumber: &user.PhoneNumber{CountryCode: "90", AreaCode: "532", Number: "1234567"},
+	}}
+	userRepository := NewMockUserRepository(userModel)
+	userService := service.NewUserService(userRepository, service.RoleService{}, nil, nil, nil)
+	repo := NewMockPhoneVerificationRepository()
+	var code string
+	sender := sms.SMSSenderFunc(func(_ context.Context, _ string, message string) error {
+		code = regexp.MustCompile(`\d{6}`).FindString(message)
+		return nil
+	})
+	authContext := auth.PermissionContext{Permissions: []auth.Permission{permissions.UserSelfUpdatePermission}, UserID: &userModel.ID}
+
+	verification, err := service.NewPhoneVerificationService(repo, *userService, sender).SendCode(authContext)
+	if err != nil || code == "" {
+		t.Fatalf("Error sending code: %v", err)
+	}
+
+	// Concurrent requests used up the attempts after the verification was read, the right code is still refused
+	stored := repo.verifications[verification.ID]
+	stored.Attempts = phoneverification.MaxAttempts
+	repo.verifications[verification.ID] = stored
+	staleService := service.NewPhoneVerificationService(staleVerificationRepository{repo, verification}, *userService, sender)
+	if _, err = staleService.Verify(phoneverification.VerifyPhoneCommand{Code: code}, authContext); !errors.Is(err, phoneverification.ErrTooManyAttempts) {
+		t.Fatalf("Expected the attempt to be refused, got %v", err)
+	}
+
+	stored.Attempts = 0
+	repo.verifications[verification.ID] = stored
+	verificationService := service.NewPhoneVerificationService(repo, *userService, sender)
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+	if _, err = verificationService.Verify(phoneverification.VerifyPhoneCommand{Code: wrongCode}, authContext); !errors.Is(err, phoneverification.ErrCodeInvalid) {
+		t.Fatalf("Expected the wrong code to be refused, got %v", err)
+	}
+	if attempts := repo.verifications[verification.ID].Attempts; attempts != 1 {
+		t.Errorf("Expected the wrong code to be counted, got %d attempts", attempts)
+	}
+
+	verified, err := verificationService.Verify(phoneverification.VerifyPhoneCommand{Code: code}, authContext)
+	if err != nil || !verified.PhoneVerified {
+		t.Fatalf("Expected the phone number to be verified, got %v", err)
+	}
+	if len(repo.verifications) != 0 {
+		t.Errorf("Expected the verification to be deleted")
+	}
+}
+
+func testPhoneVerificationSendLimits(t *testing.T) {
+	userModel := user.Model{ID: primitive.NewObjectID(), Username: "phone-sender", ContactInfo: user.ContactInfo{
+		PhoneNumber: &user.PhoneNumber{CountryCode: "90", AreaCode: "532", Number: "7654321"},
+	}}
+	userService := service.NewUserService(NewMockUserRepository(userModel), service.RoleService{}, nil, nil, nil)
+	repo := NewMockPhoneVerificationRepository()
+	sent := 0
+	sender := sms.SMSSenderFunc(func(context.Context, string, string) error {
+		sent++
+		return nil
+	})
+	authContext := auth.PermissionContext{Permissions: []auth.Permission{permissions.UserSelfUpdatePermission}, UserID: &userModel.ID}
+	verificationService := service.NewPhoneVerificationService(repo, *userService, sender)
+
+	verification, err := verificationService.SendCode(authContext)
+	if err != nil || verification.SentCount != 1 {
+		t.Fatalf("Expected the first code to be sent, got %+v (%v)", verification, err)
+	}
+	if _, err = verificationService.SendCode(authContext); !errors.Is(err, phoneverification.ErrResendTooSoon) {
+		t.Fatalf("Expected the resend cooldown, got %v", err)
+	}
+
+	// The cooldown passed, but the sends of the window are used up
+	stored := repo.verifications[verification.ID]
+	stored.LastSentAt = stored.LastSentAt.Add(-phoneverification.ResendCooldown)
+	stored.SentCount = phoneverification.MaxSendsPerWindow
+	repo.verifications[verification.ID] = stored
+	if _, err = verificationService.SendCode(authContext); !errors.Is(err, phoneverification.ErrTooManyCodes) {
+		t.Fatalf("Expected the send limit, got %v", err)
+	}
+	if sent != 1 {
+		t.Errorf("Expected only the first code to be sent, got %d messages", sent)
+	}
+}
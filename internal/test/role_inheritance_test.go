@@ -0,0 +1,188 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRoleInheritance(t *testing.T) {
+	t.Run("EffectivePermissions", testRoleEffectivePermissions)
+	t.Run("RejectCycles", testRoleRejectCycles)
+	t.Run("ClearParents", testRoleClearParents)
+	t.Run("ResolveTolerateStoredCycles", testRoleResolveStoredCycles)
+}
+
+func testRoleEffectivePermissions(t *testing.T) {
+	roleService := service.NewRoleService(NewMockRoleRepository())
+	adminContext := auth.CreateAdminAuthContext()
+
+	agent, err := roleService.Create(role.CreateRoleCommand{
+		Name:        "Support Agent",
+		Permissions: []auth.Permission{{Domain: "ticket", Action: "READ"}},
+	}, adminContext)
+	if err != nil {
+		t.Fatalf("Error creating parent role: %v", err)
+	}
+
+	lead, err := roleService.Create(role.CreateRoleCommand{
+		Name:        "Support Lead",
+		Permissions: []auth.Permission{{Domain: "ticket", Action: "ASSIGN"}},
+		ParentIDs:   []primitive.ObjectID{agent.ID},
+	}, adminContext)
+	if err != nil {
+		t.Fatalf("Error creating child role: %v", err)
+	}
+
+	effective, err := roleService.GetEffective(lead.ID.Hex(), adminContext)
+	if err != nil {
+		t.Fatalf("Error getting effective role: %v", err)
+	}
+	if len(effective.Permissions) != 1 {
+		t.Errorf("Expected 1 direct permission, got %d", len(effective.Permissions))
+	}
+	if len(effective.EffectivePermissions) != 2 {
+		t.Errorf("Expected 2 effective permissions, got %d", len(effective.EffectivePermissions))
+	}
+
+	// Changing the parent immediately affects the child
+	_, err = roleService.UpdateRole(agent.ID.Hex(), role.UpdateRoleCommand{
+		Name: agent.Name,
+		Permissions: []auth.Permission{
+			{Domain: "ticket", Action: "READ"},
+			{Domain: "ticket", Action: "COMMENT"},
+		},
+	}, adminContext)
+	if err != nil {
+		t.Fatalf("Error updating parent role: %v", err)
+	}
+
+	effective, err = roleService.GetEffective(lead.ID.Hex(), adminContext)
+	if err != nil {
+		t.Fatalf("Error getting effective role: %v", err)
+	}
+	if !auth.HasPermission(effective.EffectivePermissions, auth.Permission{Domain: "ticket", Action: "COMMENT"}) {
+		t.Errorf("Expected child role to inherit the new parent permission")
+	}
+}
+
+func testRoleRejectCycles(t *testing.T) {
+	roleService := service.NewRoleService(NewMockRoleRepository())
+	adminContext := auth.CreateAdminAuthContext()
+
+	parent, err := roleService.Create(role.CreateRoleCommand{Name: "Parent"}, adminContext)
+	if err != nil {
+		t.Fatalf("Error creating parent role: %v", err)
+	}
+	child, err := roleService.Create(role.CreateRoleCommand{Name: "Child", ParentIDs: []primitive.ObjectID{parent.ID}}, adminContext)
+	if err != nil {
+		t.Fatalf("Error creating child role: %v", err)
+	}
+
+	_, err = roleService.UpdateRole(parent.ID.Hex(), role.UpdateRoleCommand{
+		Name:      parent.Name,
+		ParentIDs: []primitive.ObjectID{child.ID},
+	}, adminContext)
+	if !errors.Is(err, constants.ErrorBadRequest) {
+		t.Errorf("Expected ErrorBadRequest for a cycle, got %v", err)
+	}
+
+	_, err = roleService.Create(role.CreateRoleCommand{Name: "Orphan", ParentIDs: []primitive.ObjectID{primitive.NewObjectID()}}, adminContext)
+	if !errors.Is(err, constants.ErrorNotFound) {
+		t.Errorf("Expected ErrorNotFound for a missing parent, got %v", err)
+	}
+}
+
+func testRoleClearParents(t *testing.T) {
+	roleService := service.NewRoleService(NewMockRoleRepository())
+	adminContext := auth.CreateAdminAuthContext()
+
+	parent, err := roleService.Create(role.CreateRoleCommand{Name: "Reviewer"}, adminContext)
+	if err != nil {
+		t.Fatalf("Error creating parent role: %v", err)
+	}
+	child, err := roleService.Create(role.CreateRoleCommand{Name: "Approver", ParentIDs: []primitive.ObjectID{parent.ID}}, adminContext)
+	if err != nil {
+		t.Fatalf("Error creating child role: %v", err)
+	}
+
+	// Omitted parents are kept
+	updated, err := roleService.UpdateRole(child.ID.Hex(), role.UpdateRoleCommand{Name: "Approver", Info: "Approves"}, adminContext)
+	if err != nil || len(updated.ParentIDs) != 1 {
+		t.Fatalf("Expected the parents to be kept, got %v (%v)", updated.ParentIDs, err)
+	}
+
+	var command role.UpdateRoleCommand
+	if err := json.Unmarshal([]byte(`{"name":"Approver","parentIds":[]}`), &command); err != nil {
+		t.Fatalf("Error decoding command: %v", err)
+	}
+	updated, err = roleService.UpdateRole(child.ID.Hex(), command, adminContext)
+	if err != nil {
+		t.Fatalf("Error updating role: %v", err)
+	}
+	if len(updated.ParentIDs) != 0 {
+		t.Errorf("Expected the parents to be removed, got %v", updated.ParentIDs)
+	}
+}
+
+func testRoleResolveStoredCycles(t *testing.T) {
+	repo := NewMockRoleRepository()
+	roleService := service.NewRoleService(repo)
+
+	// A cycle that already exists in the database must not break resolution
+	firstID, secondID := primitive.NewObjectID(), primitive.NewObjectID()
+	repo.roles[firstID] = role.Model{ID: firstID, Name: "First", ParentIDs: []primitive.ObjectID{secondID},
+		Permissions: []auth.Permission{{Domain: "a", Action: "READ"}}}
+	repo.roles[secondID] = role.Model{ID: secondID, Name: "Second", ParentIDs: []primitive.ObjectID{firstID},
+		Permissions: []auth.Permission{{Domain: "b", Action: "READ"}}}
+
+	resolved, err := roleService.ResolveRoles([]primitive.ObjectID{firstID}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error resolving roles: %v", err)
+	}
+	if len(resolved) != 2 {
+		t.Errorf("Expected 2 resolved roles, got %d", len(resolved))
+	}
+}
+
+func TestRoleSafeDelete(t *testing.T) {
+	repo := NewMockRoleRepository()
+	roleService := service.NewRoleService(repo)
+	adminContext := auth.CreateAdminAuthContext()
+
+	parent, err := roleService.Create(role.CreateRoleCommand{Name: "Member"}, adminContext)
+	if err != nil {
+		t.Fatalf("Error creating parent role: %v", err)
+	}
+	child, err := roleService.Create(role.CreateRoleCommand{Name: "Moderator", ParentIDs: []primitive.ObjectID{parent.ID}}, adminContext)
+	if err != nil {
+		t.Fatalf("Error creating child role: %v", err)
+	}
+	userID := primitive.NewObjectID()
+	repo.userRoles[userID] = []primitive.ObjectID{parent.ID}
+
+	err = roleService.Delete(parent.ID.Hex(), adminContext)
+	if !errors.Is(err, constants.ErrorConflict) {
+		t.Errorf("Expected ErrorConflict while the role is in use, got %v", err)
+	}
+
+	err = roleService.DeleteCascade(parent.ID.Hex(), adminContext)
+	if err != nil {
+		t.Fatalf("Error deleting role with cascade: %v", err)
+	}
+	if len(repo.userRoles[userID]) != 0 {
+		t.Errorf("Expected the role to be removed from the user")
+	}
+	if len(repo.roles[child.ID].ParentIDs) != 0 {
+		t.Errorf("Expected the role to be removed from the child role parents")
+	}
+	if _, ok := repo.roles[parent.ID]; ok {
+		t.Errorf("Expected the role to be deleted")
+	}
+}
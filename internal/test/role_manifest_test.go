@@ -0,0 +1,135 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testRoleManifest = `
+prune: true
+keep:
+  - ADMIN
+roles:
+  - name: Editor
+    info: Edits articles
+    default: true
+    parents:
+      - Reader
+    permissions:
+      - domain: article
+        action: UPDATE
+  - name: Reader
+    permissions:
+      - domain: article
+        action: READ
+`
+
+func TestRoleManifestReconcile(t *testing.T) {
+	repo := NewMockRoleRepository()
+	roleService := service.NewRoleService(repo)
+	adminContext := auth.CreateAdminAuthContext()
+
+	manifest, err := role.ParseManifest("roles.yaml", []byte(testRoleManifest))
+	if err != nil {
+		t.Fatalf("Error parsing manifest: %v", err)
+	}
+	if names := manifest.GetDefaultRoleNames(); len(names) != 1 || names[0] != "Editor" {
+		t.Errorf("Expected Editor to be the only default role, got %v", names)
+	}
+
+	// Unmanaged roles are pruned unless they are kept or still in use
+	adminID, legacyID, usedID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	repo.roles[adminID] = role.Model{ID: adminID, Name: "ADMIN"}
+	repo.roles[legacyID] = role.Model{ID: legacyID, Name: "Legacy"}
+	repo.roles[usedID] = role.Model{ID: usedID, Name: "Used"}
+	repo.userRoles[primitive.NewObjectID()] = []primitive.ObjectID{usedID}
+
+	result, err := roleService.Reconcile(manifest, adminContext)
+	if err != nil {
+		t.Fatalf("Error reconciling manifest: %v", err)
+	}
+	if len(result.Created) != 2 || len(result.Updated) != 0 {
+		t.Errorf("Expected 2 created and no updated roles, got %v", result)
+	}
+	if len(result.Deleted) != 1 || result.Deleted[0] != "Legacy" {
+		t.Errorf("Expected Legacy to be deleted, got %v", result.Deleted)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "Used" {
+		t.Errorf("Expected Used to be skipped, got %v", result.Skipped)
+	}
+
+	editor, err := roleService.GetByName("Editor", adminContext)
+	if err != nil {
+		t.Fatalf("Error getting reconciled role: %v", err)
+	}
+	effective, err := roleService.GetEffective(editor.ID.Hex(), adminContext)
+	if err != nil {
+		t.Fatalf("Error getting effective role: %v", err)
+	}
+	if !auth.HasPermission(effective.EffectivePermissions, auth.Permission{Domain: "article", Action: "READ"}) {
+		t.Errorf("Expected Editor to inherit the Reader permissions")
+	}
+
+	// Only the difference is reported when a permission is added
+	manifest.Roles[0].Permissions = append([]auth.Permission{{Domain: "article", Action: "PUBLISH"}}, manifest.Roles[0].Permissions...)
+	result, err = roleService.Reconcile(manifest, adminContext)
+	if err != nil {
+		t.Fatalf("Error reconciling manifest: %v", err)
+	}
+	if len(result.Created) != 0 || len(result.Updated) != 1 || len(result.Updated[0].AddedPermissions) != 1 {
+		t.Errorf("Expected only the added permission to be reported, got %v", result)
+	}
+
+	// Reconciling an applied manifest is a no-op
+	result, err = roleService.Reconcile(manifest, adminContext)
+	if err != nil {
+		t.Fatalf("Error reconciling manifest: %v", err)
+	}
+	if len(result.Created) != 0 || len(result.Updated) != 0 || len(result.Deleted) != 0 {
+		t.Errorf("Expected no changes, got %v", result)
+	}
+
+	// Emptied info, tags, permissions and parents are removed from the stored role
+	manifest.Roles[0].Info = ""
+	manifest.Roles[0].Tags = nil
+	manifest.Roles[0].Permissions = nil
+	manifest.Roles[0].Parents = []string{}
+	result, err = roleService.Reconcile(manifest, adminContext)
+	if err != nil {
+		t.Fatalf("Error reconciling manifest: %v", err)
+	}
+	if len(result.Updated) != 1 || !result.Updated[0].InfoChanged || !result.Updated[0].ParentsChanged {
+		t.Errorf("Expected the info and parents of Editor to be reported, got %v", result)
+	}
+	editor, err = roleService.GetByName("Editor", adminContext)
+	if err != nil {
+		t.Fatalf("Error getting reconciled role: %v", err)
+	}
+	if editor.Info != "" || len(editor.Permissions) != 0 || len(editor.ParentIDs) != 0 {
+		t.Errorf("Expected Editor to be emptied, got %+v", editor)
+	}
+}
+
+func TestRoleManifestParse(t *testing.T) {
+	manifest, err := role.ParseManifest("roles.yaml", []byte("roles:\n  - name: A\n    parents: []\n  - name: B\n"))
+	if err != nil {
+		t.Fatalf("Error parsing manifest: %v", err)
+	}
+	if manifest.Roles[0].Parents == nil || manifest.Roles[1].Parents != nil {
+		t.Errorf("Expected only the declared parents to be managed, got %+v", manifest.Roles)
+	}
+
+	_, err = role.ParseManifest("roles.json", []byte(`{"roles":[{"name":"A","parents":["Missing"]}]}`))
+	if err == nil {
+		t.Errorf("Expected an error for an undeclared parent")
+	}
+
+	_, err = role.ParseManifest("roles.toml", []byte(``))
+	if err == nil {
+		t.Errorf("Expected an error for an unsupported format")
+	}
+}
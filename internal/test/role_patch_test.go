@@ -0,0 +1,95 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"github.com/LydiaTrack/ground/pkg/mongodb/patch"
+)
+
+func TestRolePatch(t *testing.T) {
+	t.Run("MergePatch", testRoleMergePatch)
+	t.Run("JSONPatch", testRoleJSONPatch)
+	t.Run("RejectInvalidPatches", testRoleRejectInvalidPatches)
+}
+
+func createPatchedRole(t *testing.T, roleService *service.RoleService) role.Model {
+	roleModel, err := roleService.Create(role.CreateRoleCommand{
+		Name:        "Editor",
+		Info:        "Edits the content",
+		Tags:        []string{"content"},
+		Permissions: []auth.Permission{{Domain: "content", Action: "UPDATE"}},
+	}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error creating role: %v", err)
+	}
+	return roleModel
+}
+
+func testRoleMergePatch(t *testing.T) {
+	roleService := service.NewRoleService(NewMockRoleRepository())
+	editor := createPatchedRole(t, roleService)
+
+	patched, err := roleService.PatchRole(editor.ID.Hex(), role.PatchRoleCommand{
+		ContentType: patch.MergePatchContentType,
+		Patch:       []byte(`{"info":null,"tags":["content","review"]}`),
+	}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error patching role: %v", err)
+	}
+	if patched.Info != "" {
+		t.Errorf("Expected the info to be cleared, got %q", patched.Info)
+	}
+	if len(patched.Tags) != 2 || patched.Tags[1] != "review" {
+		t.Errorf("Expected the tags to be replaced, got %v", patched.Tags)
+	}
+	if patched.Name != "Editor" || len(patched.Permissions) != 1 {
+		t.Errorf("Expected the other fields to be kept, got %+v", patched)
+	}
+}
+
+func testRoleJSONPatch(t *testing.T) {
+	roleService := service.NewRoleService(NewMockRoleRepository())
+	editor := createPatchedRole(t, roleService)
+
+	patched, err := roleService.PatchRole(editor.ID.Hex(), role.PatchRoleCommand{
+		ContentType: patch.JSONPatchContentType,
+		Patch:       []byte(`[{"op":"add","path":"/permissions/-","value":{"domain":"content","action":"PUBLISH"}}]`),
+	}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error patching role: %v", err)
+	}
+	if len(patched.Permissions) != 2 || patched.Permissions[1].Action != "PUBLISH" {
+		t.Errorf("Expected the permission to be appended, got %v", patched.Permissions)
+	}
+}
+
+func testRoleRejectInvalidPatches(t *testing.T) {
+	roleService := service.NewRoleService(NewMockRoleRepository())
+	editor := createPatchedRole(t, roleService)
+	if _, err := roleService.Create(role.CreateRoleCommand{Name: "Viewer"}, auth.CreateAdminAuthContext()); err != nil {
+		t.Fatalf("Error creating role: %v", err)
+	}
+
+	for name, test := range map[string]struct {
+		patch    string
+		expected error
+	}{
+		"Forbidden field": {`{"createdDate":"2020-01-01T00:00:00Z"}`, constants.ErrorBadRequest},
+		"Empty name":      {`{"name":""}`, constants.ErrorBadRequest},
+		"Taken name":      {`{"name":"Viewer"}`, constants.ErrorConflict},
+		"Self parent":     {`{"parentIds":["` + editor.ID.Hex() + `"]}`, constants.ErrorBadRequest},
+	} {
+		_, err := roleService.PatchRole(editor.ID.Hex(), role.PatchRoleCommand{
+			ContentType: patch.MergePatchContentType,
+			Patch:       []byte(test.patch),
+		}, auth.CreateAdminAuthContext())
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUserIdentityChanges(t *testing.T) {
+	t.Run("RecordChanges", testRecordIdentityChanges)
+	t.Run("NoChanges", testNoIdentityChanges)
+	t.Run("ReservationPeriod", testUsernameReservationPeriod)
+}
+
+func testRecordIdentityChanges(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	adminID := primitive.NewObjectID()
+	before := user.Model{ID: primitive.NewObjectID(), Username: "jo", ContactInfo: user.ContactInfo{Email: "jo@example.com"}}
+	after := user.Model{ID: before.ID, Username: "joe", ContactInfo: user.ContactInfo{Email: "joe@example.com"}}
+
+	changes := user.IdentityChanges(before, after, &adminID, now, 24*time.Hour)
+	if len(changes) != 2 {
+		t.Fatalf("Expected the username and email changes, got %d", len(changes))
+	}
+
+	username, email := changes[0], changes[1]
+	if username.Field != user.IdentityFieldUsername || username.OldValue != "jo" || username.NewValue != "joe" ||
+		username.UserID != before.ID || *username.ChangedBy != adminID || !username.ChangedAt.Equal(now) {
+		t.Errorf("Unexpected username change %+v", username)
+	}
+	if username.ReservedUntil == nil || !username.ReservedUntil.Equal(now.Add(24*time.Hour)) {
+		t.Errorf("Expected the released username to be reserved for a day, got %v", username.ReservedUntil)
+	}
+	if email.Field != user.IdentityFieldEmail || email.OldValue != "jo@example.com" || email.NewValue != "joe@example.com" {
+		t.Errorf("Unexpected email change %+v", email)
+	}
+	if email.ReservedUntil != nil {
+		t.Errorf("Expected released emails not to be reserved, got %v", email.ReservedUntil)
+	}
+
+	unreserved := user.IdentityChanges(before, after, nil, now, 0)
+	if unreserved[0].ReservedUntil != nil {
+		t.Errorf("Expected no reservation with a zero reservation period")
+	}
+}
+
+func testNoIdentityChanges(t *testing.T) {
+	before := user.Model{ID: primitive.NewObjectID(), Username: "jo"}
+	after := before
+	after.PersonInfo = &user.PersonInfo{FirstName: "Jo", LastName: "Doe"}
+	if changes := user.IdentityChanges(before, after, nil, time.Now(), time.Hour); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+
+	// Setting an email for the first time does not release one
+	after.ContactInfo.Email = "jo@example.com"
+	if changes := user.IdentityChanges(before, after, nil, time.Now(), time.Hour); len(changes) != 0 {
+		t.Errorf("Expected no changes when an email is first set, got %+v", changes)
+	}
+}
+
+func testUsernameReservationPeriod(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"":        720 * time.Hour,
+		"invalid": 720 * time.Hour,
+		"-1":      720 * time.Hour,
+		"0":       0,
+		"48":      48 * time.Hour,
+	} {
+		t.Setenv("USERNAME_RESERVATION_PERIOD_IN_HOUR", value)
+		if period := service.GetUsernameReservationPeriod(); period != expected {
+			t.Errorf("Reservation period for %q = %v, want %v", value, period, expected)
+		}
+	}
+}
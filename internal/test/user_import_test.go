@@ -0,0 +1,166 @@
+package test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUserImportDecoding(t *testing.T) {
+	t.Run("DecodeCSVRows", testDecodeCSVRows)
+	t.Run("DecodeNDJSONRows", testDecodeNDJSONRows)
+	t.Run("DecodeUnsupportedFormat", testDecodeUnsupportedFormat)
+	t.Run("DecodeTooManyRows", testDecodeTooManyRows)
+	t.Run("DecodeOversizedUpload", testDecodeOversizedUpload)
+	t.Run("ExportStrings", testExportStrings)
+}
+
+func TestUserImportRoles(t *testing.T) {
+	log.InitLogging()
+	roleService := service.NewRoleService(NewMockRoleRepository())
+	editor, err := roleService.Create(role.CreateRoleCommand{Name: "Editor"}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error creating role: %v", err)
+	}
+	repo := NewMockUserRepository()
+	userService := service.NewUserService(repo, *roleService, nil, nil, nil)
+	importService := service.NewUserImportService(*userService, *roleService, service.InvitationService{})
+
+	input := "username,email,password,roles\njane,jane@example.com,Secret123!,Editor|Editor\n"
+	report, err := importService.Import(user.ImportFormatCSV, strings.NewReader(input), user.ImportOptions{}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error importing users: %v", err)
+	}
+	if report.Created != 1 || report.Rows[0].UserID == nil {
+		t.Fatalf("Expected the user to be created, got %+v", report.Rows)
+	}
+
+	// The roles are written with the user, a created user is never left without them
+	created := repo.users[*report.Rows[0].UserID]
+	if created.RoleIDs == nil || !reflect.DeepEqual(*created.RoleIDs, []primitive.ObjectID{editor.ID}) || len(created.RoleAssignments) != 1 {
+		t.Errorf("Expected the Editor role to be assigned once, got %v %+v", created.RoleIDs, created.RoleAssignments)
+	}
+}
+
+func testDecodeCSVRows(t *testing.T) {
+	input := "username,email,firstName,lastName,roles,properties\n" +
+		"jane,Jane@example.com,Jane,Doe,Editor|Viewer,\"{\"\"team\"\":\"\"blue\"\"}\"\n" +
+		"john,john@example.com,,,,not-json\n"
+
+	rows, err := user.DecodeImportRows(user.ImportFormatCSV, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+
+	first := rows[0]
+	if first.Error != nil || first.Number != 1 {
+		t.Fatalf("Expected the first row to decode, got %v", first.Error)
+	}
+	if first.Record.Username != "jane" || first.Record.LastName != "Doe" {
+		t.Errorf("Unexpected record %+v", first.Record)
+	}
+	if len(first.Record.Roles) != 2 || first.Record.Roles[1] != "Viewer" {
+		t.Errorf("Expected roles Editor and Viewer, got %v", first.Record.Roles)
+	}
+	if first.Record.Properties["team"] != "blue" {
+		t.Errorf("Expected the team property, got %v", first.Record.Properties)
+	}
+
+	if rows[1].Error == nil {
+		t.Error("Expected the invalid properties of the second row to fail")
+	}
+}
+
+func testDecodeNDJSONRows(t *testing.T) {
+	input := `{"username":"jane","email":"jane@example.com","roles":["Editor"]}
+
+{"username":`
+
+	rows, err := user.DecodeImportRows(user.ImportFormatNDJSON, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected blank lines to be skipped, got %d rows", len(rows))
+	}
+	if rows[0].Error != nil || rows[0].Record.Roles[0] != "Editor" {
+		t.Errorf("Unexpected first row %+v", rows[0])
+	}
+	if rows[1].Error == nil || rows[1].Number != 2 {
+		t.Errorf("Expected the malformed second row to fail, got %+v", rows[1])
+	}
+}
+
+func testDecodeUnsupportedFormat(t *testing.T) {
+	if _, err := user.DecodeImportRows("xml", strings.NewReader("")); err == nil {
+		t.Error("Expected an unsupported format to fail")
+	}
+}
+
+func testDecodeTooManyRows(t *testing.T) {
+	var csvInput, ndjsonInput strings.Builder
+	csvInput.WriteString("username\n")
+	for i := 0; i <= user.MaxImportRows; i++ {
+		csvInput.WriteString("user\n")
+		ndjsonInput.WriteString(`{"username":"user"}` + "\n")
+	}
+
+	if _, err := user.DecodeImportRows(user.ImportFormatCSV, strings.NewReader(csvInput.String())); !errors.Is(err, user.ErrTooManyImportRows) {
+		t.Errorf("Expected too many CSV rows to fail the import, got %v", err)
+	}
+	if _, err := user.DecodeImportRows(user.ImportFormatNDJSON, strings.NewReader(ndjsonInput.String())); !errors.Is(err, user.ErrTooManyImportRows) {
+		t.Errorf("Expected too many NDJSON rows to fail the import, got %v", err)
+	}
+}
+
+func testDecodeOversizedUpload(t *testing.T) {
+	input := "username,email\n" + strings.Repeat("jane,jane@example.com\n", 100)
+	body := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader(input)), 64)
+
+	_, err := user.DecodeImportRows(user.ImportFormatCSV, body)
+	var maxBytesError *http.MaxBytesError
+	if !errors.As(err, &maxBytesError) {
+		t.Errorf("Expected an oversized upload to fail the import, got %v", err)
+	}
+}
+
+func testExportStrings(t *testing.T) {
+	userModel, err := user.NewUser(
+		user.WithUsername("jane"),
+		user.WithContactInfo(user.ContactInfo{Email: "jane@example.com"}),
+		user.WithProperties(map[string]interface{}{"team": "blue"}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fields := []string{"username", "email", "firstName", "status", "properties"}
+	if err := user.ValidateExportFields(fields); err != nil {
+		t.Fatalf("Expected the fields to be exportable, got %v", err)
+	}
+	values := userModel.ExportStrings(fields)
+	expected := []string{"jane", "jane@example.com", "", "ACTIVE", `{"team":"blue"}`}
+	for i := range expected {
+		if values[i] != expected[i] {
+			t.Errorf("Expected %s to be %q, got %q", fields[i], expected[i], values[i])
+		}
+	}
+
+	if err := user.ValidateExportFields([]string{"password"}); err == nil {
+		t.Error("Expected the password not to be exportable")
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/group"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/registry"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUserProperties(t *testing.T) {
+	err := registry.RegisterUserPropertiesSchema([]byte(`{
+		"type": "object",
+		"required": ["plan"],
+		"properties": {
+			"plan": {"enum": ["free", "pro"]},
+			"nickname": {"type": "string", "maxLength": 10},
+			"credit": {"type": "number"}
+		}
+	}`), map[string]registry.PropertyVisibility{
+		"nickname": registry.PropertyVisibilityPublic,
+		"credit":   registry.PropertyVisibilityAdmin,
+	})
+	if err != nil {
+		t.Fatalf("Error registering the properties schema: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = registry.RegisterUserPropertiesSchema(nil, nil)
+	})
+
+	t.Run("RejectUnknownVisibility", testRejectUnknownVisibility)
+	t.Run("ValidateSelfProperties", testValidateSelfProperties)
+	t.Run("FilterProperties", testFilterProperties)
+	t.Run("MemberListingProfiles", testMemberListingProfiles)
+}
+
+func testRejectUnknownVisibility(t *testing.T) {
+	err := registry.RegisterUserPropertiesSchema(nil, map[string]registry.PropertyVisibility{"plan": "PRIVATE"})
+	if err == nil {
+		t.Fatalf("Expected the unknown visibility to be refused")
+	}
+}
+
+func testValidateSelfProperties(t *testing.T) {
+	userService := service.UserService{}
+
+	if err := userService.ValidateSelfProperties(map[string]interface{}{"plan": "free", "nickname": "jo"}); err != nil {
+		t.Fatalf("Expected the properties to be valid, got %v", err)
+	}
+
+	err := userService.ValidateSelfProperties(nil)
+	if !errors.Is(err, constants.ErrorBadRequest) || !strings.Contains(err.Error(), "properties: plan: is required") {
+		t.Fatalf("Expected the missing required key to be refused, got %v", err)
+	}
+
+	err = userService.ValidateSelfProperties(map[string]interface{}{"plan": "gold", "nickname": "a very long nickname"})
+	if !errors.Is(err, constants.ErrorBadRequest) ||
+		!strings.Contains(err.Error(), "nickname: must be at most 10 characters long; plan: must be one of [free pro]") {
+		t.Fatalf("Expected readable validation errors, got %v", err)
+	}
+
+	err = userService.ValidateSelfProperties(map[string]interface{}{"plan": "free", "credit": 100})
+	if !errors.Is(err, constants.ErrorBadRequest) || !strings.Contains(err.Error(), "credit can only be changed by admins") {
+		t.Fatalf("Expected the admin-only key to be refused, got %v", err)
+	}
+}
+
+func testFilterProperties(t *testing.T) {
+	userService := service.UserService{}
+	userModel := user.Model{Username: "jo", Properties: map[string]interface{}{
+		"plan":     "pro",
+		"nickname": "jo",
+		"credit":   42,
+	}}
+
+	self := userService.SelfView(userModel)
+	if !reflect.DeepEqual(self.Properties, map[string]interface{}{"plan": "pro", "nickname": "jo"}) {
+		t.Errorf("Unexpected self properties %v", self.Properties)
+	}
+	public := userService.PublicView(userModel)
+	if !reflect.DeepEqual(public.Properties, map[string]interface{}{"nickname": "jo"}) {
+		t.Errorf("Unexpected public properties %v", public.Properties)
+	}
+	if len(userModel.Properties) != 3 {
+		t.Errorf("Expected the properties of the user to be left untouched, got %v", userModel.Properties)
+	}
+}
+
+func testMemberListingProfiles(t *testing.T) {
+	member := user.Model{
+		ID:          primitive.NewObjectID(),
+		Username:    "jo",
+		PersonInfo:  &user.PersonInfo{FirstName: "Jo", LastName: "Doe"},
+		ContactInfo: user.ContactInfo{Email: "jo@example.com"},
+		Properties:  map[string]interface{}{"plan": "pro", "nickname": "jo", "credit": 42},
+	}
+	userService := service.NewUserService(NewMockUserRepository(member), service.RoleService{}, nil, nil, nil)
+
+	groupRepository := NewMockGroupRepository()
+	team := group.Model{ID: primitive.NewObjectID(), Name: "Team"}
+	groupRepository.groups[team.ID] = team
+	groupRepository.members = append(groupRepository.members,
+		group.Member{ID: primitive.NewObjectID(), GroupID: team.ID, UserID: member.ID},
+		// The membership of a deleted user is listed without a profile
+		group.Member{ID: primitive.NewObjectID(), GroupID: team.ID, UserID: primitive.NewObjectID()})
+	groupService := service.NewGroupService(groupRepository, *userService, service.RoleService{}, service.AuditService{})
+
+	result, err := groupService.GetMembers(team.ID.Hex(), 1, 10, auth.PermissionContext{
+		Permissions: []auth.Permission{permissions.GroupMemberReadPermission},
+	})
+	if err != nil {
+		t.Fatalf("Error listing members: %v", err)
+	}
+	if len(result.Data) != 2 || result.Data[1].User != nil {
+		t.Fatalf("Expected two members, only the first with a profile, got %+v", result.Data)
+	}
+	expected := user.PublicProfile{ID: member.ID, Username: "jo", FirstName: "Jo", LastName: "Doe",
+		Properties: map[string]interface{}{"nickname": "jo"}}
+	if profile := result.Data[0].User; profile == nil || !reflect.DeepEqual(*profile, expected) {
+		t.Errorf("Expected the public profile %+v, got %+v", expected, profile)
+	}
+}
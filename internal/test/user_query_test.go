@@ -0,0 +1,190 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/jsonschema"
+	"github.com/LydiaTrack/ground/pkg/mongodb/query"
+	"github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestUserQueryParsing(t *testing.T) {
+	t.Run("ParseFilters", testParseUserFilters)
+	t.Run("RejectUndeclaredParameters", testRejectUndeclaredParameters)
+	t.Run("ParseSort", testParseUserSort)
+	t.Run("TypedProperties", testParseTypedProperties)
+	t.Run("OAuthProvider", testParseOAuthProvider)
+}
+
+func testParseUserFilters(t *testing.T) {
+	params := url.Values{
+		"page":                     {"1"},
+		"search":                   {"jane"},
+		"oauthProvider[in]":        {"google,github"},
+		"properties.plan":          {"pro"},
+		"createdDate[gte]":         {"2024-01-01"},
+		"emailDomain":              {"example.com"},
+		"status":                   {"banned"},
+		"role[nin]":                {"65a000000000000000000001"},
+		"properties.trial[exists]": {"false"},
+	}
+
+	userQuery, err := user.QuerySchema.Parse(params)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	conditions, ok := userQuery.Filter["$and"].(bson.A)
+	if !ok || len(conditions) != 7 {
+		t.Fatalf("Expected 7 conditions, got %v", userQuery.Filter)
+	}
+	if userQuery.Sort != nil {
+		t.Errorf("Expected no sort, got %v", userQuery.Sort)
+	}
+
+	single, err := user.QuerySchema.ParseFilter(url.Values{"properties.plan": {"pro"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if single["properties.plan"] != "pro" {
+		t.Errorf("Expected a properties.plan condition, got %v", single)
+	}
+}
+
+func testRejectUndeclaredParameters(t *testing.T) {
+	invalid := []url.Values{
+		{"password": {"secret"}},
+		{"username[$where]": {"1"}},
+		{"username[regex]": {".*"}},
+		{"role": {"not-an-id"}},
+		{"status": {"UNKNOWN"}},
+		{"emailDomain": {".*"}},
+		{"properties.$where": {"1"}},
+		{"properties.a.b": {"1"}},
+		{"createdDate": {"2024-01-01"}},
+		{"createdDate[gte]": {"yesterday"}},
+	}
+	for _, params := range invalid {
+		if _, err := user.QuerySchema.ParseFilter(params); !errors.Is(err, query.ErrInvalidQuery) {
+			t.Errorf("Expected %v to be rejected, got %v", params, err)
+		}
+	}
+}
+
+func testParseUserSort(t *testing.T) {
+	sort, err := user.QuerySchema.ParseSort("-createdDate,username")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := bson.D{{Key: "createdDate", Value: -1}, {Key: "username", Value: 1}, {Key: "_id", Value: 1}}
+	if len(sort) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, sort)
+	}
+	for i := range expected {
+		if sort[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], sort[i])
+		}
+	}
+
+	for _, invalid := range []string{"role", "password", "-$natural"} {
+		if _, err := user.QuerySchema.ParseSort(invalid); !errors.Is(err, query.ErrInvalidQuery) {
+			t.Errorf("Expected sort %q to be rejected, got %v", invalid, err)
+		}
+	}
+}
+
+func testParseTypedProperties(t *testing.T) {
+	properties, err := jsonschema.Compile([]byte(`{"type":"object","properties":{
+		"seats":{"type":"integer"},"trial":{"type":["boolean","null"]},"plan":{"type":"string"},"extra":{}}}`))
+	if err != nil {
+		t.Fatalf("Error compiling schema: %v", err)
+	}
+	schema := user.QuerySchemaWithProperties(properties)
+
+	filter, err := schema.ParseFilter(url.Values{"properties.seats": {"10"}})
+	if err != nil || filter["properties.seats"] != float64(10) {
+		t.Errorf("Expected a number condition, got %v (%v)", filter, err)
+	}
+	filter, err = schema.ParseFilter(url.Values{"properties.trial[ne]": {"true"}})
+	if err != nil || !reflect.DeepEqual(filter["properties.trial"], bson.M{"$ne": true}) {
+		t.Errorf("Expected a bool condition, got %v (%v)", filter, err)
+	}
+	for _, key := range []string{"plan", "extra", "undeclared"} {
+		filter, err = schema.ParseFilter(url.Values{"properties." + key: {"10"}})
+		if err != nil || filter["properties."+key] != "10" {
+			t.Errorf("Expected a string condition for %s, got %v (%v)", key, filter, err)
+		}
+	}
+	if _, err = schema.ParseFilter(url.Values{"properties.seats": {"many"}}); !errors.Is(err, query.ErrInvalidQuery) {
+		t.Errorf("Expected a non-numeric value to be rejected, got %v", err)
+	}
+
+	// Without a schema every property is a string
+	filter, err = user.QuerySchemaWithProperties(nil).ParseFilter(url.Values{"properties.seats": {"10"}})
+	if err != nil || filter["properties.seats"] != "10" {
+		t.Errorf("Expected a string condition without a schema, got %v (%v)", filter, err)
+	}
+}
+
+func testParseOAuthProvider(t *testing.T) {
+	// Accounts linked before the provider was stored are OAuth accounts as well
+	filter, err := user.QuerySchema.ParseFilter(url.Values{"oauthProvider[exists]": {"true"}})
+	if err != nil || !reflect.DeepEqual(filter, bson.M{"OAuthInfo": bson.M{"$exists": true}}) {
+		t.Errorf("Expected the OAuth account to be checked, got %v (%v)", filter, err)
+	}
+	filter, err = user.QuerySchema.ParseFilter(url.Values{"oauthProvider": {"google"}})
+	if err != nil || filter["OAuthInfo.provider"] != "google" {
+		t.Errorf("Expected the provider to be matched, got %v (%v)", filter, err)
+	}
+}
+
+func TestUserListingCursorParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := NewMockUserRepository()
+	userService := service.NewUserService(repo, service.RoleService{}, nil, nil, nil)
+	userHandler := handlers.NewUserHandler(*userService, auth.Service{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		auth.SetAuthContext(c, auth.PermissionContext{Permissions: []auth.Permission{permissions.UserReadPermission}})
+	})
+	r.GET("/users", userHandler.GetUsers)
+
+	cases := []struct {
+		path     string
+		expected int
+	}{
+		{"/users?cursor=", http.StatusOK},
+		{"/users?cursor=&count=true&limit=5&status=banned", http.StatusOK},
+		{"/users?cursor=&count=maybe", http.StatusBadRequest},
+		{"/users?cursor=&limit=100000", http.StatusOK},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.expected {
+			t.Errorf("GET %s: expected status %d, got %d: %s", tc.path, tc.expected, w.Code, w.Body.String())
+		}
+	}
+
+	if len(repo.cursorRequests) != 3 {
+		t.Fatalf("Expected 3 keyset paginated queries, got %d", len(repo.cursorRequests))
+	}
+	if request := repo.cursorRequests[1]; !request.Count || request.Limit != 5 {
+		t.Errorf("Expected the count and limit parameters to be applied, got %+v", request)
+	}
+	if request := repo.cursorRequests[2]; request.Limit != repository.MaxLimit {
+		t.Errorf("Expected the limit to be reduced to %d, got %d", repository.MaxLimit, request.Limit)
+	}
+}
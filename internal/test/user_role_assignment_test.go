@@ -0,0 +1,48 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUserRoleAssignments(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	permanentRoleID := primitive.NewObjectID()
+	expiredRoleID := primitive.NewObjectID()
+	temporaryRoleID := primitive.NewObjectID()
+	legacyRoleID := primitive.NewObjectID()
+
+	userModel := user.Model{
+		RoleIDs: &[]primitive.ObjectID{permanentRoleID, expiredRoleID, temporaryRoleID, legacyRoleID},
+		RoleAssignments: []user.RoleAssignment{
+			{RoleID: permanentRoleID, GrantedAt: past},
+			{RoleID: expiredRoleID, GrantedAt: past, ExpiresAt: &past, Reason: "on-call"},
+			{RoleID: temporaryRoleID, GrantedAt: past, ExpiresAt: &future, Reason: "on-call"},
+		},
+	}
+
+	activeRoleIDs := userModel.ActiveRoleIDs(now)
+	if len(activeRoleIDs) != 3 {
+		t.Fatalf("Expected 3 active roles, got %d", len(activeRoleIDs))
+	}
+	for _, roleID := range activeRoleIDs {
+		if roleID == expiredRoleID {
+			t.Errorf("Expired role should not be active")
+		}
+	}
+
+	if len((user.Model{}).ActiveRoleIDs(now)) != 0 {
+		t.Errorf("Expected no active roles for a user without roles")
+	}
+
+	command := user.AddRoleToUserCommand{RoleID: temporaryRoleID, ExpiresAt: &past}
+	if command.Validate() == nil {
+		t.Errorf("Expected an error for an expiry date in the past")
+	}
+}
@@ -0,0 +1,141 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/settings"
+	"github.com/LydiaTrack/ground/pkg/registry"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mockSettingsRepository keeps the settings in memory like the settings collection does
+type mockSettingsRepository struct {
+	settings map[primitive.ObjectID]settings.Model
+}
+
+func (r *mockSettingsRepository) GetByUserID(userID primitive.ObjectID) (settings.Model, error) {
+	settingsModel, ok := r.settings[userID]
+	if !ok {
+		return settings.Model{}, mongo.ErrNoDocuments
+	}
+	return settingsModel, nil
+}
+
+func (r *mockSettingsRepository) UpdateValues(userID primitive.ObjectID, namespace string, set map[string]interface{}, unset []string) error {
+	settingsModel, ok := r.settings[userID]
+	if !ok {
+		settingsModel = settings.Model{UserID: userID, Namespaces: map[string]map[string]interface{}{}}
+	}
+	if settingsModel.Namespaces[namespace] == nil {
+		settingsModel.Namespaces[namespace] = map[string]interface{}{}
+	}
+	for key, value := range set {
+		settingsModel.Namespaces[namespace][key] = value
+	}
+	for _, key := range unset {
+		delete(settingsModel.Namespaces[namespace], key)
+	}
+	settingsModel.Version++
+	r.settings[userID] = settingsModel
+	return nil
+}
+
+func (r *mockSettingsRepository) DeleteByUserID(userID primitive.ObjectID) error {
+	delete(r.settings, userID)
+	return nil
+}
+
+func TestUserSettings(t *testing.T) {
+	if err := registry.RegisterUserSettings("ui", map[string]interface{}{
+		"theme":    "light",
+		"pageSize": 20,
+		"compact":  false,
+		"pinned":   []string{},
+	}); err != nil {
+		t.Fatalf("Failed to register the settings: %v", err)
+	}
+
+	t.Run("Registration", testSettingsRegistration)
+	t.Run("Validate", testSettingsValidate)
+	t.Run("UpdateSelf", testUpdateSelfSettings)
+}
+
+func testSettingsRegistration(t *testing.T) {
+	if err := registry.RegisterUserSettings("ui.theme", nil); err == nil {
+		t.Errorf("Expected namespaces with dots to be refused")
+	}
+	if err := registry.RegisterUserSettings("other", map[string]interface{}{"$set": 1}); err == nil {
+		t.Errorf("Expected keys with operators to be refused")
+	}
+	if _, ok := registry.GetUserSettingsNamespace("missing"); ok {
+		t.Errorf("Expected an unregistered namespace not to be found")
+	}
+}
+
+func testSettingsValidate(t *testing.T) {
+	namespace, _ := registry.GetUserSettingsNamespace("ui")
+	if err := namespace.Validate(map[string]interface{}{"theme": "dark", "pageSize": 50.0, "pinned": []interface{}{"a"}, "compact": nil}); err != nil {
+		t.Errorf("Expected the values to be valid, got %v", err)
+	}
+	err := namespace.Validate(map[string]interface{}{"theme": true, "unknown": 1})
+	if err == nil || err.Error() != "settings ui: theme must be a string, unknown is not a setting" {
+		t.Errorf("Unexpected validation error %v", err)
+	}
+
+	values := namespace.Resolve(map[string]interface{}{"theme": "dark", "compact": "yes", "removed": 1})
+	if values["theme"] != "dark" || values["compact"] != false || values["pageSize"] != 20.0 || len(values) != 4 {
+		t.Errorf("Expected the stored values over the defaults, got %v", values)
+	}
+}
+
+func testUpdateSelfSettings(t *testing.T) {
+	settingsService := service.NewSettingsService(&mockSettingsRepository{settings: map[primitive.ObjectID]settings.Model{}})
+	userID := primitive.NewObjectID()
+	authContext := auth.PermissionContext{
+		Permissions: []auth.Permission{permissions.UserSelfGetPermission, permissions.UserSelfUpdatePermission},
+		UserID:      &userID,
+	}
+
+	initial, err := settingsService.GetSelf("ui", authContext)
+	if err != nil {
+		t.Fatalf("Failed to get the settings: %v", err)
+	}
+	if initial.Values["theme"] != "light" {
+		t.Errorf("Expected the defaults before any update, got %v", initial.Values)
+	}
+
+	updated, err := settingsService.UpdateSelf("ui", settings.UpdateSettingsCommand{Values: map[string]interface{}{"theme": "dark", "compact": true}}, authContext)
+	if err != nil {
+		t.Fatalf("Failed to update the settings: %v", err)
+	}
+	if updated.Values["theme"] != "dark" || updated.Values["compact"] != true || updated.Values["pageSize"] != 20.0 {
+		t.Errorf("Unexpected settings after the update %v", updated.Values)
+	}
+
+	reset, err := settingsService.UpdateSelf("ui", settings.UpdateSettingsCommand{Values: map[string]interface{}{"theme": nil}}, authContext)
+	if err != nil {
+		t.Fatalf("Failed to reset the setting: %v", err)
+	}
+	if reset.Values["theme"] != "light" || reset.Values["compact"] != true {
+		t.Errorf("Expected only the theme to be reset, got %v", reset.Values)
+	}
+
+	_, err = settingsService.UpdateSelf("ui", settings.UpdateSettingsCommand{Values: map[string]interface{}{"theme": 1}}, authContext)
+	if !errors.Is(err, constants.ErrorBadRequest) {
+		t.Errorf("Expected a bad request for a value of the wrong type, got %v", err)
+	}
+	_, err = settingsService.GetSelf("missing", authContext)
+	if !errors.Is(err, constants.ErrorNotFound) {
+		t.Errorf("Expected an unregistered namespace not to be found, got %v", err)
+	}
+	_, err = settingsService.GetSelf("ui", auth.PermissionContext{UserID: &userID})
+	if !errors.Is(err, constants.ErrorPermissionDenied) {
+		t.Errorf("Expected the permission to be checked, got %v", err)
+	}
+}
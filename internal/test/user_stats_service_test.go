@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/LydiaTrack/ground/internal/permissions"
 	"github.com/LydiaTrack/ground/internal/service"
 	"github.com/LydiaTrack/ground/pkg/auth"
 	"github.com/LydiaTrack/ground/pkg/constants"
@@ -120,6 +121,19 @@ func (m *MockUserStatsRepository) UpdateField(statsID primitive.ObjectID, fieldN
 	return nil
 }
 
+func (m *MockUserStatsRepository) DeleteStatsByUserID(userID primitive.ObjectID) error {
+	if m.shouldReturnError {
+		return errors.New(m.errorMessage)
+	}
+
+	for id, stats := range m.stats {
+		if stats.GetObjectID("userId") == userID {
+			delete(m.stats, id)
+		}
+	}
+	return nil
+}
+
 func (m *MockUserStatsRepository) UpdateFields(statsID primitive.ObjectID, fields map[string]interface{}) error {
 	if m.shouldReturnError {
 		return errors.New(m.errorMessage)
@@ -522,4 +536,16 @@ func testPermissionChecks(t *testing.T) {
 	if err != constants.ErrorPermissionDenied {
 		t.Errorf("User should not be able to modify other user's stats, expected ErrorPermissionDenied, got: %v", err)
 	}
+
+	// The updates only require the update permission
+	updaterContext := auth.PermissionContext{
+		Permissions: []auth.Permission{permissions.UserStatsUpdatePermission},
+		UserID:      &otherUserID,
+	}
+	if err = statsService.IncrementField(userID, "tasksCreated", 1, updaterContext); err != nil {
+		t.Errorf("Updater should be able to modify the stats without the read permission, got error: %v", err)
+	}
+	if err = statsService.RecordLogin(userID, updaterContext); err != nil {
+		t.Errorf("Updater should be able to record a login without the read permission, got error: %v", err)
+	}
 }
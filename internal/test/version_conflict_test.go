@@ -0,0 +1,158 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LydiaTrack/ground/internal/handlers"
+	"github.com/LydiaTrack/ground/internal/permissions"
+	"github.com/LydiaTrack/ground/internal/repository"
+	"github.com/LydiaTrack/ground/internal/service"
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/role"
+	baseRepository "github.com/LydiaTrack/ground/pkg/mongodb/repository"
+	"github.com/LydiaTrack/ground/pkg/test_support"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRoleUpdateIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := NewMockRoleRepository()
+	roleService := service.NewRoleService(repo)
+	roleHandler := handlers.NewRoleHandler(*roleService, auth.Service{}, service.UserService{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		auth.SetAuthContext(c, auth.PermissionContext{Permissions: []auth.Permission{
+			permissions.RoleReadPermission, permissions.RoleUpdatePermission,
+		}})
+	})
+	r.PUT("/roles/:id", roleHandler.UpdateRole)
+
+	roleModel, err := roleService.Create(role.CreateRoleCommand{Name: "Editor"}, auth.CreateAdminAuthContext())
+	if err != nil {
+		t.Fatalf("Error creating role: %v", err)
+	}
+
+	update := func(id, ifMatch string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPut, "/roles/"+id, strings.NewReader(`{"name":"Editor"}`))
+		request.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			request.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, request)
+		return w
+	}
+
+	// Each successful update increments the version from 1
+	cases := []struct {
+		name     string
+		ifMatch  string
+		expected int
+		etag     string
+	}{
+		{"matching version", `"1"`, http.StatusOK, `"2"`},
+		{"stale version", `"1"`, http.StatusConflict, ""},
+		{"matching weak ETag", `W/"2"`, http.StatusOK, `"3"`},
+		{"stale weak ETag", `W/"2"`, http.StatusConflict, ""},
+		{"any version", "*", http.StatusOK, `"4"`},
+		{"missing header", "", http.StatusOK, `"5"`},
+		{"malformed header", `"latest"`, http.StatusBadRequest, ""},
+		{"stale version zero", `"0"`, http.StatusConflict, ""},
+		{"negative version", `"-1"`, http.StatusBadRequest, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := update(roleModel.ID.Hex(), tc.ifMatch)
+			if w.Code != tc.expected {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expected, w.Code, w.Body.String())
+			}
+			if tc.etag != "" && w.Header().Get("ETag") != tc.etag {
+				t.Errorf("Expected ETag %s, got %s", tc.etag, w.Header().Get("ETag"))
+			}
+			if w.Code == http.StatusConflict {
+				var body map[string]interface{}
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body["code"] != "VERSION_CONFLICT" {
+					t.Errorf("Expected the VERSION_CONFLICT code, got %s", w.Body.String())
+				}
+			}
+		})
+	}
+
+	t.Run("role stored before it was versioned", func(t *testing.T) {
+		// The legacy role is renamed to Editor
+		delete(repo.roles, roleModel.ID)
+		legacy := role.Model{ID: primitive.NewObjectID(), Name: "Legacy"}
+		repo.roles[legacy.ID] = legacy
+		w := update(legacy.ID.Hex(), `"0"`)
+		if w.Code != http.StatusOK || w.Header().Get("ETag") != `"1"` {
+			t.Errorf("Expected version zero to match, got %d with ETag %s", w.Code, w.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("missing role", func(t *testing.T) {
+		if w := update(primitive.NewObjectID().Hex(), `"1"`); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
+
+// TestVersionGuard checks the version guard of BaseRepository against MongoDB
+func TestVersionGuard(t *testing.T) {
+	test_support.TestWithMongo()
+	repo := repository.GetRoleMongoRepository()
+
+	roleModel, err := role.NewRole(role.WithName("version-guard-" + primitive.NewObjectID().Hex()))
+	if err != nil {
+		t.Fatalf("Error creating role: %v", err)
+	}
+	if _, err = repo.Create(context.Background(), *roleModel); err != nil {
+		t.Fatalf("Error storing role: %v", err)
+	}
+	defer repo.Delete(context.Background(), roleModel.ID)
+
+	set := bson.M{"info": "guarded"}
+	t.Run("matching version updates and increments it", func(t *testing.T) {
+		result, err := repo.UpdateFields(baseRepository.WithExpectedVersion(context.Background(), 1), roleModel.ID, set, nil)
+		if err != nil || result.MatchedCount != 1 {
+			t.Fatalf("Expected the update to apply, got %v", err)
+		}
+		updated, err := repo.GetByID(context.Background(), roleModel.ID)
+		if err != nil || updated.Version != 2 {
+			t.Errorf("Expected version 2, got %d (%v)", updated.Version, err)
+		}
+	})
+
+	t.Run("stale version is a conflict", func(t *testing.T) {
+		_, err := repo.UpdateFields(baseRepository.WithExpectedVersion(context.Background(), 1), roleModel.ID, set, nil)
+		if !errors.Is(err, constants.ErrorVersionConflict) {
+			t.Errorf("Expected ErrorVersionConflict, got %v", err)
+		}
+	})
+
+	t.Run("missing document is not a conflict", func(t *testing.T) {
+		result, err := repo.UpdateFields(baseRepository.WithExpectedVersion(context.Background(), 1), primitive.NewObjectID(), set, nil)
+		if err != nil || result.MatchedCount != 0 {
+			t.Errorf("Expected nothing to match, got %v", err)
+		}
+	})
+
+	t.Run("unguarded update increments the version", func(t *testing.T) {
+		if _, err := repo.UpdateFields(context.Background(), roleModel.ID, set, nil); err != nil {
+			t.Fatalf("Expected the update to apply, got %v", err)
+		}
+		updated, err := repo.GetByID(context.Background(), roleModel.ID)
+		if err != nil || updated.Version != 3 {
+			t.Errorf("Expected version 3, got %d (%v)", updated.Version, err)
+		}
+	})
+}
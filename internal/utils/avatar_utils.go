@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var inlineAvatarPattern = regexp.MustCompile(`^data:image/(png|jpg|jpeg|gif);base64,`)
+
+// ValidateUserAvatar checks if the avatar is a secure URL or the URL of an uploaded avatar
+func ValidateUserAvatar(avatar string) error {
+	if avatarID, uploaded := UploadedAvatarID(avatar); uploaded {
+		if !primitive.IsValidObjectID(avatarID) {
+			return errors.New("invalid avatar, uploaded avatars are referred to by their ID")
+		}
+		return nil
+	}
+	if strings.HasPrefix(avatar, "https://") {
+		return nil
+	}
+	return errors.New("invalid avatar format, avatars are uploaded or given as https URLs")
+}
+
+// UploadedAvatarID returns the avatar ID of the URL of an uploaded avatar, false if the avatar is not served by us
+func UploadedAvatarID(avatar string) (string, bool) {
+	return strings.CutPrefix(avatar, AvatarURL(""))
+}
+
+// AvatarURL returns the URL an uploaded avatar is served from, relative to AVATAR_BASE_URL
+func AvatarURL(avatarID string) string {
+	return strings.TrimSuffix(os.Getenv("AVATAR_BASE_URL"), "/") + "/avatars/" + avatarID
+}
+
+// IsInlineAvatar checks if the avatar is a base64 data URL, as the avatars were stored before they were uploaded
+func IsInlineAvatar(avatar string) bool {
+	return inlineAvatarPattern.MatchString(avatar)
+}
+
+// DecodeInlineAvatar decodes the image of a base64 data URL avatar
+func DecodeInlineAvatar(avatar string) ([]byte, error) {
+	prefix := inlineAvatarPattern.FindString(avatar)
+	if prefix == "" {
+		return nil, errors.New("avatar is not a base64 data URL")
+	}
+	return base64.StdEncoding.DecodeString(avatar[len(prefix):])
+}
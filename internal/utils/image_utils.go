@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+var (
+	// ErrUnsupportedImage is returned for content that is not a PNG, JPEG or GIF image
+	ErrUnsupportedImage = errors.New("unsupported image type, expected PNG, JPEG or GIF")
+	// ErrInvalidImage is returned for images that cannot be decoded or are too large
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+var imageTypes = map[string]bool{"image/png": true, "image/jpeg": true, "image/gif": true}
+
+// DecodeImage decodes a PNG, JPEG or GIF image. The type is sniffed from the content, not taken from the name or
+// the declared content type, and the dimensions are checked before decoding so large images are not decompressed.
+func DecodeImage(data []byte, maxDimension int) (image.Image, error) {
+	if contentType := http.DetectContentType(data); !imageTypes[contentType] {
+		return nil, fmt.Errorf("%w: got %s", ErrUnsupportedImage, contentType)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > maxDimension || config.Height > maxDimension {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %dx%d", ErrInvalidImage, config.Width, config.Height, maxDimension, maxDimension)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	return img, nil
+}
+
+// SquareThumbnail crops the centered square of the image and scales it to size x size. Every pixel of the thumbnail
+// averages the pixels of the image it covers, so downscaling does not alias.
+func SquareThumbnail(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2))
+
+	source := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(source, source.Bounds(), img, crop.Min, draw.Src)
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := span(y, size, side)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(x, size, side)
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pixel := source.RGBAAt(sx, sy)
+					r, g, b, a = r+uint64(pixel.R), g+uint64(pixel.G), b+uint64(pixel.B), a+uint64(pixel.A)
+					count++
+				}
+			}
+			thumbnail.SetRGBA(x, y, color.RGBA{R: uint8(r / count), G: uint8(g / count), B: uint8(b / count), A: uint8(a / count)})
+		}
+	}
+	return thumbnail
+}
+
+// span returns the range of the source pixels covered by a pixel of the scaled image, at least one pixel wide
+func span(i, size, side int) (int, int) {
+	start := i * side / size
+	end := (i + 1) * side / size
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
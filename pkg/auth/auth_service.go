@@ -16,6 +16,7 @@ import (
 	"github.com/LydiaTrack/ground/pkg/domain/user"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type UserService interface {
@@ -26,6 +27,10 @@ type UserService interface {
 	Get(id string, authContext PermissionContext) (user.Model, error)
 	GetByEmail(email string, authContext PermissionContext) (user.Model, error)
 	Update(id string, command user.UpdateUserCommand, authContext PermissionContext) (user.Model, error)
+	CancelScheduledDeletion(userID primitive.ObjectID) error
+	SetOAuthProvider(userID primitive.ObjectID, provider string) error
+	ValidateSelfProperties(properties map[string]interface{}) error
+	SelfView(userModel user.Model) user.Model
 }
 
 type SessionService interface {
@@ -110,6 +115,15 @@ func (s Service) Login(request Request) (Response, error) {
 		return Response{}, err
 	}
 
+	// Only checked after the password, so the state of an account is not disclosed to anyone else
+	if err = userModel.CheckAccountStatus(time.Now()); err != nil {
+		return Response{}, err
+	}
+
+	if err = s.cancelScheduledDeletion(userModel); err != nil {
+		return Response{}, err
+	}
+
 	// Generate token
 	tokenPair, err := jwt.GenerateTokenPair(userModel.ID)
 	if err != nil {
@@ -129,8 +143,13 @@ func (s Service) Login(request Request) (Response, error) {
 	}, nil
 }
 
-// SignUp is a function that handles the signup process, creates a new user from the given request
+// SignUp is a function that handles the signup process, creates a new user from the given request.
+// The signup mode decides if the user can sign up without an invitation.
 func (s Service) SignUp(cmd user.CreateUserCommand) (user.Model, error) {
+	if err := CheckSignupAllowed(cmd.ContactInfo.Email); err != nil {
+		return user.Model{}, err
+	}
+
 	// Check if user exists
 	exists, err := s.userService.ExistsByUsername(cmd.Username, CreateAdminAuthContext())
 	if err != nil {
@@ -140,13 +159,18 @@ func (s Service) SignUp(cmd user.CreateUserCommand) (user.Model, error) {
 		return user.Model{}, constants.ErrorConflict
 	}
 
+	// The user cannot set the admin-only properties
+	if err := s.userService.ValidateSelfProperties(cmd.Properties); err != nil {
+		return user.Model{}, err
+	}
+
 	// Create user
 	userResponse, err := s.userService.Create(cmd, PermissionContext{Permissions: []Permission{AdminPermission}, UserID: nil})
 	if err != nil {
 		return user.Model{}, constants.ErrorInternalServerError
 	}
 
-	return userResponse, nil
+	return s.userService.SelfView(userResponse), nil
 }
 
 // SetSession is a function that sets the session with the given user id and token pair
@@ -178,9 +202,10 @@ func (s Service) SetSession(userID string, tokenPair jwt.TokenPair) error {
 
 	// Save refresh token with expire time
 	createSessionCmd := session.CreateSessionCommand{
-		UserID:       userID,
-		ExpireTime:   time.Now().Add(time.Hour * time.Duration(refreshTokenLifespan)).Unix(),
-		RefreshToken: tokenPair.RefreshToken,
+		UserID:         userID,
+		ExpireTime:     time.Now().Add(time.Hour * time.Duration(refreshTokenLifespan)).Unix(),
+		RefreshToken:   tokenPair.RefreshToken,
+		OrganizationID: tokenPair.OrganizationID,
 	}
 	_, err = s.sessionService.CreateSession(createSessionCmd)
 	if err != nil {
@@ -207,6 +232,56 @@ func (s Service) GetCurrentUser(c *gin.Context) (user.Model, error) {
 	return userModel, nil
 }
 
+// GetCurrentSelfUser returns the current user as the user sees their own account, without the admin-only properties
+func (s Service) GetCurrentSelfUser(c *gin.Context) (user.Model, error) {
+	userModel, err := s.GetCurrentUser(c)
+	if err != nil {
+		return user.Model{}, err
+	}
+	return s.userService.SelfView(userModel), nil
+}
+
+// SwitchOrganization issues a token pair with the given organization as the active one.
+// An empty organization ID issues a token pair without an active organization.
+func (s Service) SwitchOrganization(c *gin.Context, organizationID string) (jwt.TokenPair, error) {
+	currentUser, err := s.GetCurrentUser(c)
+	if err != nil {
+		return jwt.TokenPair{}, constants.ErrorUnauthorized
+	}
+	if err = currentUser.CheckAccountStatus(time.Now()); err != nil {
+		return jwt.TokenPair{}, err
+	}
+
+	var activeOrganizationID *primitive.ObjectID
+	if organizationID != "" {
+		tenantID, err := primitive.ObjectIDFromHex(organizationID)
+		if err != nil {
+			return jwt.TokenPair{}, constants.ErrorBadRequest
+		}
+		if tenantPermissionProvider == nil {
+			return jwt.TokenPair{}, constants.ErrorPermissionDenied
+		}
+		// Only members can switch to the organization
+		if _, err = tenantPermissionProvider.GetTenantPermissionList(currentUser, tenantID); err != nil {
+			return jwt.TokenPair{}, err
+		}
+		activeOrganizationID = &tenantID
+	}
+
+	tokenPair, err := jwt.GenerateOrganizationTokenPair(currentUser.ID, activeOrganizationID)
+	if err != nil {
+		log.Log("Error generating token pair", err)
+		return jwt.TokenPair{}, constants.ErrorInternalServerError
+	}
+
+	err = s.SetSession(currentUser.ID.Hex(), tokenPair)
+	if err != nil {
+		return jwt.TokenPair{}, constants.ErrorInternalServerError
+	}
+
+	return tokenPair, nil
+}
+
 // RefreshTokenPair is a function that refreshes the token pair
 func (s Service) RefreshTokenPair(c *gin.Context) (jwt.TokenPair, error) {
 	// Get the refresh token from the request body
@@ -241,8 +316,23 @@ func (s Service) RefreshTokenPair(c *gin.Context) (jwt.TokenPair, error) {
 		return jwt.TokenPair{}, constants.ErrorUnauthorized
 	}
 
-	// Now that we know the token is valid and not expired, generate new tokens
-	tokenPair, err := jwt.GenerateTokenPair(sessionInfo.UserID)
+	// The account status is checked again, a suspended or deleted user cannot keep refreshing its tokens
+	if s.userService == nil {
+		log.Log("No user service to check the account of user %s", sessionInfo.UserID.Hex())
+		return jwt.TokenPair{}, constants.ErrorInternalServerError
+	}
+	userModel, err := s.userService.Get(sessionInfo.UserID.Hex(), CreateAdminAuthContext())
+	if err != nil {
+		log.Log("Error getting user %s for the token refresh", sessionInfo.UserID.Hex())
+		return jwt.TokenPair{}, constants.ErrorUnauthorized
+	}
+	if err = userModel.CheckAccountStatus(time.Now()); err != nil {
+		_ = s.sessionService.DeleteSessionByUser(sessionInfo.UserID.Hex())
+		return jwt.TokenPair{}, err
+	}
+
+	// Now that we know the token is valid and not expired, generate new tokens, keeping the active organization
+	tokenPair, err := jwt.GenerateOrganizationTokenPair(sessionInfo.UserID, sessionInfo.OrganizationID)
 	if err != nil {
 		log.Log("Error generating new token pair", err)
 		return jwt.TokenPair{}, constants.ErrorInternalServerError
@@ -326,6 +416,7 @@ func (s Service) OAuthLogin(provider string, token string) (Response, error) {
 
 	oauthInfo := user.OAuthInfo{
 		ProviderID:     userInfo.ProviderID,
+		Provider:       provider,
 		Email:          userInfo.Email,
 		AccessToken:    token,
 		RefreshToken:   "", // We no longer track refresh tokens separately
@@ -334,6 +425,11 @@ func (s Service) OAuthLogin(provider string, token string) (Response, error) {
 	}
 	var userModel user.Model
 	if !exists {
+		// New users are subject to the signup mode
+		if err := CheckSignupAllowed(userInfo.Email); err != nil {
+			return Response{}, err
+		}
+
 		// Create new user
 		createCmd := user.CreateUserCommand{
 			Username: userInfo.Email, // Use email as username for OAuth users
@@ -358,20 +454,36 @@ func (s Service) OAuthLogin(provider string, token string) (Response, error) {
 		if err != nil {
 			return Response{}, err
 		}
+		if err = userModel.CheckAccountStatus(time.Now()); err != nil {
+			return Response{}, err
+		}
+		if err = s.cancelScheduledDeletion(userModel); err != nil {
+			return Response{}, err
+		}
+		// Accounts linked before the provider was stored get it on their next login, so they match the provider filter
+		if userModel.OAuthInfo != nil && userModel.OAuthInfo.Provider == "" && userModel.OAuthInfo.ProviderID == userInfo.ProviderID {
+			if err = s.userService.SetOAuthProvider(userModel.ID, provider); err != nil {
+				log.LogError("Failed to store the OAuth provider of user %s: %v", userModel.ID.Hex(), err)
+			}
+		}
 		// Update OAuth provider info
 		// TODO: If user tries to login with a different OAuth provider, we should handle that case
 		userModel.OAuthInfo = &oauthInfo
 	}
 
-	// Update user with OAuth info
-	anyPropChanged := userModel.Avatar != userInfo.Picture ||
+	// Update user with OAuth info, an uploaded avatar is kept over the picture of the provider
+	picture := userInfo.Picture
+	if userModel.AvatarID != "" {
+		picture = userModel.Avatar
+	}
+	anyPropChanged := userModel.Avatar != picture ||
 		userModel.PersonInfo.FirstName != userInfo.FirstName ||
 		userModel.PersonInfo.LastName != userInfo.LastName ||
 		userModel.ContactInfo.Email != userInfo.Email
 	if anyPropChanged {
 		updateCmd := user.UpdateUserCommand{
 			Username: userModel.Username,
-			Avatar:   userInfo.Picture,
+			Avatar:   picture,
 			PersonInfo: &user.PersonInfo{
 				FirstName: userInfo.FirstName,
 				LastName:  userInfo.LastName,
@@ -410,3 +522,31 @@ func (s Service) IsOAuthProviderEnabled(provider string) bool {
 	_, exists := s.oauthProviders[provider]
 	return exists
 }
+
+// ReauthenticateOAuth re-authenticates a user signed up with an OAuth provider. The token is validated by the
+// provider the user logged in with, as in OAuthLogin, and must belong to the same provider account.
+func (s Service) ReauthenticateOAuth(userModel user.Model, token string) error {
+	if userModel.OAuthInfo == nil || token == "" {
+		return constants.ErrorUnauthorized
+	}
+
+	oauthProvider, ok := s.oauthProviders[userModel.OAuthInfo.Provider]
+	if !ok {
+		return constants.ErrorUnauthorized
+	}
+
+	userInfo, err := oauthProvider.GetUserInfo(token)
+	if err != nil || userInfo.ProviderID != userModel.OAuthInfo.ProviderID {
+		return constants.ErrorUnauthorized
+	}
+	return nil
+}
+
+// cancelScheduledDeletion cancels the self-service deletion of a user that logs in during the cooling-off period
+func (s Service) cancelScheduledDeletion(userModel user.Model) error {
+	if userModel.DeletionScheduledAt == nil {
+		return nil
+	}
+	log.Log("Cancelling the scheduled deletion of user %s", userModel.ID.Hex())
+	return s.userService.CancelScheduledDeletion(userModel.ID)
+}
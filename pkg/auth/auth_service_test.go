@@ -10,7 +10,9 @@ import (
 
 	"github.com/LydiaTrack/ground/pkg/constants"
 	"github.com/LydiaTrack/ground/pkg/domain/session"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
 	"github.com/LydiaTrack/ground/pkg/jwt"
+	"github.com/LydiaTrack/ground/pkg/log"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -72,7 +74,22 @@ func (m *mockSessionService) CleanupExpiredSessions() error {
 	return nil
 }
 
+// Mock user service for testing, only the lookup of the refreshed user is implemented
+type mockUserService struct {
+	UserService
+	users map[string]user.Model
+}
+
+func (m *mockUserService) Get(id string, _ PermissionContext) (user.Model, error) {
+	userModel, exists := m.users[id]
+	if !exists {
+		return user.Model{}, constants.ErrorNotFound
+	}
+	return userModel, nil
+}
+
 func TestRefreshTokenPairSessionExpiration(t *testing.T) {
+	log.InitLogging()
 	// Set up environment variables
 	os.Setenv(jwt.JwtSecretKey, "test_secret")
 	os.Setenv(jwt.JwtExpirationKey, "5")
@@ -89,15 +106,15 @@ func TestRefreshTokenPairSessionExpiration(t *testing.T) {
 			sessions: make(map[string]session.InfoModel),
 		}
 
-		// Create auth service (we'll mock other dependencies as nil since we're only testing session logic)
-		authService := Service{
-			sessionService: mockSession,
-			// Other fields can be nil for this test
-		}
-
 		// Create a session that expires in the future
 		futureExpiry := time.Now().Add(1 * time.Hour).Unix()
 		userID := primitive.NewObjectID()
+
+		// Create auth service with a user service knowing the session's user
+		authService := Service{
+			sessionService: mockSession,
+			userService:    &mockUserService{users: map[string]user.Model{userID.Hex(): {ID: userID}}},
+		}
 		refreshToken := "test_refresh_token"
 
 		sessionModel := session.InfoModel{
@@ -123,11 +140,40 @@ func TestRefreshTokenPairSessionExpiration(t *testing.T) {
 		// Test refresh token - should succeed since session is not expired
 		_, err := authService.RefreshTokenPair(c)
 
-		// Since we don't have a full user service setup, this will fail at token generation
-		// But it should NOT fail with unauthorized due to expiration
-		// The error should be ErrorInternalServerError (from missing user service), not ErrorUnauthorized
-		if err == constants.ErrorUnauthorized {
-			t.Error("Expected session expiration check to pass, but got unauthorized error")
+		if err != nil {
+			t.Errorf("Expected the token pair to be refreshed, got %v", err)
+		}
+	})
+
+	t.Run("Fail when the session's user cannot be found", func(t *testing.T) {
+		mockSession := &mockSessionService{
+			sessions: make(map[string]session.InfoModel),
+		}
+
+		authService := Service{
+			sessionService: mockSession,
+			userService:    &mockUserService{users: map[string]user.Model{}},
+		}
+
+		refreshToken := "orphaned_refresh_token"
+		mockSession.sessions[refreshToken] = session.InfoModel{
+			ID:           primitive.NewObjectID(),
+			UserID:       primitive.NewObjectID(),
+			RefreshToken: refreshToken,
+			ExpireTime:   time.Now().Add(1 * time.Hour).Unix(),
+		}
+
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		jsonBody, _ := json.Marshal(RefreshTokenRequest{RefreshToken: refreshToken})
+		req := httptest.NewRequest("POST", "/auth/refreshToken", strings.NewReader(string(jsonBody)))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		if _, err := authService.RefreshTokenPair(c); err != constants.ErrorUnauthorized {
+			t.Errorf("Expected ErrorUnauthorized for a missing user, got %v", err)
 		}
 	})
 
@@ -4,25 +4,61 @@ import (
 	"fmt"
 	"github.com/LydiaTrack/ground/pkg/constants"
 	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"github.com/LydiaTrack/ground/pkg/jwt"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"time"
 )
 
-type userService interface {
+// PermissionListProvider resolves the permissions of a user
+type PermissionListProvider interface {
 	GetPermissionList(userModel user.Model) ([]Permission, error)
 }
 
-func CreateAuthContext(c *gin.Context, authService Service, userService userService) (PermissionContext, error) {
+// TenantPermissionProvider resolves the permissions of the roles a user holds in an organization.
+// It must fail with constants.ErrorPermissionDenied if the user is not a member of the organization.
+type TenantPermissionProvider interface {
+	GetTenantPermissionList(userModel user.Model, tenantID primitive.ObjectID) ([]Permission, error)
+}
+
+// tenantPermissionProvider resolves the permissions of the requests with an active organization
+var tenantPermissionProvider TenantPermissionProvider
+
+// SetTenantPermissionProvider sets the provider used to resolve the permissions in the active organization
+func SetTenantPermissionProvider(provider TenantPermissionProvider) {
+	tenantPermissionProvider = provider
+}
+
+// authContextKey is the gin context key the auth context of the request is stored under
+const authContextKey = "authContext"
+
+// CreateAuthContext creates the auth context of the current user.
+// If a middleware already created it for this request, the stored one is returned.
+func CreateAuthContext(c *gin.Context, authService Service, userService PermissionListProvider) (PermissionContext, error) {
+	if authContext, ok := GetAuthContext(c); ok {
+		return authContext, nil
+	}
+
 	now := time.Now()
 	currentUser, err := authService.GetCurrentUser(c)
 	if err != nil {
 		return PermissionContext{}, constants.ErrorNotFound
 	}
+	if err = currentUser.CheckAccountStatus(now); err != nil {
+		return PermissionContext{}, err
+	}
 	elapsedCurrentUser := time.Since(now)
+	organizationID, err := jwt.ExtractOrganizationIDFromContext(c)
+	if err != nil {
+		return PermissionContext{}, constants.ErrorUnauthorized
+	}
 	currentUserPermissions, err := userService.GetPermissionList(currentUser)
 	if err != nil {
 		return PermissionContext{}, constants.ErrorNotFound
 	}
+	if organizationID != "" {
+		return createTenantAuthContext(currentUser, currentUserPermissions, organizationID)
+	}
 	elapsedPermissions := time.Since(now) - elapsedCurrentUser
 	elapsed := time.Since(now)
 	if elapsed > 100*time.Millisecond {
@@ -35,6 +71,42 @@ func CreateAuthContext(c *gin.Context, authService Service, userService userServ
 	}, nil
 }
 
+// createTenantAuthContext creates the auth context of a user in the active organization of the token. The permissions
+// held in the organization are kept apart from the global ones, so they do not apply outside the organization.
+func createTenantAuthContext(currentUser user.Model, globalPermissions []Permission, organizationID string) (PermissionContext, error) {
+	tenantID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil || tenantPermissionProvider == nil {
+		return PermissionContext{}, constants.ErrorUnauthorized
+	}
+
+	tenantPermissions, err := tenantPermissionProvider.GetTenantPermissionList(currentUser, tenantID)
+	if err != nil {
+		return PermissionContext{}, err
+	}
+
+	return PermissionContext{
+		Permissions:       globalPermissions,
+		UserID:            &currentUser.ID,
+		TenantID:          &tenantID,
+		TenantPermissions: tenantPermissions,
+	}, nil
+}
+
+// SetAuthContext stores the auth context in the request context, so it is created once per request
+func SetAuthContext(c *gin.Context, authContext PermissionContext) {
+	c.Set(authContextKey, authContext)
+}
+
+// GetAuthContext retrieves the auth context stored in the request context
+func GetAuthContext(c *gin.Context) (PermissionContext, bool) {
+	value, exists := c.Get(authContextKey)
+	if !exists {
+		return PermissionContext{}, false
+	}
+	authContext, ok := value.(PermissionContext)
+	return authContext, ok
+}
+
 // CreateAdminAuthContext creates an auth context for an admin user
 func CreateAdminAuthContext() PermissionContext {
 	return PermissionContext{
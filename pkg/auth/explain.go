@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/LydiaTrack/ground/pkg/log"
+)
+
+// MatchRule is the rule that decided an authorization request
+type MatchRule string
+
+const (
+	// MatchRuleExact means a permission with the same domain and action allowed the request
+	MatchRuleExact MatchRule = "exact"
+	// MatchRuleWildcard means a permission with a wildcard domain or action allowed the request
+	MatchRuleWildcard MatchRule = "wildcard"
+	// MatchRuleAllowPolicy means an allow policy allowed the request
+	MatchRuleAllowPolicy MatchRule = "allowPolicy"
+	// MatchRuleDeny means a deny policy denied the request
+	MatchRuleDeny MatchRule = "deny"
+	// MatchRuleNone means nothing allowed the request
+	MatchRuleNone MatchRule = "none"
+)
+
+// Decision describes why an authorization request was allowed or denied
+type Decision struct {
+	Allowed           bool        `json:"allowed"`
+	Rule              MatchRule   `json:"rule"`
+	MatchedPermission *Permission `json:"matchedPermission,omitempty"`
+	Policy            string      `json:"policy,omitempty"`
+}
+
+// ExplainPermission decides if permissions contain the required permission, with the same rules as HasPermission
+func ExplainPermission(permissions []Permission, required Permission) Decision {
+	matchers := []struct {
+		rule    MatchRule
+		matches func(permission Permission) bool
+	}{
+		{MatchRuleWildcard, func(p Permission) bool { return p.Domain == "*" && p.Action == "*" }},
+		{MatchRuleWildcard, func(p Permission) bool { return p.Domain == "*" && p.Action == required.Action }},
+		{MatchRuleWildcard, func(p Permission) bool { return p.Domain == required.Domain && p.Action == "*" }},
+		{MatchRuleExact, func(p Permission) bool { return p.Domain == required.Domain && p.Action == required.Action }},
+	}
+
+	for _, matcher := range matchers {
+		for _, permission := range permissions {
+			if matcher.matches(permission) {
+				matched := permission
+				return Decision{Allowed: true, Rule: matcher.rule, MatchedPermission: &matched}
+			}
+		}
+	}
+	return Decision{Allowed: false, Rule: MatchRuleNone}
+}
+
+// ExplainAuthorize decides if subject can perform action on resource, in the same order as Authorize
+func ExplainAuthorize(ctx context.Context, subject PermissionContext, action Permission, resource Resource) Decision {
+	for _, policy := range policyRegistry.policies {
+		if policy.Effect == EffectDeny && policy.matches(ctx, subject, action, resource) {
+			return Decision{Allowed: false, Rule: MatchRuleDeny, Policy: policy.Name}
+		}
+	}
+
+	if decision := ExplainPermission(subject.Permissions, action); decision.Allowed {
+		return decision
+	}
+
+	for _, policy := range policyRegistry.policies {
+		if policy.Effect == EffectAllow && policy.matches(ctx, subject, action, resource) {
+			return Decision{Allowed: true, Rule: MatchRuleAllowPolicy, Policy: policy.Name}
+		}
+	}
+
+	return Decision{Allowed: false, Rule: MatchRuleNone}
+}
+
+// LogDenial logs a denied request with its decision when LOG_PERMISSION_DENIALS is enabled
+func LogDenial(subject PermissionContext, action Permission, decision Decision) {
+	if decision.Allowed || os.Getenv("LOG_PERMISSION_DENIALS") != "true" {
+		return
+	}
+
+	userID := "anonymous"
+	if subject.UserID != nil {
+		userID = subject.UserID.Hex()
+	}
+	reason := fmt.Sprintf("rule: %s", decision.Rule)
+	if decision.Policy != "" {
+		reason += fmt.Sprintf(", policy: %s", decision.Policy)
+	}
+	log.Log("Permission denied for user %s on %s/%s (%s)", userID, action.Domain, action.Action, reason)
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExplainPermission(t *testing.T) {
+	required := Permission{Domain: "note", Action: "READ"}
+
+	cases := []struct {
+		name        string
+		permissions []Permission
+		allowed     bool
+		rule        MatchRule
+	}{
+		{"Exact", []Permission{required}, true, MatchRuleExact},
+		{"Domain wildcard", []Permission{{Domain: "note", Action: "*"}, required}, true, MatchRuleWildcard},
+		{"Admin", []Permission{AdminPermission}, true, MatchRuleWildcard},
+		{"Missing", []Permission{{Domain: "note", Action: "UPDATE"}}, false, MatchRuleNone},
+	}
+	for _, tc := range cases {
+		decision := ExplainPermission(tc.permissions, required)
+		if decision.Allowed != tc.allowed || decision.Rule != tc.rule {
+			t.Errorf("%s: expected allowed=%v rule=%s, got allowed=%v rule=%s", tc.name, tc.allowed, tc.rule, decision.Allowed, decision.Rule)
+		}
+	}
+
+	// The wildcard is reported even when an exact permission also exists, like HasPermission checks it first
+	decision := ExplainPermission([]Permission{required, {Domain: "note", Action: "*"}}, required)
+	if decision.MatchedPermission == nil || decision.MatchedPermission.Action != "*" {
+		t.Errorf("Expected the wildcard permission to be matched, got %v", decision.MatchedPermission)
+	}
+}
+
+func TestExplainAuthorizeDeny(t *testing.T) {
+	withPolicies(t, Policy{
+		Name:      "archived-notes-are-read-only",
+		Effect:    EffectDeny,
+		Actions:   []Permission{testNoteUpdatePermission},
+		Condition: StatusIn("archived"),
+	})
+
+	subject := PermissionContext{Permissions: []Permission{AdminPermission}}
+	decision := ExplainAuthorize(context.Background(), subject, testNoteUpdatePermission, Resource{Status: "archived"})
+	if decision.Allowed || decision.Rule != MatchRuleDeny || decision.Policy != "archived-notes-are-read-only" {
+		t.Errorf("Expected the deny policy to decide, got %+v", decision)
+	}
+}
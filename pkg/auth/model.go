@@ -3,11 +3,25 @@ package auth
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
 type Permission struct {
-	Domain string `json:"domain"`
-	Action string `json:"action"`
+	Domain string `json:"domain" yaml:"domain"`
+	Action string `json:"action" yaml:"action"`
 }
 
 type PermissionContext struct {
 	Permissions []Permission        `json:"permissions"`
 	UserID      *primitive.ObjectID `json:"userID"`
+	// TenantID is the active organization of the request, nil when no organization is active
+	TenantID *primitive.ObjectID `json:"tenantID,omitempty"`
+	// TenantPermissions are the permissions of the roles held in the active organization, they only apply to the
+	// operations scoped to it
+	TenantPermissions []Permission `json:"tenantPermissions,omitempty"`
+}
+
+// TenantScopedPermissions returns the permissions of the operations scoped to the active organization, the global
+// permissions together with the ones held in the organization
+func (c PermissionContext) TenantScopedPermissions() []Permission {
+	if len(c.TenantPermissions) == 0 {
+		return c.Permissions
+	}
+	return append(append([]Permission{}, c.Permissions...), c.TenantPermissions...)
 }
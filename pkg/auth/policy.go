@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Effect is the outcome a policy produces when it matches
+type Effect string
+
+const (
+	EffectAllow Effect = "allow"
+	EffectDeny  Effect = "deny"
+)
+
+// Resource describes the object an action is performed on.
+// Services fill in the fields they know about, policies decide based on them.
+type Resource struct {
+	Domain     string                 `json:"domain"`
+	ID         string                 `json:"id,omitempty"`
+	OwnerID    *primitive.ObjectID    `json:"ownerId,omitempty"`
+	TenantID   string                 `json:"tenantId,omitempty"`
+	Status     string                 `json:"status,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Condition is evaluated against the subject, the requested action and the resource.
+type Condition func(ctx context.Context, subject PermissionContext, action Permission, resource Resource) bool
+
+// Policy is a rule that allows or denies a set of actions when its condition holds.
+// Actions are matched with the same wildcard rules as HasPermission.
+type Policy struct {
+	Name      string
+	Effect    Effect
+	Actions   []Permission
+	Condition Condition
+}
+
+// matches checks if the policy applies to the given request
+func (p Policy) matches(ctx context.Context, subject PermissionContext, action Permission, resource Resource) bool {
+	if !HasPermission(p.Actions, action) {
+		return false
+	}
+	if p.Condition == nil {
+		return true
+	}
+	return p.Condition(ctx, subject, action, resource)
+}
+
+// policyRegistry is the singleton instance of PolicyRegistry.
+var policyRegistry = &PolicyRegistry{
+	policies: []Policy{},
+}
+
+// PolicyRegistry holds the policies evaluated by Authorize.
+type PolicyRegistry struct {
+	policies []Policy
+}
+
+// RegisterPolicy registers a new Policy in the global PolicyRegistry.
+func RegisterPolicy(policy Policy) {
+	policyRegistry.policies = append(policyRegistry.policies, policy)
+}
+
+// GetPolicies returns all the registered policies.
+func GetPolicies() []Policy {
+	return policyRegistry.policies
+}
+
+// Authorize decides if subject can perform action on resource.
+// It evaluates in the following order:
+// 1. Any matching deny policy denies the request
+// 2. A global permission of the subject allows the request
+// 3. Any matching allow policy allows the request
+// Otherwise the request is denied.
+func Authorize(ctx context.Context, subject PermissionContext, action Permission, resource Resource) error {
+	decision := ExplainAuthorize(ctx, subject, action, resource)
+	if !decision.Allowed {
+		LogDenial(subject, action, decision)
+		return constants.ErrorPermissionDenied
+	}
+	return nil
+}
+
+// IsOwner holds when the subject is the owner of the resource
+func IsOwner(_ context.Context, subject PermissionContext, _ Permission, resource Resource) bool {
+	return subject.UserID != nil && resource.OwnerID != nil && *subject.UserID == *resource.OwnerID
+}
+
+// InSameTenant holds when the resource belongs to the active organization of the subject
+func InSameTenant(_ context.Context, subject PermissionContext, _ Permission, resource Resource) bool {
+	return subject.TenantID != nil && resource.TenantID == subject.TenantID.Hex()
+}
+
+// StatusIn holds when the resource status is one of the given statuses
+func StatusIn(statuses ...string) Condition {
+	return func(_ context.Context, _ PermissionContext, _ Permission, resource Resource) bool {
+		for _, status := range statuses {
+			if resource.Status == status {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AttributeEquals holds when the resource attribute with the given key deeply equals value, so maps and slices can
+// be compared as well
+func AttributeEquals(key string, value interface{}) Condition {
+	return func(_ context.Context, _ PermissionContext, _ Permission, resource Resource) bool {
+		attribute, ok := resource.Attributes[key]
+		return ok && reflect.DeepEqual(attribute, value)
+	}
+}
+
+// AllOf holds when every given condition holds
+func AllOf(conditions ...Condition) Condition {
+	return func(ctx context.Context, subject PermissionContext, action Permission, resource Resource) bool {
+		for _, condition := range conditions {
+			if !condition(ctx, subject, action, resource) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyOf holds when at least one of the given conditions holds
+func AnyOf(conditions ...Condition) Condition {
+	return func(ctx context.Context, subject PermissionContext, action Permission, resource Resource) bool {
+		for _, condition := range conditions {
+			if condition(ctx, subject, action, resource) {
+				return true
+			}
+		}
+		return false
+	}
+}
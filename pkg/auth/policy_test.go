@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	testNoteReadPermission   = Permission{Domain: "note", Action: "READ"}
+	testNoteUpdatePermission = Permission{Domain: "note", Action: "UPDATE"}
+)
+
+func withPolicies(t *testing.T, policies ...Policy) {
+	previous := policyRegistry.policies
+	policyRegistry.policies = policies
+	t.Cleanup(func() {
+		policyRegistry.policies = previous
+	})
+}
+
+func TestAuthorize(t *testing.T) {
+	ownerID := primitive.NewObjectID()
+	otherID := primitive.NewObjectID()
+	resource := Resource{Domain: "note", OwnerID: &ownerID, Status: "archived"}
+
+	withPolicies(t,
+		Policy{
+			Name:      "note-owner",
+			Effect:    EffectAllow,
+			Actions:   []Permission{testNoteReadPermission, testNoteUpdatePermission},
+			Condition: IsOwner,
+		},
+		Policy{
+			Name:      "archived-notes-are-read-only",
+			Effect:    EffectDeny,
+			Actions:   []Permission{testNoteUpdatePermission},
+			Condition: StatusIn("archived"),
+		},
+	)
+
+	t.Run("Owner is allowed by policy", func(t *testing.T) {
+		subject := PermissionContext{UserID: &ownerID}
+		if err := Authorize(context.Background(), subject, testNoteReadPermission, resource); err != nil {
+			t.Errorf("Expected owner to be allowed, got: %v", err)
+		}
+	})
+
+	t.Run("Other user is denied", func(t *testing.T) {
+		subject := PermissionContext{UserID: &otherID}
+		if err := Authorize(context.Background(), subject, testNoteReadPermission, resource); err != constants.ErrorPermissionDenied {
+			t.Errorf("Expected ErrorPermissionDenied, got: %v", err)
+		}
+	})
+
+	t.Run("Global permission is allowed", func(t *testing.T) {
+		subject := PermissionContext{UserID: &otherID, Permissions: []Permission{{Domain: "note", Action: "*"}}}
+		if err := Authorize(context.Background(), subject, testNoteReadPermission, resource); err != nil {
+			t.Errorf("Expected global permission to be allowed, got: %v", err)
+		}
+	})
+
+	t.Run("Deny policy wins over admin permission", func(t *testing.T) {
+		subject := PermissionContext{Permissions: []Permission{AdminPermission}}
+		if err := Authorize(context.Background(), subject, testNoteUpdatePermission, resource); err != constants.ErrorPermissionDenied {
+			t.Errorf("Expected ErrorPermissionDenied, got: %v", err)
+		}
+	})
+}
+
+func TestConditions(t *testing.T) {
+	resource := Resource{Status: "active", Attributes: map[string]interface{}{"visibility": "public"}}
+	ctx := context.Background()
+
+	if !AttributeEquals("visibility", "public")(ctx, PermissionContext{}, Permission{}, resource) {
+		t.Errorf("Expected AttributeEquals to hold")
+	}
+	if AttributeEquals("missing", "public")(ctx, PermissionContext{}, Permission{}, resource) {
+		t.Errorf("Expected AttributeEquals to fail for a missing attribute")
+	}
+	labeled := Resource{Attributes: map[string]interface{}{"labels": []string{"a", "b"}, "owner": map[string]interface{}{"team": "blue"}}}
+	if !AttributeEquals("labels", []string{"a", "b"})(ctx, PermissionContext{}, Permission{}, labeled) ||
+		!AttributeEquals("owner", map[string]interface{}{"team": "blue"})(ctx, PermissionContext{}, Permission{}, labeled) {
+		t.Errorf("Expected AttributeEquals to compare slices and maps")
+	}
+	if AttributeEquals("labels", "a")(ctx, PermissionContext{}, Permission{}, labeled) {
+		t.Errorf("Expected AttributeEquals to fail for a value of another type")
+	}
+	if !AllOf(StatusIn("active"), AttributeEquals("visibility", "public"))(ctx, PermissionContext{}, Permission{}, resource) {
+		t.Errorf("Expected AllOf to hold")
+	}
+	if !AnyOf(StatusIn("archived"), StatusIn("active"))(ctx, PermissionContext{}, Permission{}, resource) {
+		t.Errorf("Expected AnyOf to hold")
+	}
+	if IsOwner(ctx, PermissionContext{}, Permission{}, resource) {
+		t.Errorf("Expected IsOwner to fail without an owner")
+	}
+}
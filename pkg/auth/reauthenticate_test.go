@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/LydiaTrack/ground/pkg/auth/types"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+)
+
+// mockOAuthProvider resolves the tokens to the provider accounts they were issued for
+type mockOAuthProvider struct {
+	accounts map[string]string
+}
+
+func (m mockOAuthProvider) GetUserInfo(token string) (*types.OAuthUserInfo, error) {
+	providerID, ok := m.accounts[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return &types.OAuthUserInfo{ProviderID: providerID}, nil
+}
+
+func TestReauthenticateOAuth(t *testing.T) {
+	service := Service{oauthProviders: map[string]types.OAuthProvider{
+		types.GoogleProvider: mockOAuthProvider{accounts: map[string]string{
+			"own-token":   "google-1",
+			"other-token": "google-2",
+		}},
+	}}
+	oauthUser := user.Model{OAuthInfo: &user.OAuthInfo{Provider: types.GoogleProvider, ProviderID: "google-1"}}
+
+	testCases := []struct {
+		name      string
+		userModel user.Model
+		token     string
+		wantErr   bool
+	}{
+		{"token of the provider account", oauthUser, "own-token", false},
+		{"token of another provider account", oauthUser, "other-token", true},
+		{"invalid token", oauthUser, "expired-token", true},
+		{"empty token", oauthUser, "", true},
+		{"disabled provider", user.Model{OAuthInfo: &user.OAuthInfo{Provider: types.AppleProvider, ProviderID: "google-1"}}, "own-token", true},
+		{"user without OAuth provider", user.Model{}, "own-token", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := service.ReauthenticateOAuth(tc.userModel, tc.token)
+			if tc.wantErr && !errors.Is(err, constants.ErrorUnauthorized) {
+				t.Errorf("Expected unauthorized, got %v", err)
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("Expected the token to re-authenticate the user, got %v", err)
+			}
+		})
+	}
+}
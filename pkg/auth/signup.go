@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"os"
+	"strings"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+)
+
+// SignupMode decides who can create an account without an invitation
+type SignupMode string
+
+const (
+	// SignupModeOpen lets anyone sign up
+	SignupModeOpen SignupMode = "open"
+	// SignupModeInviteOnly only lets invited users sign up
+	SignupModeInviteOnly SignupMode = "invite_only"
+	// SignupModeDomainRestricted only lets users with an email of an allowed domain sign up
+	SignupModeDomainRestricted SignupMode = "domain_restricted"
+)
+
+// GetSignupMode reads the signup mode from SIGNUP_MODE, signup is open by default
+func GetSignupMode() SignupMode {
+	switch mode := SignupMode(strings.ToLower(os.Getenv("SIGNUP_MODE"))); mode {
+	case SignupModeInviteOnly, SignupModeDomainRestricted:
+		return mode
+	default:
+		return SignupModeOpen
+	}
+}
+
+// CheckSignupAllowed checks if a user with the email can sign up without an invitation.
+// The allowed domains of the domain restricted mode are read from SIGNUP_ALLOWED_DOMAINS, separated by commas.
+func CheckSignupAllowed(email string) error {
+	switch GetSignupMode() {
+	case SignupModeInviteOnly:
+		return constants.ErrorPermissionDenied
+	case SignupModeDomainRestricted:
+		at := strings.LastIndex(email, "@")
+		if at < 0 {
+			return constants.ErrorPermissionDenied
+		}
+		domain := strings.ToLower(email[at+1:])
+		for _, allowed := range strings.Split(os.Getenv("SIGNUP_ALLOWED_DOMAINS"), ",") {
+			if allowed = strings.ToLower(strings.TrimSpace(allowed)); allowed != "" && allowed == domain {
+				return nil
+			}
+		}
+		return constants.ErrorPermissionDenied
+	default:
+		return nil
+	}
+}
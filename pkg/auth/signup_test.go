@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+)
+
+func TestCheckSignupAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		domains string
+		email   string
+		allowed bool
+	}{
+		{"open by default", "", "", "jane@example.com", true},
+		{"unknown mode is open", "closed", "", "jane@example.com", true},
+		{"invite only", "invite_only", "", "jane@example.com", false},
+		{"allowed domain", "domain_restricted", "example.com, acme.io", "jane@Acme.io", true},
+		{"other domain", "domain_restricted", "example.com", "jane@example.org", false},
+		{"subdomain is not allowed", "domain_restricted", "example.com", "jane@mail.example.com", false},
+		{"missing domain", "domain_restricted", "example.com", "jane", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SIGNUP_MODE", tt.mode)
+			t.Setenv("SIGNUP_ALLOWED_DOMAINS", tt.domains)
+
+			err := CheckSignupAllowed(tt.email)
+			if tt.allowed && err != nil {
+				t.Errorf("Expected %s to be allowed, got %v", tt.email, err)
+			}
+			if !tt.allowed && !errors.Is(err, constants.ErrorPermissionDenied) {
+				t.Errorf("Expected %s to be denied, got %v", tt.email, err)
+			}
+		})
+	}
+}
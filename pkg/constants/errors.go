@@ -10,4 +10,11 @@ var (
 	ErrorInternalServerError = errors.New("internal server error")
 	ErrorConflict            = errors.New("conflict")
 	ErrorOAuthWithPassWord   = errors.New("cannot use password with an account that has OAuth")
+	ErrorAccountSuspended    = errors.New("account suspended")
+	ErrorAccountBanned       = errors.New("account banned")
+	ErrorAccountDeactivated  = errors.New("account deactivated")
+	// ErrorVersionConflict is returned when a document was modified since the version an update expects
+	ErrorVersionConflict = errors.New("version conflict")
+	// ErrorTooManyRequests is returned when a rate limited action is repeated too often
+	ErrorTooManyRequests = errors.New("too many requests")
 )
@@ -0,0 +1,59 @@
+package changelog
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateChangelogEntryCommand struct {
+	Version         string               `json:"version"`
+	Title           string               `json:"title"`
+	Body            string               `json:"body"`
+	PublishDate     *time.Time           `json:"publishDate,omitempty"`
+	AudienceRoleIDs []primitive.ObjectID `json:"audienceRoleIds"`
+}
+
+func (cmd CreateChangelogEntryCommand) Validate() error {
+	return validateEntry(cmd.Version, cmd.Title)
+}
+
+type UpdateChangelogEntryCommand struct {
+	Version         string               `json:"version" bson:"version"`
+	Title           string               `json:"title" bson:"title"`
+	Body            string               `json:"body" bson:"body"`
+	PublishDate     time.Time            `json:"publishDate" bson:"publishDate"`
+	AudienceRoleIDs []primitive.ObjectID `json:"audienceRoleIds" bson:"audienceRoleIds"`
+}
+
+func (cmd UpdateChangelogEntryCommand) Validate() error {
+	if cmd.PublishDate.IsZero() {
+		return errors.New("publish date is required")
+	}
+	return validateEntry(cmd.Version, cmd.Title)
+}
+
+// MarkSeenCommand advances the last seen changelog version of the current user. Without a version, every
+// published entry is marked as seen.
+type MarkSeenCommand struct {
+	Version string `json:"version,omitempty"`
+}
+
+func (cmd MarkSeenCommand) Validate() error {
+	if cmd.Version == "" {
+		return nil
+	}
+	_, err := ParseVersion(cmd.Version)
+	return err
+}
+
+func validateEntry(version, title string) error {
+	if _, err := ParseVersion(version); err != nil {
+		return err
+	}
+	if title == "" {
+		return errors.New("title is required")
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package changelog
+
+import (
+	"fmt"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+)
+
+// ErrVersionExists is the error returned when another changelog entry has the same version
+var ErrVersionExists = fmt.Errorf("%w: a changelog entry with this version already exists", constants.ErrorConflict)
@@ -0,0 +1,94 @@
+package changelog
+
+import (
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Model is a changelog entry, the release notes of a version. Entries are shown to the users once their publish
+// date has passed, only to the users with one of the audience roles if there are any.
+type Model struct {
+	ID              primitive.ObjectID   `json:"id" bson:"_id"`
+	Version         string               `json:"version" bson:"version"`
+	Title           string               `json:"title" bson:"title"`
+	Body            string               `json:"body" bson:"body"`
+	PublishDate     time.Time            `json:"publishDate" bson:"publishDate"`
+	AudienceRoleIDs []primitive.ObjectID `json:"audienceRoleIds" bson:"audienceRoleIds"`
+	CreatedBy       *primitive.ObjectID  `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedDate     time.Time            `json:"createdDate" bson:"createdDate"`
+	UpdatedDate     time.Time            `json:"updatedDate" bson:"updatedDate"`
+}
+
+// NewEntry creates a changelog entry from the command, published now if the command has no publish date
+func NewEntry(command CreateChangelogEntryCommand, createdBy *primitive.ObjectID, now time.Time) Model {
+	entry := Model{
+		ID:              primitive.NewObjectID(),
+		Version:         command.Version,
+		Title:           command.Title,
+		Body:            command.Body,
+		PublishDate:     now,
+		AudienceRoleIDs: command.AudienceRoleIDs,
+		CreatedBy:       createdBy,
+		CreatedDate:     now,
+		UpdatedDate:     now,
+	}
+	if command.PublishDate != nil {
+		entry.PublishDate = *command.PublishDate
+	}
+	if entry.AudienceRoleIDs == nil {
+		entry.AudienceRoleIDs = []primitive.ObjectID{}
+	}
+	return entry
+}
+
+// IsVisibleTo checks if the entry is published and the user with the roles is in its audience
+func (m Model) IsVisibleTo(roleIDs []primitive.ObjectID, now time.Time) bool {
+	if now.Before(m.PublishDate) {
+		return false
+	}
+	if len(m.AudienceRoleIDs) == 0 {
+		return true
+	}
+	for _, audienceRoleID := range m.AudienceRoleIDs {
+		for _, roleID := range roleIDs {
+			if audienceRoleID == roleID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Unseen returns the entries visible to the user with the roles that are newer than the last seen version, the
+// newest first. Every entry is unseen if the user has not seen any version.
+func Unseen(entries []Model, lastSeenVersion string, roleIDs []primitive.ObjectID, now time.Time) []Model {
+	lastSeen, err := ParseVersion(lastSeenVersion)
+	hasSeen := lastSeenVersion != "" && err == nil
+
+	unseen := make([]Model, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsVisibleTo(roleIDs, now) {
+			continue
+		}
+		version, err := ParseVersion(entry.Version)
+		if err != nil {
+			continue
+		}
+		if !hasSeen || version.Compare(lastSeen) > 0 {
+			unseen = append(unseen, entry)
+		}
+	}
+	SortNewestFirst(unseen)
+	return unseen
+}
+
+// SortNewestFirst sorts the entries by their semantic versions, the newest first
+func SortNewestFirst(entries []Model) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, _ := ParseVersion(entries[i].Version)
+		b, _ := ParseVersion(entries[j].Version)
+		return a.Compare(b) > 0
+	})
+}
@@ -0,0 +1,147 @@
+package changelog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SemVer is a semantic version, https://semver.org. The build metadata is ignored as it does not affect precedence.
+type SemVer struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	PreRelease []string
+}
+
+// ParseVersion parses a semantic version such as 1.4.0 or 2.0.0-beta.1, an optional leading v is allowed
+func ParseVersion(version string) (SemVer, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexByte(text, '+'); i >= 0 {
+		text = text[:i]
+	}
+	var preRelease []string
+	if i := strings.IndexByte(text, '-'); i >= 0 {
+		preRelease = strings.Split(text[i+1:], ".")
+		text = text[:i]
+		for _, identifier := range preRelease {
+			if identifier == "" || !isIdentifier(identifier) || (isNumeric(identifier) && len(identifier) > 1 && identifier[0] == '0') {
+				return SemVer{}, fmt.Errorf("invalid pre-release of version %q", version)
+			}
+		}
+	}
+
+	parts := strings.Split(text, ".")
+	if len(parts) != 3 {
+		return SemVer{}, fmt.Errorf("version %q must be MAJOR.MINOR.PATCH", version)
+	}
+	numbers := make([]uint64, 3)
+	for i, part := range parts {
+		if !isNumeric(part) || (len(part) > 1 && part[0] == '0') {
+			return SemVer{}, fmt.Errorf("version %q must be MAJOR.MINOR.PATCH", version)
+		}
+		number, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return SemVer{}, fmt.Errorf("version %q must be MAJOR.MINOR.PATCH", version)
+		}
+		numbers[i] = number
+	}
+	return SemVer{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], PreRelease: preRelease}, nil
+}
+
+// Compare returns -1, 0 or 1 if v is lower than, equal to or greater than other. A pre-release is lower than its
+// release.
+func (v SemVer) Compare(other SemVer) int {
+	if c := compareUint(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+
+	switch {
+	case len(v.PreRelease) == 0 && len(other.PreRelease) == 0:
+		return 0
+	case len(v.PreRelease) == 0:
+		return 1
+	case len(other.PreRelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.PreRelease) && i < len(other.PreRelease); i++ {
+		if c := compareIdentifier(v.PreRelease[i], other.PreRelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.PreRelease)), uint64(len(other.PreRelease)))
+}
+
+// String formats the version without a leading v
+func (v SemVer) String() string {
+	version := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.PreRelease) > 0 {
+		version += "-" + strings.Join(v.PreRelease, ".")
+	}
+	return version
+}
+
+// compareIdentifier compares pre-release identifiers, numeric ones numerically and lower than alphanumeric ones
+func compareIdentifier(a, b string) int {
+	aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+	switch {
+	case aNumeric && bNumeric:
+		if c := compareUint(uint64(len(a)), uint64(len(b))); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentifier(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// CanonicalVersion formats a valid version without a leading v or build metadata, so equal versions are stored alike
+func CanonicalVersion(version string) string {
+	parsed, err := ParseVersion(version)
+	if err != nil {
+		return version
+	}
+	return parsed.String()
+}
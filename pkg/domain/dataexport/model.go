@@ -0,0 +1,97 @@
+package dataexport
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status is the state of a data export
+type Status string
+
+const (
+	StatusPending Status = "PENDING"
+	StatusReady   Status = "READY"
+	StatusFailed  Status = "FAILED"
+)
+
+// Model is an export of the data of a user, assembled as a ZIP archive in the background and kept in the blob store
+// under ArchiveKey. Only the hash of the download token is stored, the token itself is sent to the user.
+type Model struct {
+	ID          primitive.ObjectID  `json:"id" bson:"_id"`
+	UserID      primitive.ObjectID  `json:"userId" bson:"userId"`
+	RequestedBy *primitive.ObjectID `json:"requestedBy,omitempty" bson:"requestedBy,omitempty"`
+	Status      Status              `json:"status" bson:"status"`
+	TokenHash   string              `json:"-" bson:"tokenHash"`
+	ArchiveKey  string              `json:"-" bson:"archiveKey,omitempty"`
+	ExpiresAt   time.Time           `json:"expiresAt" bson:"expiresAt"`
+	CompletedAt *time.Time          `json:"completedAt,omitempty" bson:"completedAt,omitempty"`
+	CreatedDate time.Time           `json:"createdDate" bson:"createdDate"`
+	Version     int                 `json:"version" bson:"version"`
+}
+
+// EmailTemplateData is the data the export email template is rendered with
+type EmailTemplateData struct {
+	Username  string
+	Token     string
+	Link      string
+	ExpiresAt time.Time
+}
+
+type Option func(*Model) error
+
+func NewDataExport(opts ...Option) (*Model, error) {
+	e := &Model{
+		ID:          primitive.NewObjectID(),
+		Status:      StatusPending,
+		CreatedDate: time.Now(),
+		Version:     1,
+	}
+
+	for _, opt := range opts {
+		if err := opt(e); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func WithUserID(userID primitive.ObjectID) Option {
+	return func(e *Model) error {
+		e.UserID = userID
+		return nil
+	}
+}
+
+func WithRequestedBy(requestedBy *primitive.ObjectID) Option {
+	return func(e *Model) error {
+		e.RequestedBy = requestedBy
+		return nil
+	}
+}
+
+func WithTokenHash(tokenHash string) Option {
+	return func(e *Model) error {
+		e.TokenHash = tokenHash
+		return nil
+	}
+}
+
+func WithExpiresAt(expiresAt time.Time) Option {
+	return func(e *Model) error {
+		e.ExpiresAt = expiresAt
+		return nil
+	}
+}
+
+// ArchiveKey returns the blob store key of the archive of an export
+func ArchiveKey(exportID primitive.ObjectID) string {
+	return fmt.Sprintf("data-exports/%s.zip", exportID.Hex())
+}
+
+// IsExpired checks if the download link of the export is no longer valid
+func (m Model) IsExpired(now time.Time) bool {
+	return !m.ExpiresAt.After(now)
+}
@@ -15,4 +15,6 @@ type SupportedEmailType string
 const (
 	EmailTypeResetPassword SupportedEmailType = "RESET_PASSWORD"
 	EmailTypeFeedback      SupportedEmailType = "FEEDBACK"
+	EmailTypeInvitation    SupportedEmailType = "INVITATION"
+	EmailTypeDataExport    SupportedEmailType = "DATA_EXPORT"
 )
@@ -0,0 +1,33 @@
+package group
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateGroupCommand struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	RoleIDs     []primitive.ObjectID `json:"roleIds"`
+	ParentIDs   []primitive.ObjectID `json:"parentIds"`
+}
+
+type UpdateGroupCommand struct {
+	Name        string               `json:"name" bson:"name"`
+	Description string               `json:"description" bson:"description"`
+	RoleIDs     []primitive.ObjectID `json:"roleIds" bson:"roleIds"`
+	ParentIDs   []primitive.ObjectID `json:"parentIds" bson:"parentIds"`
+}
+
+func (cmd UpdateGroupCommand) Validate() error {
+	if len(cmd.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
+
+type AddGroupMemberCommand struct {
+	UserID primitive.ObjectID `json:"userId"`
+}
@@ -0,0 +1,98 @@
+package group
+
+import (
+	"errors"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Model is a group of users. Members hold the roles of the group and of its parent groups.
+type Model struct {
+	ID          primitive.ObjectID   `json:"id" bson:"_id"`
+	Name        string               `json:"name" bson:"name"`
+	Description string               `json:"description,omitempty" bson:"description,omitempty"`
+	RoleIDs     []primitive.ObjectID `json:"roleIds" bson:"roleIds"`
+	// ParentIDs are the groups this group is nested in
+	ParentIDs   []primitive.ObjectID `json:"parentIds" bson:"parentIds"`
+	CreatedDate time.Time            `json:"createdDate" bson:"createdDate"`
+	Version     int                  `json:"version" bson:"version"`
+}
+
+// Member is the membership of a user in a group
+type Member struct {
+	ID      primitive.ObjectID  `json:"id" bson:"_id"`
+	GroupID primitive.ObjectID  `json:"groupId" bson:"groupId"`
+	UserID  primitive.ObjectID  `json:"userId" bson:"userId"`
+	AddedBy *primitive.ObjectID `json:"addedBy,omitempty" bson:"addedBy,omitempty"`
+	AddedAt time.Time           `json:"addedAt" bson:"addedAt"`
+	// User is the public profile of the member, filled in the member listings
+	User *user.PublicProfile `json:"user,omitempty" bson:"-"`
+}
+
+type Option func(*Model) error
+
+func NewGroup(opts ...Option) (*Model, error) {
+	g := &Model{
+		ID:          primitive.NewObjectID(),
+		RoleIDs:     []primitive.ObjectID{},
+		ParentIDs:   []primitive.ObjectID{},
+		CreatedDate: time.Now(),
+		Version:     1,
+	}
+
+	for _, opt := range opts {
+		if err := opt(g); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+func WithName(name string) Option {
+	return func(g *Model) error {
+		g.Name = name
+		return nil
+	}
+}
+
+func WithDescription(description string) Option {
+	return func(g *Model) error {
+		g.Description = description
+		return nil
+	}
+}
+
+func WithRoleIDs(roleIDs []primitive.ObjectID) Option {
+	return func(g *Model) error {
+		if roleIDs != nil {
+			g.RoleIDs = roleIDs
+		}
+		return nil
+	}
+}
+
+func WithParentIDs(parentIDs []primitive.ObjectID) Option {
+	return func(g *Model) error {
+		if parentIDs != nil {
+			g.ParentIDs = parentIDs
+		}
+		return nil
+	}
+}
+
+func (g Model) Validate() error {
+	if len(g.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	for _, parentID := range g.ParentIDs {
+		if parentID == g.ID {
+			return errors.New("group cannot be its own parent")
+		}
+	}
+
+	return nil
+}
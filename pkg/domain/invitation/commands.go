@@ -0,0 +1,33 @@
+package invitation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateInvitationCommand struct {
+	Email   string               `json:"email"`
+	RoleIDs []primitive.ObjectID `json:"roleIds"`
+	// ExpiresAt defaults to INVITATION_EXPIRES_IN_HOUR hours from now
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// AcceptInvitationCommand creates the account of the invitee, the email is taken from the invitation
+type AcceptInvitationCommand struct {
+	Token      string                 `json:"token"`
+	Username   string                 `json:"username"`
+	Password   string                 `json:"password"`
+	PersonInfo *user.PersonInfo       `json:"personInfo"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+func (cmd AcceptInvitationCommand) Validate() error {
+	if cmd.Token == "" {
+		return errors.New("token is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package invitation
+
+import (
+	"fmt"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+)
+
+// ErrInvitationExpired is the error returned when an invitation has expired
+var ErrInvitationExpired = fmt.Errorf("%w: invitation has expired", constants.ErrorBadRequest)
+
+// ErrInvitationNotPending is the error returned when an invitation was already accepted or revoked
+var ErrInvitationNotPending = fmt.Errorf("%w: invitation is not pending", constants.ErrorConflict)
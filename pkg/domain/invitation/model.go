@@ -0,0 +1,120 @@
+package invitation
+
+import (
+	"errors"
+	"net/mail"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status is the state of an invitation
+type Status string
+
+const (
+	StatusPending  Status = "PENDING"
+	StatusAccepted Status = "ACCEPTED"
+	StatusRevoked  Status = "REVOKED"
+)
+
+// Model is an invitation to create an account with preassigned roles.
+// Only the hash of the token is stored, the token itself is sent to the invitee.
+type Model struct {
+	ID             primitive.ObjectID   `json:"id" bson:"_id"`
+	Email          string               `json:"email" bson:"email"`
+	RoleIDs        []primitive.ObjectID `json:"roleIds" bson:"roleIds"`
+	TokenHash      string               `json:"-" bson:"tokenHash"`
+	Status         Status               `json:"status" bson:"status"`
+	InvitedBy      *primitive.ObjectID  `json:"invitedBy,omitempty" bson:"invitedBy,omitempty"`
+	ExpiresAt      time.Time            `json:"expiresAt" bson:"expiresAt"`
+	AcceptedAt     *time.Time           `json:"acceptedAt,omitempty" bson:"acceptedAt,omitempty"`
+	AcceptedUserID *primitive.ObjectID  `json:"acceptedUserId,omitempty" bson:"acceptedUserId,omitempty"`
+	CreatedDate    time.Time            `json:"createdDate" bson:"createdDate"`
+	Version        int                  `json:"version" bson:"version"`
+}
+
+// EmailTemplateData is the data the invitation email template is rendered with
+type EmailTemplateData struct {
+	Email     string
+	Token     string
+	Link      string
+	ExpiresAt time.Time
+}
+
+type Option func(*Model) error
+
+func NewInvitation(opts ...Option) (*Model, error) {
+	i := &Model{
+		ID:          primitive.NewObjectID(),
+		RoleIDs:     []primitive.ObjectID{},
+		Status:      StatusPending,
+		CreatedDate: time.Now(),
+		Version:     1,
+	}
+
+	for _, opt := range opts {
+		if err := opt(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+func WithEmail(email string) Option {
+	return func(i *Model) error {
+		i.Email = email
+		return nil
+	}
+}
+
+func WithRoleIDs(roleIDs []primitive.ObjectID) Option {
+	return func(i *Model) error {
+		if roleIDs != nil {
+			i.RoleIDs = roleIDs
+		}
+		return nil
+	}
+}
+
+func WithTokenHash(tokenHash string) Option {
+	return func(i *Model) error {
+		i.TokenHash = tokenHash
+		return nil
+	}
+}
+
+func WithInvitedBy(invitedBy *primitive.ObjectID) Option {
+	return func(i *Model) error {
+		i.InvitedBy = invitedBy
+		return nil
+	}
+}
+
+func WithExpiresAt(expiresAt time.Time) Option {
+	return func(i *Model) error {
+		i.ExpiresAt = expiresAt
+		return nil
+	}
+}
+
+func (i Model) Validate() error {
+	if _, err := mail.ParseAddress(i.Email); err != nil {
+		return errors.New("email is invalid")
+	}
+
+	if i.TokenHash == "" {
+		return errors.New("token is required")
+	}
+
+	if !i.ExpiresAt.After(i.CreatedDate) {
+		return errors.New("expiry date must be in the future")
+	}
+
+	return nil
+}
+
+// IsExpired checks if the invitation has expired
+func (i Model) IsExpired(now time.Time) bool {
+	return !i.ExpiresAt.After(now)
+}
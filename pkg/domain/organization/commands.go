@@ -0,0 +1,38 @@
+package organization
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CreateOrganizationCommand struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type UpdateOrganizationCommand struct {
+	Name string `json:"name" bson:"name"`
+}
+
+func (cmd UpdateOrganizationCommand) Validate() error {
+	if len(cmd.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
+
+type AddMemberCommand struct {
+	UserID  primitive.ObjectID   `json:"userId"`
+	RoleIDs []primitive.ObjectID `json:"roleIds"`
+}
+
+type UpdateMemberRolesCommand struct {
+	RoleIDs []primitive.ObjectID `json:"roleIds" bson:"roleIds"`
+}
+
+// SwitchOrganizationCommand selects the active organization of the tokens, an empty ID leaves the organization
+type SwitchOrganizationCommand struct {
+	OrganizationID string `json:"organizationId"`
+}
@@ -0,0 +1,77 @@
+package organization
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Model is a customer organization, the tenant of the data scoped to it
+type Model struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	Name        string             `json:"name" bson:"name"`
+	Slug        string             `json:"slug" bson:"slug"`
+	CreatedDate time.Time          `json:"createdDate" bson:"createdDate"`
+	Version     int                `json:"version" bson:"version"`
+}
+
+// Membership is the membership of a user in an organization with the roles the user holds in it
+type Membership struct {
+	ID             primitive.ObjectID   `json:"id" bson:"_id"`
+	OrganizationID primitive.ObjectID   `json:"organizationId" bson:"organizationId"`
+	UserID         primitive.ObjectID   `json:"userId" bson:"userId"`
+	RoleIDs        []primitive.ObjectID `json:"roleIds" bson:"roleIds"`
+	AddedBy        *primitive.ObjectID  `json:"addedBy,omitempty" bson:"addedBy,omitempty"`
+	JoinedAt       time.Time            `json:"joinedAt" bson:"joinedAt"`
+	// User is the public profile of the member, filled in the member listings
+	User *user.PublicProfile `json:"user,omitempty" bson:"-"`
+}
+
+type Option func(*Model) error
+
+func NewOrganization(opts ...Option) (*Model, error) {
+	o := &Model{
+		ID:          primitive.NewObjectID(),
+		CreatedDate: time.Now(),
+		Version:     1,
+	}
+
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+func WithName(name string) Option {
+	return func(o *Model) error {
+		o.Name = name
+		return nil
+	}
+}
+
+func WithSlug(slug string) Option {
+	return func(o *Model) error {
+		o.Slug = slug
+		return nil
+	}
+}
+
+func (o Model) Validate() error {
+	if len(o.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	if !slugPattern.MatchString(o.Slug) {
+		return errors.New("slug must consist of lowercase letters, digits and dashes")
+	}
+
+	return nil
+}
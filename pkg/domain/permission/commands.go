@@ -0,0 +1,27 @@
+package permission
+
+import (
+	"errors"
+
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExplainCommand asks why a user is allowed or denied a permission.
+// Resource is optional, when given the policies are evaluated against it.
+type ExplainCommand struct {
+	UserID     primitive.ObjectID `json:"userId"`
+	Permission auth.Permission    `json:"permission"`
+	Resource   *auth.Resource     `json:"resource,omitempty"`
+}
+
+func (cmd ExplainCommand) Validate() error {
+	if cmd.UserID.IsZero() {
+		return errors.New("userId is required")
+	}
+	if cmd.Permission.Domain == "" || cmd.Permission.Action == "" {
+		return errors.New("permission domain and action are required")
+	}
+
+	return nil
+}
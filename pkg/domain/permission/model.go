@@ -0,0 +1,28 @@
+package permission
+
+import (
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RoleReference identifies a role in an explanation
+type RoleReference struct {
+	ID   primitive.ObjectID `json:"id"`
+	Name string             `json:"name"`
+}
+
+// Grant is an effective permission together with the roles that contribute it
+type Grant struct {
+	Permission auth.Permission `json:"permission"`
+	Roles      []RoleReference `json:"roles"`
+}
+
+// Explanation describes the decision for a user and a required permission
+type Explanation struct {
+	UserID     primitive.ObjectID `json:"userId"`
+	Permission auth.Permission    `json:"permission"`
+	auth.Decision
+	// DecidingRoles are the roles that contribute the matched permission
+	DecidingRoles        []RoleReference `json:"decidingRoles"`
+	EffectivePermissions []Grant         `json:"effectivePermissions"`
+}
@@ -0,0 +1,15 @@
+package phoneverification
+
+import "errors"
+
+// VerifyPhoneCommand verifies the phone number of the current user with the code sent to it
+type VerifyPhoneCommand struct {
+	Code string `json:"code"`
+}
+
+func (cmd VerifyPhoneCommand) Validate() error {
+	if cmd.Code == "" {
+		return errors.New("code is required")
+	}
+	return nil
+}
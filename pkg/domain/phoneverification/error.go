@@ -0,0 +1,34 @@
+package phoneverification
+
+import (
+	"fmt"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+)
+
+// ErrNoPhoneNumber is the error returned when the user has no phone number to verify
+var ErrNoPhoneNumber = fmt.Errorf("%w: user has no phone number", constants.ErrorBadRequest)
+
+// ErrPhoneAlreadyVerified is the error returned when the phone number of the user is already verified
+var ErrPhoneAlreadyVerified = fmt.Errorf("%w: phone number is already verified", constants.ErrorConflict)
+
+// ErrNoPendingVerification is the error returned when no code was sent to the phone number of the user
+var ErrNoPendingVerification = fmt.Errorf("%w: no verification code was sent", constants.ErrorBadRequest)
+
+// ErrCodeInvalid is the error returned when the code does not match the sent one
+var ErrCodeInvalid = fmt.Errorf("%w: verification code is invalid", constants.ErrorBadRequest)
+
+// ErrCodeExpired is the error returned when the sent code has expired
+var ErrCodeExpired = fmt.Errorf("%w: verification code has expired", constants.ErrorBadRequest)
+
+// ErrPhoneNumberChanged is the error returned when the phone number of the user changed after the code was sent
+var ErrPhoneNumberChanged = fmt.Errorf("%w: phone number has changed since the code was sent", constants.ErrorBadRequest)
+
+// ErrTooManyAttempts is the error returned when the code was entered wrong too many times
+var ErrTooManyAttempts = fmt.Errorf("%w: too many wrong codes, request a new code", constants.ErrorTooManyRequests)
+
+// ErrResendTooSoon is the error returned when a code is requested before the resend cooldown
+var ErrResendTooSoon = fmt.Errorf("%w: wait before requesting another code", constants.ErrorTooManyRequests)
+
+// ErrTooManyCodes is the error returned when too many codes were requested within the send window
+var ErrTooManyCodes = fmt.Errorf("%w: too many codes requested, try again later", constants.ErrorTooManyRequests)
@@ -0,0 +1,85 @@
+package phoneverification
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// CodeLifetime is the period a sent code can be verified in
+	CodeLifetime = 10 * time.Minute
+	// ResendCooldown is the period to wait before another code is sent
+	ResendCooldown = time.Minute
+	// SendWindow is the period MaxSendsPerWindow applies to
+	SendWindow = time.Hour
+	// MaxSendsPerWindow is the number of codes that can be sent to a user within SendWindow
+	MaxSendsPerWindow = 5
+	// MaxAttempts is the number of times a code can be entered, after which it is locked and a new one must be sent
+	MaxAttempts = 5
+)
+
+// Model is the pending verification of the phone number of a user, a user has at most one. Only the hash of the
+// code is stored, the code itself is sent by SMS.
+type Model struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	UserID      primitive.ObjectID `json:"userId" bson:"userId"`
+	PhoneNumber string             `json:"phoneNumber" bson:"phoneNumber"`
+	CodeHash    string             `json:"-" bson:"codeHash"`
+	ExpiresAt   time.Time          `json:"expiresAt" bson:"expiresAt"`
+	Attempts    int                `json:"-" bson:"attempts"`
+	SentCount   int                `json:"-" bson:"sentCount"`
+	WindowStart time.Time          `json:"-" bson:"windowStart"`
+	LastSentAt  time.Time          `json:"lastSentAt" bson:"lastSentAt"`
+	CreatedDate time.Time          `json:"createdDate" bson:"createdDate"`
+	Version     int                `json:"version" bson:"version"`
+}
+
+// NewVerification creates the verification of the phone number of a user, without a code yet
+func NewVerification(userID primitive.ObjectID, now time.Time) Model {
+	return Model{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		CreatedDate: now,
+		Version:     1,
+	}
+}
+
+// CheckResend checks if another code can be sent, the limits apply whatever the phone number is
+func (m Model) CheckResend(now time.Time) error {
+	if !m.LastSentAt.IsZero() && now.Before(m.LastSentAt.Add(ResendCooldown)) {
+		return ErrResendTooSoon
+	}
+	if now.Before(m.WindowStart.Add(SendWindow)) && m.SentCount >= MaxSendsPerWindow {
+		return ErrTooManyCodes
+	}
+	return nil
+}
+
+// Renew replaces the code of the verification with a newly sent one and counts the send
+func (m *Model) Renew(phoneNumber, codeHash string, now time.Time) {
+	if !now.Before(m.WindowStart.Add(SendWindow)) {
+		m.WindowStart = now
+		m.SentCount = 0
+	}
+	m.SentCount++
+	m.PhoneNumber = phoneNumber
+	m.CodeHash = codeHash
+	m.Attempts = 0
+	m.LastSentAt = now
+	m.ExpiresAt = now.Add(CodeLifetime)
+}
+
+// CheckVerifiable checks if the code of the verification can still be verified
+func (m Model) CheckVerifiable(now time.Time) error {
+	if m.CodeHash == "" {
+		return ErrNoPendingVerification
+	}
+	if m.Attempts >= MaxAttempts {
+		return ErrTooManyAttempts
+	}
+	if !now.Before(m.ExpiresAt) {
+		return ErrCodeExpired
+	}
+	return nil
+}
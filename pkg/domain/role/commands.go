@@ -1,15 +1,18 @@
 package role
 
 import (
+	"errors"
+
 	"github.com/LydiaTrack/ground/pkg/auth"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type CreateRoleCommand struct {
-	Name        string            `json:"name"`
-	Tags        []string          `json:"tags,omitempty"`
-	Info        string            `json:"info,omitempty"`
-	Permissions []auth.Permission `json:"permissions"`
+	Name        string               `json:"name"`
+	Tags        []string             `json:"tags,omitempty"`
+	Info        string               `json:"info,omitempty"`
+	Permissions []auth.Permission    `json:"permissions"`
+	ParentIDs   []primitive.ObjectID `json:"parentIds,omitempty"`
 }
 
 type UpdateRoleCommand struct {
@@ -17,6 +20,30 @@ type UpdateRoleCommand struct {
 	Info        string            `json:"info,omitempty" bson:"info,omitempty"`
 	Tags        []string          `json:"tags,omitempty" bson:"tags,omitempty"`
 	Permissions []auth.Permission `json:"permissions" bson:"permissions"`
+	// ParentIDs replace the parents of the role, they are kept when omitted and removed when empty
+	ParentIDs []primitive.ObjectID `json:"parentIds,omitempty" bson:"parentIds,omitempty"`
+	// ExpectedVersion guards the update against concurrent ones, the update is refused if the role has another
+	// version. It is not guarded when nil.
+	ExpectedVersion *int `json:"-" bson:"-"`
+}
+
+func (cmd UpdateRoleCommand) Validate() error {
+	if len(cmd.Name) == 0 {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
+
+// PatchableFields are the fields of a role a patch can change
+var PatchableFields = []string{"name", "info", "tags", "permissions", "parentIds"}
+
+// PatchRoleCommand applies a JSON merge patch or a JSON patch, told apart by the content type, to a role
+type PatchRoleCommand struct {
+	ContentType string
+	Patch       []byte
+	// ExpectedVersion guards the patch against concurrent updates like UpdateRoleCommand.ExpectedVersion
+	ExpectedVersion *int
 }
 
 type DeleteRoleCommand struct {
@@ -0,0 +1,211 @@
+package role
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest declares the roles that are reconciled at startup
+type Manifest struct {
+	// Prune deletes the roles that are not declared in the manifest, except the ones listed in Keep
+	Prune bool           `json:"prune" yaml:"prune"`
+	Keep  []string       `json:"keep,omitempty" yaml:"keep,omitempty"`
+	Roles []ManifestRole `json:"roles" yaml:"roles"`
+}
+
+// ManifestRole is the declaration of a single role. Parents are referenced by name.
+type ManifestRole struct {
+	Name        string            `json:"name" yaml:"name"`
+	Info        string            `json:"info,omitempty" yaml:"info,omitempty"`
+	Tags        []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Permissions []auth.Permission `json:"permissions" yaml:"permissions"`
+	// Parents are only managed if they are declared, an empty list removes the parents of the role
+	Parents []string `json:"parents,omitempty" yaml:"parents,omitempty"`
+	// Default marks the role to be assigned to every new user
+	Default bool `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// ParseManifest parses a manifest from YAML or JSON content, the format is decided by the file name extension
+func ParseManifest(fileName string, content []byte) (Manifest, error) {
+	var manifest Manifest
+	var err error
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &manifest)
+	case ".json":
+		err = json.Unmarshal(content, &manifest)
+	default:
+		return Manifest{}, fmt.Errorf("unsupported role manifest format: %s", fileName)
+	}
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse role manifest %s: %w", fileName, err)
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return Manifest{}, fmt.Errorf("invalid role manifest %s: %w", fileName, err)
+	}
+	return manifest, nil
+}
+
+// Validate checks that every role has a unique name and that parents are declared in the manifest
+func (m Manifest) Validate() error {
+	names := make(map[string]bool)
+	for _, r := range m.Roles {
+		if r.Name == "" {
+			return errors.New("role name is required")
+		}
+		if names[r.Name] {
+			return fmt.Errorf("role %s is declared more than once", r.Name)
+		}
+		names[r.Name] = true
+	}
+
+	for _, r := range m.Roles {
+		for _, parent := range r.Parents {
+			if !names[parent] {
+				return fmt.Errorf("parent %s of role %s is not declared", parent, r.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Merge returns a manifest with the roles of both manifests. Roles of other replace the roles with the same name,
+// prune settings are taken from other.
+func (m Manifest) Merge(other Manifest) Manifest {
+	merged := Manifest{
+		Prune: other.Prune,
+		Keep:  append(append([]string{}, m.Keep...), other.Keep...),
+	}
+
+	overridden := make(map[string]bool)
+	for _, r := range other.Roles {
+		overridden[r.Name] = true
+	}
+	for _, r := range m.Roles {
+		if !overridden[r.Name] {
+			merged.Roles = append(merged.Roles, r)
+		}
+	}
+	merged.Roles = append(merged.Roles, other.Roles...)
+	return merged
+}
+
+// GetDefaultRoleNames returns the names of the roles marked as default
+func (m Manifest) GetDefaultRoleNames() []string {
+	var roleNames []string
+	for _, r := range m.Roles {
+		if r.Default {
+			roleNames = append(roleNames, r.Name)
+		}
+	}
+	return roleNames
+}
+
+// ReconcileResult describes the changes applied while reconciling a manifest
+type ReconcileResult struct {
+	Created []string      `json:"created"`
+	Updated []RoleChanges `json:"updated"`
+	Deleted []string      `json:"deleted"`
+	// Skipped contains the roles that could not be pruned because they are still in use
+	Skipped []string `json:"skipped"`
+}
+
+// RoleChanges describes the difference between a stored role and its declaration
+type RoleChanges struct {
+	Name               string            `json:"name"`
+	AddedPermissions   []auth.Permission `json:"addedPermissions,omitempty"`
+	RemovedPermissions []auth.Permission `json:"removedPermissions,omitempty"`
+	InfoChanged        bool              `json:"infoChanged,omitempty"`
+	TagsChanged        bool              `json:"tagsChanged,omitempty"`
+	ParentsChanged     bool              `json:"parentsChanged,omitempty"`
+}
+
+// HasChanges checks if there is any difference
+func (c RoleChanges) HasChanges() bool {
+	return len(c.AddedPermissions) > 0 || len(c.RemovedPermissions) > 0 || c.InfoChanged || c.TagsChanged || c.ParentsChanged
+}
+
+// IsEmpty checks if the reconciliation did not change anything
+func (r ReconcileResult) IsEmpty() bool {
+	return len(r.Created) == 0 && len(r.Updated) == 0 && len(r.Deleted) == 0 && len(r.Skipped) == 0
+}
+
+// String formats the result as a diff, one line per change
+func (r ReconcileResult) String() string {
+	var lines []string
+	for _, name := range r.Created {
+		lines = append(lines, "+ "+name)
+	}
+	for _, changes := range r.Updated {
+		var details []string
+		for _, permission := range changes.AddedPermissions {
+			details = append(details, "+"+permission.Domain+"/"+permission.Action)
+		}
+		for _, permission := range changes.RemovedPermissions {
+			details = append(details, "-"+permission.Domain+"/"+permission.Action)
+		}
+		if changes.InfoChanged {
+			details = append(details, "info")
+		}
+		if changes.TagsChanged {
+			details = append(details, "tags")
+		}
+		if changes.ParentsChanged {
+			details = append(details, "parents")
+		}
+		lines = append(lines, "~ "+changes.Name+": "+strings.Join(details, " "))
+	}
+	for _, name := range r.Deleted {
+		lines = append(lines, "- "+name)
+	}
+	for _, name := range r.Skipped {
+		lines = append(lines, "! "+name+" (in use, not pruned)")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DiffPermissions returns the permissions of desired missing in current and the permissions of current missing in desired
+func DiffPermissions(current, desired []auth.Permission) (added []auth.Permission, removed []auth.Permission) {
+	currentSet := make(map[auth.Permission]bool)
+	for _, permission := range current {
+		currentSet[permission] = true
+	}
+	desiredSet := make(map[auth.Permission]bool)
+	for _, permission := range desired {
+		desiredSet[permission] = true
+		if !currentSet[permission] {
+			added = append(added, permission)
+		}
+	}
+	for _, permission := range current {
+		if !desiredSet[permission] {
+			removed = append(removed, permission)
+		}
+	}
+	return added, removed
+}
+
+// SameStrings checks if both slices contain the same strings regardless of order
+func SameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
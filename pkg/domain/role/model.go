@@ -10,13 +10,21 @@ import (
 )
 
 type Model struct {
-	ID          primitive.ObjectID `json:"id" bson:"_id"`
-	Name        string             `json:"name" bson:"name"`
-	Permissions []auth.Permission  `json:"permissions" bson:"permissions"`
-	Tags        []string           `json:"tags,omitempty" bson:"tags,omitempty"`
-	Info        string             `json:"info,omitempty" bson:"info,omitempty"`
-	CreatedDate time.Time          `json:"createdDate" bson:"createdDate"`
-	Version     int                `json:"version" bson:"version"`
+	ID          primitive.ObjectID   `json:"id" bson:"_id"`
+	Name        string               `json:"name" bson:"name"`
+	Permissions []auth.Permission    `json:"permissions" bson:"permissions"`
+	Tags        []string             `json:"tags,omitempty" bson:"tags,omitempty"`
+	Info        string               `json:"info,omitempty" bson:"info,omitempty"`
+	ParentIDs   []primitive.ObjectID `json:"parentIds,omitempty" bson:"parentIds,omitempty"`
+	CreatedDate time.Time            `json:"createdDate" bson:"createdDate"`
+	Version     int                  `json:"version" bson:"version"`
+}
+
+// EffectiveModel is a role together with the permissions it inherits from its ancestors
+type EffectiveModel struct {
+	Model
+	EffectivePermissions []auth.Permission    `json:"effectivePermissions"`
+	AncestorIDs          []primitive.ObjectID `json:"ancestorIds"`
 }
 
 type Option func(*Model) error
@@ -65,12 +73,25 @@ func WithInfo(info string) Option {
 	}
 }
 
+func WithParentIDs(parentIDs []primitive.ObjectID) Option {
+	return func(r *Model) error {
+		r.ParentIDs = parentIDs
+		return nil
+	}
+}
+
 func (r Model) Validate() error {
 
 	if len(r.Name) == 0 {
 		return errors.New("name is required")
 	}
 
+	for _, parentID := range r.ParentIDs {
+		if parentID == r.ID {
+			return errors.New("role cannot be its own parent")
+		}
+	}
+
 	return nil
 }
 
@@ -93,3 +114,19 @@ func (r Model) HasPermission(permission auth.Permission) bool {
 
 	return false
 }
+
+// MergePermissions returns the permissions of the given roles without duplicates
+func MergePermissions(roles []Model) []auth.Permission {
+	seen := make(map[auth.Permission]bool)
+	permissions := []auth.Permission{}
+	for _, r := range roles {
+		for _, permission := range r.Permissions {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+
+	return permissions
+}
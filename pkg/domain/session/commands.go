@@ -1,9 +1,13 @@
 package session
 
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
 type CreateSessionCommand struct {
 	UserID       string `json:"userID"`
 	ExpireTime   int64  `json:"expireTime"`
 	RefreshToken string `json:"refreshToken"`
+	// OrganizationID is the active organization of the session, nil when no organization is active
+	OrganizationID *primitive.ObjectID `json:"organizationID,omitempty"`
 }
 
 type DeleteSessionCommand struct {
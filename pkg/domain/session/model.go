@@ -8,4 +8,6 @@ type InfoModel struct {
 	UserID       primitive.ObjectID `json:"userID" bson:"userId"`
 	ExpireTime   int64              `json:"expireTime" bson:"expireTime"`
 	RefreshToken string             `json:"refreshToken" bson:"refreshToken"`
+	// OrganizationID is kept in the session, so refreshed tokens keep the active organization
+	OrganizationID *primitive.ObjectID `json:"organizationID,omitempty" bson:"organizationId,omitempty"`
 }
@@ -0,0 +1,16 @@
+package settings
+
+import "errors"
+
+// UpdateSettingsCommand sets the given keys of a namespace, the other keys are kept. Null values reset the keys to
+// their defaults.
+type UpdateSettingsCommand struct {
+	Values map[string]interface{} `json:"values"`
+}
+
+func (cmd UpdateSettingsCommand) Validate() error {
+	if len(cmd.Values) == 0 {
+		return errors.New("values are required")
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package settings
+
+import (
+	"fmt"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+)
+
+// ErrNamespaceNotFound is the error returned for a namespace that was not registered
+var ErrNamespaceNotFound = fmt.Errorf("%w: settings namespace not found", constants.ErrorNotFound)
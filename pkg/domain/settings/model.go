@@ -0,0 +1,32 @@
+package settings
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Model holds the settings a user has set, by namespace and key. It is stored apart from the user, keyed by the ID
+// of the user, so settings are updated key by key.
+type Model struct {
+	UserID      primitive.ObjectID                `json:"userId" bson:"_id"`
+	Namespaces  map[string]map[string]interface{} `json:"namespaces" bson:"namespaces"`
+	CreatedDate time.Time                         `json:"createdDate" bson:"createdDate"`
+	UpdatedDate time.Time                         `json:"updatedDate" bson:"updatedDate"`
+	Version     int                               `json:"version" bson:"version"`
+}
+
+// Settings are the settings of a namespace for a user, the defaults fill in the keys the user has not set
+type Settings struct {
+	Namespace string                 `json:"namespace"`
+	Values    map[string]interface{} `json:"values"`
+}
+
+// Values returns the values of every namespace, the documents and arrays decoded from BSON converted to JSON values
+func (m Model) Values() map[string]map[string]interface{} {
+	values := make(map[string]map[string]interface{}, len(m.Namespaces))
+	for namespace, namespaceValues := range m.Namespaces {
+		values[namespace] = normalize(namespaceValues).(map[string]interface{})
+	}
+	return values
+}
@@ -0,0 +1,142 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxValueSize is the maximum size of a setting value encoded as JSON
+const MaxValueSize = 4096
+
+// namePattern matches the names of the namespaces and their keys, they are used in the stored paths
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// Namespace is a group of settings registered by a host application, such as "ui" or "notifications". The type of
+// each key is the JSON type of its default, a key with a null default accepts any value.
+type Namespace struct {
+	Name     string
+	Defaults map[string]interface{}
+}
+
+// NewNamespace creates a namespace with the defaults of its keys, the defaults are normalized to their JSON values
+func NewNamespace(name string, defaults map[string]interface{}) (Namespace, error) {
+	if !namePattern.MatchString(name) {
+		return Namespace{}, fmt.Errorf("invalid settings namespace %q, use letters, digits, - and _", name)
+	}
+	for key := range defaults {
+		if !namePattern.MatchString(key) {
+			return Namespace{}, fmt.Errorf("invalid settings key %q in namespace %s, use letters, digits, - and _", key, name)
+		}
+	}
+
+	data, err := json.Marshal(defaults)
+	if err != nil {
+		return Namespace{}, fmt.Errorf("defaults of settings namespace %s: %w", name, err)
+	}
+	normalized := map[string]interface{}{}
+	if err = json.Unmarshal(data, &normalized); err != nil {
+		return Namespace{}, fmt.Errorf("defaults of settings namespace %s: %w", name, err)
+	}
+	return Namespace{Name: name, Defaults: normalized}, nil
+}
+
+// Validate checks that the values are registered keys of the namespace of the type of their default. Null values
+// reset the keys to their defaults.
+func (n Namespace) Validate(values map[string]interface{}) error {
+	var problems []string
+	for key, value := range values {
+		defaultValue, ok := n.Defaults[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is not a setting", key))
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if expected := kindOf(defaultValue); expected != "null" && expected != kindOf(value) {
+			problems = append(problems, fmt.Sprintf("%s must be a %s", key, expected))
+			continue
+		}
+		if data, err := json.Marshal(value); err != nil || len(data) > MaxValueSize {
+			problems = append(problems, fmt.Sprintf("%s must be at most %d bytes", key, MaxValueSize))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("settings %s: %s", n.Name, strings.Join(problems, ", "))
+}
+
+// Resolve returns the defaults of the namespace overridden by the stored values. Stored values of keys that are no
+// longer registered, or whose type has changed, are ignored.
+func (n Namespace) Resolve(stored map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(n.Defaults))
+	for key, defaultValue := range n.Defaults {
+		values[key] = defaultValue
+		value, ok := stored[key]
+		if !ok || value == nil {
+			continue
+		}
+		value = normalize(value)
+		if expected := kindOf(defaultValue); expected == "null" || expected == kindOf(value) {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// kindOf returns the JSON type of a value decoded from JSON or BSON
+func kindOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64, float32, int, int32, int64:
+		return "number"
+	case []interface{}, primitive.A:
+		return "array"
+	case map[string]interface{}, primitive.M, primitive.D:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// normalize converts the documents and arrays decoded from BSON to their JSON counterparts
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.D:
+		object := make(map[string]interface{}, len(v))
+		for _, element := range v {
+			object[element.Key] = normalize(element.Value)
+		}
+		return object
+	case primitive.M:
+		return normalize(map[string]interface{}(v))
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			object[key] = normalize(element)
+		}
+		return object
+	case primitive.A:
+		return normalize([]interface{}(v))
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, element := range v {
+			array[i] = normalize(element)
+		}
+		return array
+	default:
+		return value
+	}
+}
@@ -0,0 +1,32 @@
+package user
+
+import "fmt"
+
+const (
+	// MaxAvatarUploadSize is the maximum size of an uploaded avatar image in bytes
+	MaxAvatarUploadSize = 5 * 1024 * 1024
+	// MaxAvatarDimension is the maximum width and height of an uploaded avatar image in pixels
+	MaxAvatarDimension = 4096
+	// AvatarContentType is the content type of the stored avatar sizes
+	AvatarContentType = "image/png"
+)
+
+// AvatarSizes are the sizes an uploaded avatar is resized to, largest first
+var AvatarSizes = []int{256, 128, 64}
+
+// AvatarKey returns the blob key of a size of an avatar
+func AvatarKey(avatarID string, size int) string {
+	return fmt.Sprintf("avatars/%s/%d.png", avatarID, size)
+}
+
+// AvatarSize returns the smallest stored size that is at least the requested one, the largest size if the request
+// is zero or larger than every stored size
+func AvatarSize(requested int) int {
+	size := AvatarSizes[0]
+	for _, candidate := range AvatarSizes {
+		if candidate >= requested && requested > 0 {
+			size = candidate
+		}
+	}
+	return size
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"errors"
+	"time"
 
 	"github.com/LydiaTrack/ground/internal/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,6 +16,8 @@ type CreateUserCommand struct {
 	Properties  map[string]interface{} `json:"properties"`
 	Avatar      string                 `json:"avatar,omitempty"`
 	OAuthInfo   *OAuthInfo             `json:"OAuthInfo,omitempty"`
+	// RoleAssignments are the roles the user is created with, set by the services such as the invitations
+	RoleAssignments []RoleAssignment `json:"-"`
 }
 
 type UpdateUserCommand struct {
@@ -26,6 +29,9 @@ type UpdateUserCommand struct {
 	Properties               map[string]interface{} `json:"properties,omitempty"`
 	LastSeenChangelogVersion string                 `json:"lastSeenChangelogVersion,omitempty"`
 	OAuthProviders           map[string]OAuthInfo   `json:"oauthProviders,omitempty"`
+	// ExpectedVersion guards the update against concurrent ones, the update is refused if the user has another
+	// version. It is not guarded when nil.
+	ExpectedVersion *int `json:"-"`
 }
 
 func (cmd UpdateUserCommand) Validate() error {
@@ -45,16 +51,50 @@ func (cmd UpdateUserCommand) Validate() error {
 		}
 	}
 
+	if cmd.ContactInfo != nil && cmd.ContactInfo.PhoneNumber != nil {
+		if err := cmd.ContactInfo.PhoneNumber.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// PatchableFields are the fields of a user a patch can change
+var PatchableFields = []string{"username", "avatar", "personInfo", "contactInfo", "properties", "lastSeenChangelogVersion"}
+
+// PatchUserCommand applies a JSON merge patch or a JSON patch, told apart by the content type, to a user
+type PatchUserCommand struct {
+	ContentType string
+	Patch       []byte
+	// ExpectedVersion guards the patch against concurrent updates like UpdateUserCommand.ExpectedVersion
+	ExpectedVersion *int
+}
+
 type DeleteUserCommand struct {
 	ID primitive.ObjectID `json:"id" bson:"_id"`
 }
 
+// DeleteSelfCommand requests the deletion of the own account. The password re-authenticates the user,
+// users without a password re-authenticate with a fresh token of their OAuth provider.
+type DeleteSelfCommand struct {
+	Password   string `json:"password"`
+	OAuthToken string `json:"oauthToken,omitempty"`
+}
+
 type AddRoleToUserCommand struct {
-	UserID primitive.ObjectID `json:"userID"`
-	RoleID primitive.ObjectID `json:"roleID"`
+	UserID    primitive.ObjectID `json:"userID"`
+	RoleID    primitive.ObjectID `json:"roleID"`
+	ExpiresAt *time.Time         `json:"expiresAt,omitempty"`
+	Reason    string             `json:"reason,omitempty"`
+}
+
+func (cmd AddRoleToUserCommand) Validate() error {
+	if cmd.ExpiresAt != nil && !cmd.ExpiresAt.After(time.Now()) {
+		return errors.New("expiry date must be in the future")
+	}
+
+	return nil
 }
 
 type RemoveRoleFromUserCommand struct {
@@ -70,3 +110,26 @@ type UpdatePasswordCommand struct {
 type ResetPasswordCommand struct {
 	NewPassword string `json:"newPassword"`
 }
+
+// ChangeStatusCommand changes the state of an account. Suspensions require an end date.
+type ChangeStatusCommand struct {
+	State  AccountState `json:"state"`
+	Until  *time.Time   `json:"until,omitempty"`
+	Reason string       `json:"reason,omitempty"`
+}
+
+func (cmd ChangeStatusCommand) Validate() error {
+	if !cmd.State.IsValid() {
+		return errors.New("state is invalid")
+	}
+
+	if cmd.State == AccountStateSuspended {
+		if cmd.Until == nil || !cmd.Until.After(time.Now()) {
+			return errors.New("suspension end date must be in the future")
+		}
+	} else if cmd.Until != nil {
+		return errors.New("only suspensions have an end date")
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultExportFields are the fields a bulk export writes when no field is selected
+var DefaultExportFields = []string{"id", "username", "email", "firstName", "lastName", "createdDate"}
+
+// exportFields extract the fields a bulk export can select from a user
+var exportFields = map[string]func(Model) interface{}{
+	"id":       func(u Model) interface{} { return u.ID.Hex() },
+	"username": func(u Model) interface{} { return u.Username },
+	"email":    func(u Model) interface{} { return u.ContactInfo.Email },
+	"firstName": func(u Model) interface{} {
+		if u.PersonInfo == nil {
+			return ""
+		}
+		return u.PersonInfo.FirstName
+	},
+	"lastName": func(u Model) interface{} {
+		if u.PersonInfo == nil {
+			return ""
+		}
+		return u.PersonInfo.LastName
+	},
+	"createdDate": func(u Model) interface{} { return u.CreatedDate },
+	"roleIds": func(u Model) interface{} {
+		if u.RoleIDs == nil {
+			return []primitive.ObjectID{}
+		}
+		return *u.RoleIDs
+	},
+	"status":     func(u Model) interface{} { return u.GetAccountState(time.Now()) },
+	"properties": func(u Model) interface{} { return u.Properties },
+}
+
+// ValidateExportFields checks that every field can be exported
+func ValidateExportFields(fields []string) error {
+	for _, field := range fields {
+		if _, ok := exportFields[field]; !ok {
+			return fmt.Errorf("field %q cannot be exported", field)
+		}
+	}
+	return nil
+}
+
+// ExportValues returns the selected fields of the user by name
+func (u Model) ExportValues(fields []string) map[string]interface{} {
+	values := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		values[field] = exportFields[field](u)
+	}
+	return values
+}
+
+// ExportStrings returns the selected fields of the user in order, formatted for CSV.
+// Times are written in RFC 3339, lists and objects as JSON.
+func (u Model) ExportStrings(fields []string) []string {
+	values := make([]string, len(fields))
+	for i, field := range fields {
+		switch value := exportFields[field](u).(type) {
+		case string:
+			values[i] = value
+		case time.Time:
+			values[i] = value.Format(time.RFC3339)
+		case AccountState:
+			values[i] = string(value)
+		default:
+			data, err := json.Marshal(value)
+			if err != nil {
+				values[i] = fmt.Sprint(value)
+				continue
+			}
+			values[i] = string(data)
+		}
+	}
+	return values
+}
@@ -0,0 +1,58 @@
+package user
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IdentityField is a field identifying a user whose changes are recorded
+type IdentityField string
+
+const (
+	IdentityFieldUsername IdentityField = "USERNAME"
+	IdentityFieldEmail    IdentityField = "EMAIL"
+)
+
+// IdentityChange records a change of the username or the email of a user. A released username stays reserved for
+// the user until ReservedUntil, so others cannot claim it to impersonate them.
+type IdentityChange struct {
+	ID            primitive.ObjectID  `json:"id" bson:"_id"`
+	UserID        primitive.ObjectID  `json:"userId" bson:"userId"`
+	Field         IdentityField       `json:"field" bson:"field"`
+	OldValue      string              `json:"oldValue" bson:"oldValue"`
+	NewValue      string              `json:"newValue" bson:"newValue"`
+	ChangedBy     *primitive.ObjectID `json:"changedBy,omitempty" bson:"changedBy,omitempty"`
+	ChangedAt     time.Time           `json:"changedAt" bson:"changedAt"`
+	ReservedUntil *time.Time          `json:"reservedUntil,omitempty" bson:"reservedUntil,omitempty"`
+	Version       int                 `json:"version" bson:"version"`
+}
+
+// IdentityChanges returns the changes of the identity fields between two states of a user. Released usernames are
+// reserved for the reservation period, they are not reserved when it is zero.
+func IdentityChanges(before, after Model, changedBy *primitive.ObjectID, now time.Time, reservation time.Duration) []IdentityChange {
+	var changes []IdentityChange
+	record := func(field IdentityField, oldValue, newValue string, reserve bool) {
+		if oldValue == newValue || oldValue == "" {
+			return
+		}
+		change := IdentityChange{
+			ID:        primitive.NewObjectID(),
+			UserID:    before.ID,
+			Field:     field,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			ChangedBy: changedBy,
+			ChangedAt: now,
+			Version:   1,
+		}
+		if reserve && reservation > 0 {
+			reservedUntil := now.Add(reservation)
+			change.ReservedUntil = &reservedUntil
+		}
+		changes = append(changes, change)
+	}
+	record(IdentityFieldUsername, before.Username, after.Username, true)
+	record(IdentityFieldEmail, before.ContactInfo.Email, after.ContactInfo.Email, false)
+	return changes
+}
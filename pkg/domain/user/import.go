@@ -0,0 +1,229 @@
+package user
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MaxImportUploadSize is the maximum size of an uploaded import in bytes
+	MaxImportUploadSize = 10 * 1024 * 1024
+	// MaxImportRows is the maximum number of rows of an import
+	MaxImportRows = 10000
+)
+
+// ErrTooManyImportRows is returned for imports with more than MaxImportRows rows
+var ErrTooManyImportRows = fmt.Errorf("the import exceeds the maximum of %d rows", MaxImportRows)
+
+// ImportFormat is the encoding of a bulk import or export
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "csv"
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+// IsValid checks if the format is supported
+func (f ImportFormat) IsValid() bool {
+	return f == ImportFormatCSV || f == ImportFormatNDJSON
+}
+
+// ImportMatchField is the field an imported user is matched to an existing user by
+type ImportMatchField string
+
+const (
+	ImportMatchByEmail    ImportMatchField = "email"
+	ImportMatchByUsername ImportMatchField = "username"
+)
+
+// ImportAction is what an import did, or would do in a dry run, with a row
+type ImportAction string
+
+const (
+	ImportActionCreated ImportAction = "CREATED"
+	ImportActionUpdated ImportAction = "UPDATED"
+	ImportActionInvited ImportAction = "INVITED"
+	ImportActionFailed  ImportAction = "FAILED"
+)
+
+// ImportRecord is a user in a bulk import. Roles are referenced by name.
+// In CSV the roles are separated by "|" and the properties are a JSON object.
+type ImportRecord struct {
+	Username   string                 `json:"username"`
+	Email      string                 `json:"email"`
+	Password   string                 `json:"password,omitempty"`
+	FirstName  string                 `json:"firstName,omitempty"`
+	LastName   string                 `json:"lastName,omitempty"`
+	Roles      []string               `json:"roles,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// ImportRow is a decoded row of an import, Error is set if the row could not be decoded
+type ImportRow struct {
+	Number int
+	Record ImportRecord
+	Error  error
+}
+
+// ImportOptions configures a bulk import
+type ImportOptions struct {
+	// DryRun validates the rows and reports what would be done without writing anything
+	DryRun bool
+	// MatchBy is the field existing users are matched by, defaults to email
+	MatchBy ImportMatchField
+	// Invite sends an invitation to the rows without a password instead of creating the user
+	Invite bool
+}
+
+// ImportRowResult is the outcome of a row of an import
+type ImportRowResult struct {
+	Row      int                 `json:"row"`
+	Username string              `json:"username,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Action   ImportAction        `json:"action"`
+	UserID   *primitive.ObjectID `json:"userId,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// ImportReport is the per row outcome of a bulk import
+type ImportReport struct {
+	DryRun  bool              `json:"dryRun"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Invited int               `json:"invited"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// Add records the result of a row
+func (r *ImportReport) Add(result ImportRowResult) {
+	switch result.Action {
+	case ImportActionCreated:
+		r.Created++
+	case ImportActionUpdated:
+		r.Updated++
+	case ImportActionInvited:
+		r.Invited++
+	case ImportActionFailed:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, result)
+}
+
+// DecodeImportRows decodes the rows of an import. Rows that cannot be decoded are returned with an error,
+// only an unreadable input or more than MaxImportRows rows fail the whole import.
+func DecodeImportRows(format ImportFormat, reader io.Reader) ([]ImportRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return decodeCSVRows(reader)
+	case ImportFormatNDJSON:
+		return decodeNDJSONRows(reader)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// decodeCSVRows decodes a CSV with a header row naming the columns
+func decodeCSVRows(reader io.Reader) ([]ImportRow, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.TrimSpace(column)] = i
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, errors.New("username column is required")
+	}
+
+	var rows []ImportRow
+	for number := 1; ; number++ {
+		values, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if number > MaxImportRows {
+			return nil, ErrTooManyImportRows
+		}
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			rows = append(rows, ImportRow{Number: number, Error: err})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(values) {
+				return ""
+			}
+			return strings.TrimSpace(values[i])
+		}
+		record := ImportRecord{
+			Username:  value("username"),
+			Email:     value("email"),
+			Password:  value("password"),
+			FirstName: value("firstName"),
+			LastName:  value("lastName"),
+		}
+		if roles := value("roles"); roles != "" {
+			for _, role := range strings.Split(roles, "|") {
+				if role = strings.TrimSpace(role); role != "" {
+					record.Roles = append(record.Roles, role)
+				}
+			}
+		}
+		if properties := value("properties"); properties != "" {
+			if err := json.Unmarshal([]byte(properties), &record.Properties); err != nil {
+				rows = append(rows, ImportRow{Number: number, Record: record, Error: errors.New("properties must be a JSON object")})
+				continue
+			}
+		}
+		rows = append(rows, ImportRow{Number: number, Record: record})
+	}
+	return rows, nil
+}
+
+// decodeNDJSONRows decodes one JSON object per line, blank lines are skipped
+func decodeNDJSONRows(reader io.Reader) ([]ImportRow, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []ImportRow
+	number := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		number++
+		if number > MaxImportRows {
+			return nil, ErrTooManyImportRows
+		}
+
+		var record ImportRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			rows = append(rows, ImportRow{Number: number, Error: err})
+			continue
+		}
+		rows = append(rows, ImportRow{Number: number, Record: record})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
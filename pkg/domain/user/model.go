@@ -2,6 +2,7 @@ package user
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/LydiaTrack/ground/internal/utils"
@@ -15,14 +16,68 @@ type Model struct {
 	Username                 string                 `json:"username" bson:"username"`
 	Password                 string                 `json:"-" bson:"password"`
 	Avatar                   string                 `json:"avatar,omitempty" bson:"avatar,omitempty"`
+	AvatarID                 string                 `json:"-" bson:"avatarId,omitempty"`
 	PersonInfo               *PersonInfo            `json:"personInfo" bson:"personInfo"`
 	ContactInfo              ContactInfo            `json:"contactInfo" bson:"contactInfo"`
+	PhoneVerified            bool                   `json:"phoneVerified" bson:"phoneVerified,omitempty"`
 	CreatedDate              time.Time              `json:"createdDate" bson:"createdDate"`
 	Version                  int                    `json:"version" bson:"version"`
 	LastSeenChangelogVersion string                 `json:"lastSeenChangelogVersion" bson:"lastSeenChangelogVersion"`
 	RoleIDs                  *[]primitive.ObjectID  `json:"roleIDs" bson:"roleIds"`
+	RoleAssignments          []RoleAssignment       `json:"roleAssignments,omitempty" bson:"roleAssignments,omitempty"`
 	Properties               map[string]interface{} `json:"properties" bson:"properties"`
 	OAuthInfo                *OAuthInfo             `json:"OAuthInfo,omitempty" bson:"OAuthInfo,omitempty"`
+	Status                   *AccountStatus         `json:"status,omitempty" bson:"status,omitempty"`
+	DeletedAt                *time.Time             `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	DeletedBy                *primitive.ObjectID    `json:"deletedBy,omitempty" bson:"deletedBy,omitempty"`
+	DeletionScheduledAt      *time.Time             `json:"deletionScheduledAt,omitempty" bson:"deletionScheduledAt,omitempty"`
+}
+
+// PublicProfile is the user as the other users see it, without the contact details, roles and private properties
+type PublicProfile struct {
+	ID         primitive.ObjectID     `json:"id"`
+	Username   string                 `json:"username"`
+	Avatar     string                 `json:"avatar,omitempty"`
+	FirstName  string                 `json:"firstName,omitempty"`
+	LastName   string                 `json:"lastName,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// RoleAssignment records who granted a role to a user, when, why and until when it is valid
+type RoleAssignment struct {
+	RoleID    primitive.ObjectID  `json:"roleId" bson:"roleId"`
+	GrantedBy *primitive.ObjectID `json:"grantedBy,omitempty" bson:"grantedBy,omitempty"`
+	GrantedAt time.Time           `json:"grantedAt" bson:"grantedAt"`
+	ExpiresAt *time.Time          `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	Reason    string              `json:"reason,omitempty" bson:"reason,omitempty"`
+}
+
+// IsExpired checks if the assignment has an expiry date that is not after now
+func (a RoleAssignment) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !a.ExpiresAt.After(now)
+}
+
+// ActiveRoleIDs returns the role IDs of the user without the ones whose assignment has expired.
+// Roles without an assignment record are permanent.
+func (u Model) ActiveRoleIDs(now time.Time) []primitive.ObjectID {
+	if u.RoleIDs == nil {
+		return []primitive.ObjectID{}
+	}
+
+	expired := make(map[primitive.ObjectID]bool)
+	for _, assignment := range u.RoleAssignments {
+		if assignment.IsExpired(now) {
+			expired[assignment.RoleID] = true
+		}
+	}
+
+	activeRoleIDs := []primitive.ObjectID{}
+	for _, roleID := range *u.RoleIDs {
+		if !expired[roleID] {
+			activeRoleIDs = append(activeRoleIDs, roleID)
+		}
+	}
+	return activeRoleIDs
 }
 
 // StatsDocument represents a flexible statistics document for a user
@@ -209,6 +264,22 @@ func WithRoleIDs(roleIDs *[]primitive.ObjectID) Option {
 	}
 }
 
+// WithRoleAssignments grants the roles of the assignments to the user
+func WithRoleAssignments(assignments []RoleAssignment) Option {
+	return func(u *Model) error {
+		if len(assignments) == 0 {
+			return nil
+		}
+		roleIDs := make([]primitive.ObjectID, 0, len(assignments))
+		for _, assignment := range assignments {
+			roleIDs = append(roleIDs, assignment.RoleID)
+		}
+		u.RoleIDs = &roleIDs
+		u.RoleAssignments = assignments
+		return nil
+	}
+}
+
 func WithOAuthInfo(oauthInfo *OAuthInfo) Option {
 	return func(u *Model) error {
 		u.OAuthInfo = oauthInfo
@@ -237,6 +308,12 @@ func (u Model) Validate() error {
 		}
 	}
 
+	if u.ContactInfo.PhoneNumber != nil {
+		if err := u.ContactInfo.PhoneNumber.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -245,6 +322,18 @@ type ContactInfo struct {
 	PhoneNumber *PhoneNumber `json:"phoneNumber,omitempty"`
 }
 
+// PhoneE164 returns the phone number in the E.164 format, empty if there is no valid phone number
+func (c ContactInfo) PhoneE164() string {
+	if c.PhoneNumber == nil {
+		return ""
+	}
+	phone, err := c.PhoneNumber.E164()
+	if err != nil {
+		return ""
+	}
+	return phone
+}
+
 type PersonInfo struct {
 	FirstName string             `json:"firstName"`
 	LastName  string             `json:"lastName"`
@@ -283,12 +372,52 @@ func (p PhoneNumber) Validate() error {
 		return errors.New("country code is required")
 	}
 
-	return nil
+	_, err := p.E164()
+	return err
+}
+
+// phoneSeparators are the characters allowed between the digits of the parts of a phone number
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// E164 returns the phone number in the E.164 format, e.g. +905321234567. The country code may be given with a
+// leading + or 00 and the trunk prefix 0 of the area code is dropped.
+func (p PhoneNumber) E164() (string, error) {
+	countryCode := phoneSeparators.Replace(p.CountryCode)
+	countryCode = strings.TrimPrefix(countryCode, "+")
+	if strings.HasPrefix(countryCode, "00") {
+		countryCode = countryCode[2:]
+	}
+	if !isDigits(countryCode) || len(countryCode) > 3 || countryCode[0] == '0' {
+		return "", errors.New("country code must be 1 to 3 digits")
+	}
+
+	national := strings.TrimLeft(phoneSeparators.Replace(p.AreaCode), "0") + phoneSeparators.Replace(p.Number)
+	if !isDigits(national) {
+		return "", errors.New("area code and number must be digits")
+	}
+	if len(national) < 4 || len(countryCode)+len(national) > 15 {
+		return "", errors.New("phone number must have at most 15 digits with the country code")
+	}
+	return "+" + countryCode + national, nil
+}
+
+// isDigits checks if the string is a non-empty string of ASCII digits
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
 }
 
 // OAuthInfo represents OAuth provider information for a user
 type OAuthInfo struct {
 	ProviderID     string    `json:"providerId" bson:"providerId"`
+	Provider       string    `json:"provider,omitempty" bson:"provider,omitempty"`
 	Email          string    `json:"email" bson:"email"`
 	AccessToken    string    `json:"-" bson:"accessToken"`
 	RefreshToken   string    `json:"-" bson:"refreshToken"`
@@ -0,0 +1,138 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/jsonschema"
+	"github.com/LydiaTrack/ground/pkg/mongodb/query"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// QuerySchema declares the filters and sorts of the user listing, e.g.
+// role[in]=<id>,<id>&status=BANNED&createdDate[gte]=2024-01-01&emailDomain=example.com&properties.plan=pro&sort=-createdDate
+var QuerySchema = query.Schema{
+	Fields: map[string]query.Field{
+		"id":        {Path: "_id", Type: query.ObjectID, Operators: []query.Operator{query.Eq, query.In}, Sortable: true},
+		"username":  {Path: "username", Type: query.String, Operators: []query.Operator{query.Eq, query.In}, Sortable: true},
+		"email":     {Path: "contactInfo.email", Type: query.String, Operators: []query.Operator{query.Eq, query.In}, Sortable: true},
+		"firstName": {Path: "personInfo.firstname", Type: query.String, Operators: []query.Operator{query.Eq}, Sortable: true},
+		"lastName":  {Path: "personInfo.lastname", Type: query.String, Operators: []query.Operator{query.Eq}, Sortable: true},
+		"role": {Path: "roleIds", Type: query.ObjectID,
+			Operators: []query.Operator{query.Eq, query.Ne, query.In, query.Nin}},
+		"status": {Path: "status.state", Type: query.String,
+			Operators: []query.Operator{query.Eq, query.In}, Build: buildStatusFilter},
+		"createdDate": {Path: "createdDate", Type: query.Time,
+			Operators: []query.Operator{query.Gt, query.Gte, query.Lt, query.Lte}, Sortable: true},
+		"oauthProvider": {Path: "OAuthInfo.provider", Type: query.String,
+			Operators: []query.Operator{query.Eq, query.In, query.Exists}, Build: buildOAuthProviderFilter},
+		"emailDomain": {Path: "contactInfo.email", Type: query.String,
+			Operators: []query.Operator{query.Eq, query.In}, Build: buildEmailDomainFilter},
+	},
+	Prefixes: map[string]query.Field{
+		"properties.": {Path: "properties.", Type: query.String,
+			Operators: []query.Operator{query.Eq, query.Ne, query.In, query.Exists}, Sortable: true},
+	},
+	Reserved: []string{"page", "limit", "search", "cursor", "count"},
+}
+
+// QuerySchemaWithProperties returns QuerySchema parsing the values of the properties filters as the types the JSON
+// Schema of the properties declares for their keys, e.g. properties.seats=10 matches a number for an integer key.
+// Keys without a single declared type are parsed as strings.
+func QuerySchemaWithProperties(properties *jsonschema.Schema) query.Schema {
+	schema := QuerySchema
+	schema.Prefixes = make(map[string]query.Field, len(QuerySchema.Prefixes))
+	for prefix, field := range QuerySchema.Prefixes {
+		schema.Prefixes[prefix] = field
+	}
+	field := schema.Prefixes["properties."]
+	field.KeyType = func(key string) (query.Type, bool) {
+		return propertyType(properties.PropertyTypes(key))
+	}
+	schema.Prefixes["properties."] = field
+	return schema
+}
+
+// propertyType maps the JSON Schema types of a property to the type its values are parsed as, null is ignored
+func propertyType(types []string) (query.Type, bool) {
+	var declared []string
+	for _, t := range types {
+		if t != "null" {
+			declared = append(declared, t)
+		}
+	}
+	if len(declared) != 1 {
+		return query.String, false
+	}
+	switch declared[0] {
+	case "integer", "number":
+		return query.Number, true
+	case "boolean":
+		return query.Bool, true
+	case "string":
+		return query.String, true
+	default:
+		return query.String, false
+	}
+}
+
+// buildOAuthProviderFilter matches the provider of the OAuth account. Accounts linked before the provider was stored
+// have none until their next OAuth login, so exists matches the OAuth account itself.
+func buildOAuthProviderFilter(operator query.Operator, values []interface{}) (bson.M, error) {
+	switch operator {
+	case query.Exists:
+		return bson.M{"OAuthInfo": bson.M{"$exists": values[0]}}, nil
+	case query.In:
+		return bson.M{"OAuthInfo.provider": bson.M{"$in": values}}, nil
+	default:
+		return bson.M{"OAuthInfo.provider": values[0]}, nil
+	}
+}
+
+// buildStatusFilter matches the effective account state, users without a status and expired suspensions are active
+func buildStatusFilter(_ query.Operator, values []interface{}) (bson.M, error) {
+	now := time.Now()
+	var conditions bson.A
+	for _, value := range values {
+		state := AccountState(strings.ToUpper(value.(string)))
+		switch state {
+		case AccountStateActive:
+			conditions = append(conditions,
+				bson.M{"status": bson.M{"$exists": false}},
+				bson.M{"status.state": AccountStateActive},
+				bson.M{"status.state": AccountStateSuspended, "status.until": bson.M{"$lte": now}})
+		case AccountStateSuspended:
+			conditions = append(conditions, bson.M{"status.state": AccountStateSuspended, "$or": bson.A{
+				bson.M{"status.until": bson.M{"$exists": false}},
+				bson.M{"status.until": bson.M{"$gt": now}},
+			}})
+		case AccountStateBanned, AccountStateDeactivated:
+			conditions = append(conditions, bson.M{"status.state": state})
+		default:
+			return nil, fmt.Errorf("unknown state %q", value)
+		}
+	}
+	return bson.M{"$or": conditions}, nil
+}
+
+// emailDomainPattern accepts host names only, so a domain cannot carry a regular expression
+var emailDomainPattern = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+$`)
+
+// buildEmailDomainFilter matches the users whose email is in one of the domains
+func buildEmailDomainFilter(_ query.Operator, values []interface{}) (bson.M, error) {
+	var conditions bson.A
+	for _, value := range values {
+		domain := strings.TrimPrefix(value.(string), "@")
+		if !emailDomainPattern.MatchString(domain) {
+			return nil, errors.New("invalid domain")
+		}
+		conditions = append(conditions, bson.M{"contactInfo.email": bson.M{
+			"$regex":   "@" + regexp.QuoteMeta(domain) + "$",
+			"$options": "i",
+		}})
+	}
+	return bson.M{"$or": conditions}, nil
+}
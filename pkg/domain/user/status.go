@@ -0,0 +1,67 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AccountState is the state of a user account
+type AccountState string
+
+const (
+	AccountStateActive      AccountState = "ACTIVE"
+	AccountStateSuspended   AccountState = "SUSPENDED"
+	AccountStateBanned      AccountState = "BANNED"
+	AccountStateDeactivated AccountState = "DEACTIVATED"
+)
+
+// AccountStatus records the state of an account, who changed it, when and why.
+// A suspension ends by itself at Until.
+type AccountStatus struct {
+	State     AccountState        `json:"state" bson:"state"`
+	Until     *time.Time          `json:"until,omitempty" bson:"until,omitempty"`
+	Reason    string              `json:"reason,omitempty" bson:"reason,omitempty"`
+	ChangedBy *primitive.ObjectID `json:"changedBy,omitempty" bson:"changedBy,omitempty"`
+	ChangedAt time.Time           `json:"changedAt" bson:"changedAt"`
+}
+
+// IsValid checks if the state is a known state
+func (s AccountState) IsValid() bool {
+	switch s {
+	case AccountStateActive, AccountStateSuspended, AccountStateBanned, AccountStateDeactivated:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetAccountState returns the effective state of the account, users without a status are active
+func (u Model) GetAccountState(now time.Time) AccountState {
+	if u.Status == nil {
+		return AccountStateActive
+	}
+	if u.Status.State == AccountStateSuspended && u.Status.Until != nil && !u.Status.Until.After(now) {
+		return AccountStateActive
+	}
+	return u.Status.State
+}
+
+// CheckAccountStatus fails with the error of the account state unless the account is active
+func (u Model) CheckAccountStatus(now time.Time) error {
+	switch u.GetAccountState(now) {
+	case AccountStateSuspended:
+		if u.Status.Until != nil {
+			return fmt.Errorf("%w until %s", constants.ErrorAccountSuspended, u.Status.Until.Format(time.RFC3339))
+		}
+		return constants.ErrorAccountSuspended
+	case AccountStateBanned:
+		return constants.ErrorAccountBanned
+	case AccountStateDeactivated:
+		return constants.ErrorAccountDeactivated
+	default:
+		return nil
+	}
+}
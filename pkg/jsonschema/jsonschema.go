@@ -0,0 +1,481 @@
+// Package jsonschema validates JSON values against a subset of JSON Schema (draft 2020-12).
+//
+// The supported keywords are type, enum, const, properties, required, additionalProperties, minProperties,
+// maxProperties, items, minItems, maxItems, uniqueItems, minLength, maxLength, pattern, format (email, date,
+// date-time, uri), minimum, maximum, exclusiveMinimum and exclusiveMaximum. The annotations title, description,
+// default, examples, $schema, $id and $comment are accepted and ignored, any other keyword is refused when compiling
+// so schemas never silently validate less than their authors expect.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidSchema is returned for schemas that cannot be compiled
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	types                []string
+	enum                 []interface{}
+	constant             interface{}
+	hasConstant          bool
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	noAdditional         bool
+	minProperties        *int
+	maxProperties        *int
+	items                *Schema
+	minItems             *int
+	maxItems             *int
+	uniqueItems          bool
+	minLength            *int
+	maxLength            *int
+	pattern              *regexp.Regexp
+	format               string
+	minimum              *float64
+	maximum              *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+}
+
+// FieldError is a violation of the schema by the value at a path, e.g. address.city or tags[2]
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every violation of the schema by a value
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		if fieldError.Path == "" {
+			messages[i] = fieldError.Message
+		} else {
+			messages[i] = fieldError.Path + ": " + fieldError.Message
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+var annotations = map[string]bool{
+	"title": true, "description": true, "default": true, "examples": true, "$schema": true, "$id": true, "$comment": true,
+}
+
+var simpleTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true, "number": true, "integer": true, "string": true,
+}
+
+var formats = map[string]bool{"email": true, "date": true, "date-time": true, "uri": true}
+
+// Compile compiles a schema from its JSON document
+func Compile(data []byte) (*Schema, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return compile(document, "")
+}
+
+func compile(document interface{}, path string) (*Schema, error) {
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, schemaError(path, "a schema must be an object")
+	}
+
+	schema := &Schema{}
+	for keyword, value := range object {
+		var err error
+		switch keyword {
+		case "type":
+			schema.types, err = compileTypes(value)
+		case "enum":
+			values, ok := value.([]interface{})
+			if !ok || len(values) == 0 {
+				err = errors.New("must be a non empty array")
+			}
+			schema.enum = values
+		case "const":
+			schema.constant, schema.hasConstant = value, true
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				err = errors.New("must be an object")
+				break
+			}
+			schema.properties = map[string]*Schema{}
+			for name, propertySchema := range properties {
+				if schema.properties[name], err = compile(propertySchema, joinPath(path, name)); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			schema.required, err = compileStrings(value)
+		case "additionalProperties":
+			if allowed, ok := value.(bool); ok {
+				schema.noAdditional = !allowed
+			} else if schema.additionalProperties, err = compile(value, joinPath(path, "*")); err != nil {
+				return nil, err
+			}
+		case "items":
+			if schema.items, err = compile(value, path+"[]"); err != nil {
+				return nil, err
+			}
+		case "uniqueItems":
+			unique, ok := value.(bool)
+			if !ok {
+				err = errors.New("must be a boolean")
+			}
+			schema.uniqueItems = unique
+		case "minProperties":
+			schema.minProperties, err = compileCount(value)
+		case "maxProperties":
+			schema.maxProperties, err = compileCount(value)
+		case "minItems":
+			schema.minItems, err = compileCount(value)
+		case "maxItems":
+			schema.maxItems, err = compileCount(value)
+		case "minLength":
+			schema.minLength, err = compileCount(value)
+		case "maxLength":
+			schema.maxLength, err = compileCount(value)
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				err = errors.New("must be a string")
+				break
+			}
+			schema.pattern, err = regexp.Compile(pattern)
+		case "format":
+			format, ok := value.(string)
+			if !ok || !formats[format] {
+				err = fmt.Errorf("unsupported format %v", value)
+			}
+			schema.format = format
+		case "minimum":
+			schema.minimum, err = compileNumber(value)
+		case "maximum":
+			schema.maximum, err = compileNumber(value)
+		case "exclusiveMinimum":
+			schema.exclusiveMinimum, err = compileNumber(value)
+		case "exclusiveMaximum":
+			schema.exclusiveMaximum, err = compileNumber(value)
+		default:
+			if !annotations[keyword] {
+				err = errors.New("unsupported keyword")
+			}
+		}
+		if err != nil {
+			return nil, schemaError(joinPath(path, keyword), err.Error())
+		}
+	}
+	return schema, nil
+}
+
+// PropertyTypes returns the types declared for a property of an object schema, or by additionalProperties for the
+// properties that are not listed. It is nil if no type is declared.
+func (s *Schema) PropertyTypes(name string) []string {
+	if s == nil {
+		return nil
+	}
+	if property, ok := s.properties[name]; ok {
+		return property.types
+	}
+	if s.additionalProperties != nil {
+		return s.additionalProperties.types
+	}
+	return nil
+}
+
+// Validate validates the value, decoded from JSON or BSON, and returns a *ValidationError listing every violation
+func (s *Schema) Validate(value interface{}) error {
+	var fieldErrors []FieldError
+	s.validate(normalize(value), "", &fieldErrors)
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: fieldErrors}
+}
+
+func (s *Schema) validate(value interface{}, path string, fieldErrors *[]FieldError) {
+	fail := func(format string, args ...interface{}) {
+		*fieldErrors = append(*fieldErrors, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.types) > 0 && !hasType(value, s.types) {
+		fail("must be of type %s", strings.Join(s.types, " or "))
+		return
+	}
+	if s.hasConstant && !reflect.DeepEqual(value, normalize(s.constant)) {
+		fail("must be %v", s.constant)
+	}
+	if s.enum != nil && !contains(s.enum, value) {
+		fail("must be one of %v", s.enum)
+	}
+
+	switch typed := value.(type) {
+	case string:
+		length := len([]rune(typed))
+		if s.minLength != nil && length < *s.minLength {
+			fail("must be at least %d characters long", *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			fail("must be at most %d characters long", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(typed) {
+			fail("must match %s", s.pattern.String())
+		}
+		if s.format != "" && !matchesFormat(s.format, typed) {
+			fail("must be a valid %s", s.format)
+		}
+	case float64:
+		if s.minimum != nil && typed < *s.minimum {
+			fail("must be at least %v", *s.minimum)
+		}
+		if s.maximum != nil && typed > *s.maximum {
+			fail("must be at most %v", *s.maximum)
+		}
+		if s.exclusiveMinimum != nil && typed <= *s.exclusiveMinimum {
+			fail("must be greater than %v", *s.exclusiveMinimum)
+		}
+		if s.exclusiveMaximum != nil && typed >= *s.exclusiveMaximum {
+			fail("must be less than %v", *s.exclusiveMaximum)
+		}
+	case []interface{}:
+		if s.minItems != nil && len(typed) < *s.minItems {
+			fail("must have at least %d items", *s.minItems)
+		}
+		if s.maxItems != nil && len(typed) > *s.maxItems {
+			fail("must have at most %d items", *s.maxItems)
+		}
+		if s.uniqueItems && !unique(typed) {
+			fail("must not have duplicate items")
+		}
+		if s.items != nil {
+			for i, item := range typed {
+				s.items.validate(item, fmt.Sprintf("%s[%d]", path, i), fieldErrors)
+			}
+		}
+	case map[string]interface{}:
+		if s.minProperties != nil && len(typed) < *s.minProperties {
+			fail("must have at least %d properties", *s.minProperties)
+		}
+		if s.maxProperties != nil && len(typed) > *s.maxProperties {
+			fail("must have at most %d properties", *s.maxProperties)
+		}
+		for _, name := range s.required {
+			if _, ok := typed[name]; !ok {
+				*fieldErrors = append(*fieldErrors, FieldError{Path: joinPath(path, name), Message: "is required"})
+			}
+		}
+		for _, name := range sortedKeys(typed) {
+			propertyPath := joinPath(path, name)
+			if propertySchema, ok := s.properties[name]; ok {
+				propertySchema.validate(typed[name], propertyPath, fieldErrors)
+			} else if s.noAdditional {
+				*fieldErrors = append(*fieldErrors, FieldError{Path: propertyPath, Message: "is not allowed"})
+			} else if s.additionalProperties != nil {
+				s.additionalProperties.validate(typed[name], propertyPath, fieldErrors)
+			}
+		}
+	}
+}
+
+// normalize converts the values decoded from BSON and the Go numeric types to their JSON equivalents
+func normalize(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, element := range typed {
+			result[key] = normalize(element)
+		}
+		return result
+	case primitive.M:
+		return normalize(map[string]interface{}(typed))
+	case primitive.D:
+		return normalize(typed.Map())
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, element := range typed {
+			result[i] = normalize(element)
+		}
+		return result
+	case primitive.A:
+		return normalize([]interface{}(typed))
+	case int:
+		return float64(typed)
+	case int32:
+		return float64(typed)
+	case int64:
+		return float64(typed)
+	case float32:
+		return float64(typed)
+	case primitive.DateTime:
+		return typed.Time().UTC().Format(time.RFC3339Nano)
+	case time.Time:
+		return typed.UTC().Format(time.RFC3339Nano)
+	default:
+		return value
+	}
+}
+
+func hasType(value interface{}, types []string) bool {
+	for _, expected := range types {
+		switch expected {
+		case "null":
+			if value == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if number, ok := value.(float64); ok && number == math.Trunc(number) {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesFormat(format, value string) bool {
+	switch format {
+	case "email":
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	case "date":
+		_, err := time.Parse(time.DateOnly, value)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "uri":
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.Scheme != ""
+	}
+	return true
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(normalize(candidate), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func unique(values []interface{}) bool {
+	for i := range values {
+		for j := i + 1; j < len(values); j++ {
+			if reflect.DeepEqual(values[i], values[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func compileTypes(value interface{}) ([]string, error) {
+	if name, ok := value.(string); ok {
+		value = []interface{}{name}
+	}
+	types, err := compileStrings(value)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range types {
+		if !simpleTypes[name] {
+			return nil, fmt.Errorf("unknown type %q", name)
+		}
+	}
+	return types, nil
+}
+
+func compileStrings(value interface{}) ([]string, error) {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("must be an array of strings")
+	}
+	result := make([]string, len(values))
+	for i, element := range values {
+		if result[i], ok = element.(string); !ok {
+			return nil, errors.New("must be an array of strings")
+		}
+	}
+	return result, nil
+}
+
+func compileCount(value interface{}) (*int, error) {
+	number, ok := value.(float64)
+	if !ok || number < 0 || number != math.Trunc(number) {
+		return nil, errors.New("must be a non negative integer")
+	}
+	count := int(number)
+	return &count, nil
+}
+
+func compileNumber(value interface{}) (*float64, error) {
+	number, ok := value.(float64)
+	if !ok {
+		return nil, errors.New("must be a number")
+	}
+	return &number, nil
+}
+
+func schemaError(path, message string) error {
+	if path == "" {
+		return fmt.Errorf("%w: %s", ErrInvalidSchema, message)
+	}
+	return fmt.Errorf("%w: %s: %s", ErrInvalidSchema, path, message)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const testSchema = `{
+	"type": "object",
+	"title": "Properties",
+	"required": ["plan"],
+	"additionalProperties": false,
+	"properties": {
+		"plan": {"enum": ["free", "pro"]},
+		"seats": {"type": "integer", "minimum": 1, "maximum": 100},
+		"website": {"type": "string", "format": "uri"},
+		"tags": {"type": "array", "items": {"type": "string", "maxLength": 5}, "uniqueItems": true},
+		"billing": {
+			"type": "object",
+			"required": ["email"],
+			"properties": {"email": {"type": "string", "format": "email"}}
+		}
+	}
+}`
+
+func TestCompileErrors(t *testing.T) {
+	documents := map[string]string{
+		"not json":        `{`,
+		"not an object":   `[]`,
+		"unknown keyword": `{"type": "object", "oneOf": []}`,
+		"unknown type":    `{"type": "map"}`,
+		"bad pattern":     `{"pattern": "("}`,
+		"negative count":  `{"minLength": -1}`,
+		"unknown format":  `{"format": "ipv4"}`,
+		"nested":          `{"properties": {"a": {"type": 1}}}`,
+	}
+	for name, document := range documents {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Compile([]byte(document)); !errors.Is(err, ErrInvalidSchema) {
+				t.Fatalf("expected an invalid schema error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := Compile([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("failed to compile the schema: %v", err)
+	}
+
+	valid := map[string]interface{}{
+		"plan":    "pro",
+		"seats":   float64(10),
+		"website": "https://example.com",
+		"tags":    []interface{}{"a", "b"},
+		"billing": map[string]interface{}{"email": "billing@example.com"},
+	}
+	if err := schema.Validate(valid); err != nil {
+		t.Fatalf("expected the value to be valid, got %v", err)
+	}
+
+	invalid := map[string]interface{}{
+		"seats":   float64(1.5),
+		"website": "example",
+		"tags":    []interface{}{"a", "a", "toolong"},
+		"billing": map[string]interface{}{},
+		"other":   true,
+	}
+	err = schema.Validate(invalid)
+	var validationError *ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	expected := "plan: is required; billing.email: is required; other: is not allowed; seats: must be of type integer; " +
+		"tags: must not have duplicate items; tags[2]: must be at most 5 characters long; website: must be a valid uri"
+	if err.Error() != expected {
+		t.Fatalf("unexpected message:\n got %s\nwant %s", err.Error(), expected)
+	}
+}
+
+func TestValidateStoredValues(t *testing.T) {
+	schema, err := Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"count": {"type": "integer", "exclusiveMinimum": 0},
+			"since": {"type": "string", "format": "date-time"},
+			"items": {"type": "array", "minItems": 1},
+			"nested": {"type": "object", "required": ["a"]}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("failed to compile the schema: %v", err)
+	}
+
+	stored := bson.M{
+		"count":  int32(3),
+		"since":  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"items":  bson.A{"x"},
+		"nested": bson.D{{Key: "a", Value: int64(1)}},
+	}
+	if err := schema.Validate(stored); err != nil {
+		t.Fatalf("expected the stored value to be valid, got %v", err)
+	}
+
+	if err := schema.Validate(bson.M{"count": int64(0)}); err == nil || err.Error() != "count: must be greater than 0" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 const (
 	UserIDKey            = "sub"
+	OrganizationIDKey    = "org"
 	AuthorizedKey        = "authorized"
 	ExpKey               = "exp"
 	JwtExpirationKey     = "JWT_EXPIRES_IN_MINUTES"
@@ -26,10 +27,17 @@ type TokenPair struct {
 	Token        string             `json:"token"`
 	RefreshToken string             `json:"refreshToken"`
 	UserID       primitive.ObjectID `json:"-"`
+	// OrganizationID is the active organization of the token, nil when no organization is active
+	OrganizationID *primitive.ObjectID `json:"-"`
 }
 
 // GenerateTokenPair generates a jwt and refresh token
 func GenerateTokenPair(userID primitive.ObjectID) (TokenPair, error) {
+	return GenerateOrganizationTokenPair(userID, nil)
+}
+
+// GenerateOrganizationTokenPair generates a jwt with the active organization claim and a refresh token
+func GenerateOrganizationTokenPair(userID primitive.ObjectID, organizationID *primitive.ObjectID) (TokenPair, error) {
 
 	tokenLifespanStr := os.Getenv(JwtExpirationKey)
 	if tokenLifespanStr == "" {
@@ -53,6 +61,9 @@ func GenerateTokenPair(userID primitive.ObjectID) (TokenPair, error) {
 	claims := jwt.MapClaims{}
 	claims[AuthorizedKey] = true
 	claims[UserIDKey] = userID.Hex()
+	if organizationID != nil {
+		claims[OrganizationIDKey] = organizationID.Hex()
+	}
 	claims[ExpKey] = time.Now().Add(time.Minute * time.Duration(tokenLifespan)).Unix()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -64,7 +75,7 @@ func GenerateTokenPair(userID primitive.ObjectID) (TokenPair, error) {
 	// Refresh token is a random string
 	refreshTokenStr := primitive.NewObjectID().Hex()
 
-	return TokenPair{Token: tokenStr, RefreshToken: refreshTokenStr, UserID: userID}, nil
+	return TokenPair{Token: tokenStr, RefreshToken: refreshTokenStr, UserID: userID, OrganizationID: organizationID}, nil
 }
 
 // IsTokenValid validates the token
@@ -113,15 +124,38 @@ func extractBearerTokenFromContext(c *gin.Context) string {
 
 // ExtractUserIDFromContext extracts the token id (userID) from the request
 func ExtractUserIDFromContext(c *gin.Context) (string, error) {
+	claims, err := extractClaimsFromContext(c)
+	if err != nil {
+		return "", err
+	}
+	if claims == nil {
+		return "", nil
+	}
+	uid := claims[UserIDKey].(string)
+	return uid, nil
+}
 
-	tokenString, err := ExtractTokenFromContext(c)
+// ExtractOrganizationIDFromContext extracts the active organization id from the request.
+// It returns an empty string if the token has no active organization.
+func ExtractOrganizationIDFromContext(c *gin.Context) (string, error) {
+	claims, err := extractClaimsFromContext(c)
 	if err != nil {
 		return "", err
 	}
+	organizationID, _ := claims[OrganizationIDKey].(string)
+	return organizationID, nil
+}
+
+// extractClaimsFromContext parses the token of the request and returns its claims
+func extractClaimsFromContext(c *gin.Context) (jwt.MapClaims, error) {
+	tokenString, err := ExtractTokenFromContext(c)
+	if err != nil {
+		return nil, err
+	}
 
 	jwtSecret := os.Getenv(JwtSecretKey)
 	if jwtSecret == "" {
-		return "", fmt.Errorf("JWT_SECRET environment variable not set")
+		return nil, fmt.Errorf("JWT_SECRET environment variable not set")
 	}
 
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
@@ -132,14 +166,13 @@ func ExtractUserIDFromContext(c *gin.Context) (string, error) {
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if ok && token.Valid {
-		uid := claims[UserIDKey].(string)
-		return uid, nil
+		return claims, nil
 	}
-	return "", nil
+	return nil, nil
 }
 
 // Logout logs out the user
@@ -1,9 +1,13 @@
 package jwt
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/gin-gonic/gin"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -222,6 +226,43 @@ func TestTokenPairIntegration(t *testing.T) {
 	}
 }
 
+func TestOrganizationClaim(t *testing.T) {
+	userID := primitive.NewObjectID()
+	organizationID := primitive.NewObjectID()
+
+	os.Setenv(JwtSecretKey, "test_secret_key")
+	os.Setenv(JwtExpirationKey, "2")
+	defer func() {
+		os.Unsetenv(JwtSecretKey)
+		os.Unsetenv(JwtExpirationKey)
+	}()
+
+	contextWithToken := func(token string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set(AuthorizationHeader, "Bearer "+token)
+		return c
+	}
+
+	tokenPair, err := GenerateOrganizationTokenPair(userID, &organizationID)
+	if err != nil {
+		t.Fatalf("Failed to generate token pair: %v", err)
+	}
+	extracted, err := ExtractOrganizationIDFromContext(contextWithToken(tokenPair.Token))
+	if err != nil || extracted != organizationID.Hex() {
+		t.Errorf("Expected organization %s, got %s (%v)", organizationID.Hex(), extracted, err)
+	}
+
+	tokenPair, err = GenerateTokenPair(userID)
+	if err != nil {
+		t.Fatalf("Failed to generate token pair: %v", err)
+	}
+	extracted, err = ExtractOrganizationIDFromContext(contextWithToken(tokenPair.Token))
+	if err != nil || extracted != "" {
+		t.Errorf("Expected no organization, got %s (%v)", extracted, err)
+	}
+}
+
 // Helper function to check if a string contains a substring
 func containsString(str, substr string) bool {
 	return len(str) >= len(substr) && (str == substr || len(substr) == 0 ||
@@ -0,0 +1,190 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/constants"
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirementMode decides how the permissions of a Requirement are combined
+type RequirementMode string
+
+const (
+	// RequirementAll requires every permission
+	RequirementAll RequirementMode = "all"
+	// RequirementAny requires at least one of the permissions
+	RequirementAny RequirementMode = "any"
+)
+
+// Requirement is the set of permissions a route requires
+type Requirement struct {
+	Mode        RequirementMode   `json:"mode"`
+	Permissions []auth.Permission `json:"permissions"`
+	// TenantScoped requirements are also satisfied by the permissions held in the active organization
+	TenantScoped bool `json:"tenantScoped,omitempty"`
+}
+
+// All creates a requirement that is satisfied when the user has every given permission
+func All(permissions ...auth.Permission) Requirement {
+	return Requirement{Mode: RequirementAll, Permissions: permissions}
+}
+
+// Any creates a requirement that is satisfied when the user has at least one of the given permissions
+func Any(permissions ...auth.Permission) Requirement {
+	return Requirement{Mode: RequirementAny, Permissions: permissions}
+}
+
+// InTenant marks the requirement of a route scoped to the active organization, such as the routes of the
+// organization itself. The permissions held in an organization do not apply to the other routes.
+func (r Requirement) InTenant() Requirement {
+	r.TenantScoped = true
+	return r
+}
+
+// IsSatisfiedBy checks if the given permissions satisfy the requirement.
+// A requirement without permissions only requires an authenticated user.
+func (r Requirement) IsSatisfiedBy(permissions []auth.Permission) bool {
+	if len(r.Permissions) == 0 {
+		return true
+	}
+	for _, permission := range r.Permissions {
+		hasPermission := auth.HasPermission(permissions, permission)
+		if r.Mode == RequirementAny && hasPermission {
+			return true
+		}
+		if r.Mode != RequirementAny && !hasPermission {
+			return false
+		}
+	}
+	return r.Mode != RequirementAny
+}
+
+// RoutePermission describes the requirement of a single route
+type RoutePermission struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Requirement
+}
+
+// permissionMiddleware is the singleton instance of PermissionMiddleware.
+var permissionMiddleware = &PermissionMiddleware{
+	routes: []RoutePermission{},
+}
+
+// PermissionMiddleware builds the auth context of the request and checks the route requirements
+type PermissionMiddleware struct {
+	authService auth.Service
+	userService auth.PermissionListProvider
+	routes      []RoutePermission
+}
+
+// InitPermissionMiddleware sets the services used to build the auth context of the requests
+func InitPermissionMiddleware(authService auth.Service, userService auth.PermissionListProvider) {
+	permissionMiddleware.authService = authService
+	permissionMiddleware.userService = userService
+}
+
+// RequirePermission rejects the request with 403 unless the user has every given permission
+func RequirePermission(permissions ...auth.Permission) gin.HandlerFunc {
+	return Require(All(permissions...))
+}
+
+// RequireAll rejects the request with 403 unless the user has every given permission
+func RequireAll(permissions ...auth.Permission) gin.HandlerFunc {
+	return Require(All(permissions...))
+}
+
+// RequireAny rejects the request with 403 unless the user has at least one of the given permissions
+func RequireAny(permissions ...auth.Permission) gin.HandlerFunc {
+	return Require(Any(permissions...))
+}
+
+// Require creates the auth context of the request once, stores it in the gin context and checks the requirement.
+// Handlers can access the stored context with auth.GetAuthContext.
+func Require(requirement Requirement) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authContext, err := auth.CreateAuthContext(c, permissionMiddleware.authService, permissionMiddleware.userService)
+		if err != nil {
+			if isAccountStatusError(err) {
+				utils.EvaluateError(err, c)
+				c.Abort()
+				return
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+		auth.SetAuthContext(c, authContext)
+
+		permissions := authContext.Permissions
+		if requirement.TenantScoped {
+			permissions = authContext.TenantScopedPermissions()
+		}
+		if !requirement.IsSatisfiedBy(permissions) {
+			logDenial(authContext, requirement, permissions)
+			c.JSON(http.StatusForbidden, gin.H{"error": constants.ErrorPermissionDenied.Error()})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// logDenial logs the first permission of a denied requirement the user does not have
+func logDenial(authContext auth.PermissionContext, requirement Requirement, permissions []auth.Permission) {
+	for _, permission := range requirement.Permissions {
+		if !auth.HasPermission(permissions, permission) {
+			auth.LogDenial(authContext, permission, auth.ExplainPermission(permissions, permission))
+			return
+		}
+	}
+}
+
+// Handle registers a route guarded by the requirement and records it in the route permission table
+func Handle(group *gin.RouterGroup, method string, relativePath string, requirement Requirement, handlers ...gin.HandlerFunc) {
+	group.Handle(method, relativePath, append([]gin.HandlerFunc{Require(requirement)}, handlers...)...)
+
+	path := group.BasePath()
+	if relativePath != "" {
+		path = joinPaths(path, relativePath)
+	}
+	permissionMiddleware.routes = append(permissionMiddleware.routes, RoutePermission{
+		Method:      method,
+		Path:        path,
+		Requirement: requirement,
+	})
+}
+
+// GetRoutePermissions returns the route permission table ordered by path and method
+func GetRoutePermissions() []RoutePermission {
+	routes := append([]RoutePermission{}, permissionMiddleware.routes...)
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+func joinPaths(basePath string, relativePath string) string {
+	if len(basePath) > 0 && basePath[len(basePath)-1] == '/' {
+		basePath = basePath[:len(basePath)-1]
+	}
+	if relativePath[0] != '/' {
+		relativePath = "/" + relativePath
+	}
+	return basePath + relativePath
+}
+
+// isAccountStatusError checks if the user was rejected because the account is not active
+func isAccountStatusError(err error) bool {
+	return errors.Is(err, constants.ErrorAccountSuspended) ||
+		errors.Is(err, constants.ErrorAccountBanned) ||
+		errors.Is(err, constants.ErrorAccountDeactivated)
+}
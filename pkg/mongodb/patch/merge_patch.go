@@ -0,0 +1,23 @@
+package patch
+
+// applyMergePatch applies an RFC 7396 merge patch: objects are merged recursively, null removes a member and any
+// other value replaces the target
+func applyMergePatch(target interface{}, mergePatch interface{}) interface{} {
+	patchObject, ok := mergePatch.(map[string]interface{})
+	if !ok {
+		return mergePatch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = applyMergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
@@ -0,0 +1,269 @@
+// Package patch applies RFC 7396 JSON merge patches and RFC 6902 JSON patches to models and translates the change
+// to the $set and $unset operations of a Mongo update. Only the declared fields can be changed.
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// MergePatchContentType is the content type of RFC 7396 JSON merge patches
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType is the content type of RFC 6902 JSON patches
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+var (
+	// ErrInvalidPatch is returned for patches that cannot be decoded or applied
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrForbiddenPath is returned for patches changing fields that are not allowed to be patched
+	ErrForbiddenPath = errors.New("path cannot be patched")
+	// ErrUnsupportedContentType is returned for patches that are neither merge patches nor JSON patches
+	ErrUnsupportedContentType = errors.New("unsupported patch content type")
+)
+
+// Update is the change of a patch as Mongo update operations. The paths are the stored paths of the fields.
+type Update struct {
+	Set   bson.M
+	Unset []string
+}
+
+// IsEmpty checks if the patch does not change anything
+func (u Update) IsEmpty() bool {
+	return len(u.Set) == 0 && len(u.Unset) == 0
+}
+
+// Touches checks if the patch changes the stored field or one of its nested fields
+func (u Update) Touches(field string) bool {
+	touches := func(path string) bool {
+		return path == field || strings.HasPrefix(path, field+".")
+	}
+	for path := range u.Set {
+		if touches(path) {
+			return true
+		}
+	}
+	for _, path := range u.Unset {
+		if touches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply applies the patch of the content type to the JSON representation of original and decodes the result into
+// patched, which must be a pointer to a zero model. Only the top level fields in allowed can be changed, they must
+// have the same name in JSON and BSON. The update sets and unsets the stored fields the patch changed, fields it left
+// untouched keep their stored types even if JSON cannot represent them. The caller is expected to validate patched
+// before writing it.
+func Apply(contentType string, data []byte, original interface{}, patched interface{}, allowed []string) (Update, error) {
+	document, err := toJSONDocument(original)
+	if err != nil {
+		return Update{}, err
+	}
+
+	var result interface{}
+	switch contentType {
+	case MergePatchContentType:
+		var mergePatch interface{}
+		if err := json.Unmarshal(data, &mergePatch); err != nil {
+			return Update{}, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		result = applyMergePatch(document, mergePatch)
+	case JSONPatchContentType:
+		var operations []Operation
+		if err := json.Unmarshal(data, &operations); err != nil {
+			return Update{}, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		if result, err = applyJSONPatch(document, operations); err != nil {
+			return Update{}, err
+		}
+	default:
+		return Update{}, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+
+	resultDocument, ok := result.(map[string]interface{})
+	if !ok {
+		return Update{}, fmt.Errorf("%w: the result is not an object", ErrInvalidPatch)
+	}
+
+	// The JSON representations are compared first, so changes of fields that are not stored are refused as well
+	originalDocument, err := toJSONDocument(original)
+	if err != nil {
+		return Update{}, err
+	}
+	for _, path := range changedPaths(originalDocument, resultDocument, "") {
+		if !isAllowed(path, allowed) {
+			return Update{}, fmt.Errorf("%w: %s", ErrForbiddenPath, path)
+		}
+	}
+
+	resultData, err := json.Marshal(resultDocument)
+	if err != nil {
+		return Update{}, err
+	}
+	if err := json.Unmarshal(resultData, patched); err != nil {
+		return Update{}, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	return storedUpdate(original, patched, allowed)
+}
+
+// storedUpdate compares the stored representations of the allowed fields. patched went through JSON, so values are
+// only considered changed if their JSON representations differ as well, an int32, a date or an ObjectID read back as
+// a number or a string is left alone.
+func storedUpdate(original interface{}, patched interface{}, allowed []string) (Update, error) {
+	before, err := toBSONDocument(original)
+	if err != nil {
+		return Update{}, err
+	}
+	after, err := toBSONDocument(patched)
+	if err != nil {
+		return Update{}, err
+	}
+
+	update := Update{Set: bson.M{}}
+	for _, field := range allowed {
+		beforeValue, inBefore := before[field]
+		afterValue, inAfter := after[field]
+		if err := diff(&update, field, beforeValue, inBefore, afterValue, inAfter); err != nil {
+			return Update{}, err
+		}
+	}
+	sort.Strings(update.Unset)
+	return update, nil
+}
+
+// diff adds the operations turning the value at the path from before to after, sub documents are compared by field
+func diff(update *Update, path string, before interface{}, inBefore bool, after interface{}, inAfter bool) error {
+	switch {
+	case !inAfter && !inBefore:
+		return nil
+	case !inAfter:
+		update.Unset = append(update.Unset, path)
+		return nil
+	case !inBefore:
+		update.Set[path] = after
+		return nil
+	}
+
+	beforeDocument, beforeIsDocument := before.(bson.M)
+	afterDocument, afterIsDocument := after.(bson.M)
+	if !beforeIsDocument || !afterIsDocument {
+		if !reflect.DeepEqual(before, after) && !sameJSON(before, after) {
+			update.Set[path] = after
+		}
+		return nil
+	}
+
+	for _, key := range unionKeys(beforeDocument, afterDocument) {
+		if key == "" || strings.Contains(key, ".") || strings.HasPrefix(key, "$") {
+			return fmt.Errorf("%w: invalid key %q", ErrInvalidPatch, key)
+		}
+		beforeValue, inBeforeDocument := beforeDocument[key]
+		afterValue, inAfterDocument := afterDocument[key]
+		if err := diff(update, path+"."+key, beforeValue, inBeforeDocument, afterValue, inAfterDocument); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changedPaths lists the paths whose values differ, objects are compared by field
+func changedPaths(before, after map[string]interface{}, prefix string) []string {
+	var paths []string
+	keys := make(map[string]bool)
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+	for key := range keys {
+		path := prefix + key
+		beforeObject, beforeIsObject := before[key].(map[string]interface{})
+		afterObject, afterIsObject := after[key].(map[string]interface{})
+		if beforeIsObject && afterIsObject {
+			paths = append(paths, changedPaths(beforeObject, afterObject, path+".")...)
+			continue
+		}
+		beforeValue, inBefore := before[key]
+		afterValue, inAfter := after[key]
+		if inBefore != inAfter || !reflect.DeepEqual(beforeValue, afterValue) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// sameJSON checks if the values have the same JSON representation, which is all a patch can see of them
+func sameJSON(a, b interface{}) bool {
+	aData, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bData, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	var aValue, bValue interface{}
+	if json.Unmarshal(aData, &aValue) != nil || json.Unmarshal(bData, &bValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+func isAllowed(path string, allowed []string) bool {
+	for _, field := range allowed {
+		if path == field || strings.HasPrefix(path, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func unionKeys(a, b bson.M) []string {
+	var keys []string
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toJSONDocument(model interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+func toBSONDocument(model interface{}) (bson.M, error) {
+	data, err := bson.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	var document bson.M
+	if err := bson.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
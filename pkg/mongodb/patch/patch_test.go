@@ -0,0 +1,176 @@
+package patch
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type testContact struct {
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phoneNumber,omitempty"`
+}
+
+type testModel struct {
+	ID         primitive.ObjectID     `json:"id" bson:"_id"`
+	Name       string                 `json:"name" bson:"name"`
+	Avatar     string                 `json:"avatar,omitempty" bson:"avatar,omitempty"`
+	Secret     string                 `json:"-" bson:"secret"`
+	Contact    testContact            `json:"contact" bson:"contact"`
+	Tags       []string               `json:"tags,omitempty" bson:"tags,omitempty"`
+	Properties map[string]interface{} `json:"properties" bson:"properties"`
+}
+
+var testAllowed = []string{"name", "avatar", "contact", "tags", "properties"}
+
+func testOriginal() testModel {
+	return testModel{
+		ID:         primitive.NewObjectID(),
+		Name:       "jane",
+		Avatar:     "avatar.png",
+		Secret:     "hash",
+		Contact:    testContact{Email: "jane@example.com", Phone: "555"},
+		Tags:       []string{"a", "b"},
+		Properties: map[string]interface{}{"plan": "pro", "team": "blue"},
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	t.Run("Null clears fields and zero values are kept", func(t *testing.T) {
+		var patched testModel
+		update, err := Apply(MergePatchContentType, []byte(`{"avatar":null,"properties":{"team":null,"seats":3},"contact":{"phoneNumber":null}}`),
+			testOriginal(), &patched, testAllowed)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expectedUnset := []string{"avatar", "properties.team"}
+		if !reflect.DeepEqual(update.Unset, expectedUnset) {
+			t.Errorf("Expected unset %v, got %v", expectedUnset, update.Unset)
+		}
+		if update.Set["properties.seats"] != float64(3) || update.Set["contact.phone"] != "" || len(update.Set) != 2 {
+			t.Errorf("Unexpected set %v", update.Set)
+		}
+		if patched.Contact.Email != "jane@example.com" {
+			t.Errorf("Expected the email to be kept, got %+v", patched.Contact)
+		}
+	})
+
+	t.Run("Untouched properties keep their stored types", func(t *testing.T) {
+		original := testOriginal()
+		original.Properties["seats"] = int32(3)
+		original.Properties["joined"] = primitive.NewDateTimeFromTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+		original.Properties["manager"] = primitive.NewObjectID()
+		var patched testModel
+		update, err := Apply(MergePatchContentType, []byte(`{"properties":{"plan":"basic","seats":3}}`), original, &patched, testAllowed)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(update.Set, bson.M{"properties.plan": "basic"}) || len(update.Unset) != 0 {
+			t.Errorf("Expected only properties.plan to be set, got %+v", update)
+		}
+	})
+
+	t.Run("Unchanged document has an empty update", func(t *testing.T) {
+		var patched testModel
+		update, err := Apply(MergePatchContentType, []byte(`{"name":"jane"}`), testOriginal(), &patched, testAllowed)
+		if err != nil || !update.IsEmpty() {
+			t.Errorf("Expected an empty update, got %+v (%v)", update, err)
+		}
+	})
+}
+
+func TestJSONPatch(t *testing.T) {
+	t.Run("Operations are applied in order", func(t *testing.T) {
+		var patched testModel
+		update, err := Apply(JSONPatchContentType, []byte(`[
+			{"op":"test","path":"/name","value":"jane"},
+			{"op":"replace","path":"/name","value":"janet"},
+			{"op":"add","path":"/tags/-","value":"c"},
+			{"op":"remove","path":"/tags/0"},
+			{"op":"move","from":"/properties/plan","path":"/properties/tier"}
+		]`), testOriginal(), &patched, []string{"name", "tags", "properties"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if update.Set["name"] != "janet" || update.Set["properties.tier"] != "pro" {
+			t.Errorf("Unexpected set %v", update.Set)
+		}
+		if !reflect.DeepEqual(update.Set["tags"], bson.A{"b", "c"}) {
+			t.Errorf("Expected the tags to be replaced, got %v", update.Set["tags"])
+		}
+		if !reflect.DeepEqual(update.Unset, []string{"properties.plan"}) {
+			t.Errorf("Expected properties.plan to be unset, got %v", update.Unset)
+		}
+	})
+
+	t.Run("Failed test rejects the patch", func(t *testing.T) {
+		var patched testModel
+		_, err := Apply(JSONPatchContentType, []byte(`[{"op":"test","path":"/name","value":"john"},{"op":"replace","path":"/name","value":"x"}]`),
+			testOriginal(), &patched, testAllowed)
+		if !errors.Is(err, ErrInvalidPatch) {
+			t.Errorf("Expected ErrInvalidPatch, got %v", err)
+		}
+	})
+
+	t.Run("Missing paths are refused", func(t *testing.T) {
+		for _, operations := range []string{
+			`[{"op":"remove","path":"/missing"}]`,
+			`[{"op":"replace","path":"/tags/5","value":"x"}]`,
+			`[{"op":"add","path":"/tags/01","value":"x"}]`,
+			`[{"op":"unknown","path":"/name"}]`,
+		} {
+			var patched testModel
+			if _, err := Apply(JSONPatchContentType, []byte(operations), testOriginal(), &patched, testAllowed); !errors.Is(err, ErrInvalidPatch) {
+				t.Errorf("Expected %s to be refused, got %v", operations, err)
+			}
+		}
+	})
+}
+
+func TestPatchRestrictions(t *testing.T) {
+	for name, data := range map[string]string{
+		"ID":            `{"id":"65a000000000000000000001"}`,
+		"Unknown field": `{"secret":"x"}`,
+	} {
+		t.Run(name+" cannot be patched", func(t *testing.T) {
+			var patched testModel
+			if _, err := Apply(MergePatchContentType, []byte(data), testOriginal(), &patched, testAllowed); !errors.Is(err, ErrForbiddenPath) {
+				t.Errorf("Expected ErrForbiddenPath, got %v", err)
+			}
+		})
+	}
+
+	t.Run("Operator keys are refused", func(t *testing.T) {
+		var patched testModel
+		if _, err := Apply(MergePatchContentType, []byte(`{"properties":{"$where":"1"}}`), testOriginal(), &patched, testAllowed); !errors.Is(err, ErrInvalidPatch) {
+			t.Errorf("Expected ErrInvalidPatch, got %v", err)
+		}
+	})
+
+	t.Run("Wrong types are refused", func(t *testing.T) {
+		var patched testModel
+		if _, err := Apply(MergePatchContentType, []byte(`{"name":5}`), testOriginal(), &patched, testAllowed); !errors.Is(err, ErrInvalidPatch) {
+			t.Errorf("Expected ErrInvalidPatch, got %v", err)
+		}
+	})
+
+	t.Run("Other content types are refused", func(t *testing.T) {
+		var patched testModel
+		if _, err := Apply("application/json", []byte(`{}`), testOriginal(), &patched, testAllowed); !errors.Is(err, ErrUnsupportedContentType) {
+			t.Errorf("Expected ErrUnsupportedContentType, got %v", err)
+		}
+	})
+}
+
+func TestUpdateTouches(t *testing.T) {
+	update := Update{Set: bson.M{"properties.plan": "pro"}, Unset: []string{"avatar"}}
+	for field, expected := range map[string]bool{"properties": true, "avatar": true, "prop": false, "name": false} {
+		if update.Touches(field) != expected {
+			t.Errorf("Touches(%q) = %v, want %v", field, !expected, expected)
+		}
+	}
+}
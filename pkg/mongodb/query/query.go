@@ -0,0 +1,296 @@
+// Package query translates the query parameters of listing endpoints to Mongo filters and sorts.
+// Only the fields and operators declared in a Schema are accepted, so clients cannot inject Mongo operators.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidQuery is returned for parameters that are not declared in the schema or have invalid values
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Operator is a comparison a filter parameter applies, written as field[operator]=value. Equality is the default.
+type Operator string
+
+const (
+	Eq     Operator = "eq"
+	Ne     Operator = "ne"
+	In     Operator = "in"
+	Nin    Operator = "nin"
+	Gt     Operator = "gt"
+	Gte    Operator = "gte"
+	Lt     Operator = "lt"
+	Lte    Operator = "lte"
+	Exists Operator = "exists"
+)
+
+// Type is the type the values of a field are parsed as
+type Type int
+
+const (
+	String Type = iota
+	ObjectID
+	Time
+	Number
+	Bool
+)
+
+// BuildFunc builds the filter of a field with custom semantics from the parsed values, the value of exists is a bool
+type BuildFunc func(operator Operator, values []interface{}) (bson.M, error)
+
+// Field declares a filterable or sortable parameter
+type Field struct {
+	// Path is the document field the parameter filters and sorts by
+	Path string
+	// Type is the type the values are parsed as
+	Type Type
+	// Operators are the accepted operators, the field cannot be filtered by if there are none
+	Operators []Operator
+	// Sortable allows sorting by the field
+	Sortable bool
+	// Build replaces the default filter of the field
+	Build BuildFunc
+	// KeyType resolves the type of a prefixed parameter from its key, Type is used for the keys it does not know
+	KeyType func(key string) (Type, bool)
+}
+
+// Schema declares the parameters a listing accepts
+type Schema struct {
+	// Fields are the parameters by name
+	Fields map[string]Field
+	// Prefixes are the parameters whose name continues with an arbitrary key, e.g. "properties." for
+	// properties.plan. The key is appended to the path of the field.
+	Prefixes map[string]Field
+	// Reserved are the parameters that are not filters, e.g. page and limit
+	Reserved []string
+}
+
+// Query is the filter and sort parsed from the parameters
+type Query struct {
+	Filter bson.M
+	Sort   bson.D
+}
+
+// FilterOrEmpty returns the filter, or an empty filter if there is none
+func (q Query) FilterOrEmpty() bson.M {
+	if q.Filter == nil {
+		return bson.M{}
+	}
+	return q.Filter
+}
+
+// SortOrNil returns the sort as an untyped nil if there is none, so repositories apply their default sort
+func (q Query) SortOrNil() interface{} {
+	if len(q.Sort) == 0 {
+		return nil
+	}
+	return q.Sort
+}
+
+// SortParam is the parameter holding the sort, e.g. sort=-createdDate,username
+const SortParam = "sort"
+
+var (
+	parameterPattern = regexp.MustCompile(`^([A-Za-z0-9_.]+?)(?:\[([a-z]+)\])?$`)
+	keyPattern       = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+)
+
+// Parse parses the filter and the sort from the parameters
+func (s Schema) Parse(params url.Values) (Query, error) {
+	filter, err := s.ParseFilter(params)
+	if err != nil {
+		return Query{}, err
+	}
+	sort, err := s.ParseSort(params.Get(SortParam))
+	if err != nil {
+		return Query{}, err
+	}
+	return Query{Filter: filter, Sort: sort}, nil
+}
+
+// ParseFilter builds a filter from every parameter that is not reserved, the conditions are combined with $and
+func (s Schema) ParseFilter(params url.Values) (bson.M, error) {
+	var conditions bson.A
+	for param, values := range params {
+		if param == SortParam || s.isReserved(param) {
+			continue
+		}
+
+		match := parameterPattern.FindStringSubmatch(param)
+		if match == nil {
+			return nil, fmt.Errorf("%w: unknown parameter %q", ErrInvalidQuery, param)
+		}
+		name, operator := match[1], Operator(match[2])
+		if operator == "" {
+			operator = Eq
+		}
+
+		field, path, err := s.field(name)
+		if err != nil {
+			return nil, err
+		}
+		if !field.allows(operator) {
+			return nil, fmt.Errorf("%w: %q cannot be filtered with %q", ErrInvalidQuery, name, operator)
+		}
+
+		for _, value := range values {
+			condition, err := field.condition(path, operator, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %v", ErrInvalidQuery, name, err)
+			}
+			conditions = append(conditions, condition)
+		}
+	}
+
+	switch len(conditions) {
+	case 0:
+		return bson.M{}, nil
+	case 1:
+		return conditions[0].(bson.M), nil
+	default:
+		return bson.M{"$and": conditions}, nil
+	}
+}
+
+// ParseSort builds a sort from comma separated sortable fields, a leading "-" sorts descending.
+// The ID is appended so the order is stable. An empty sort returns nil.
+func (s Schema) ParseSort(sort string) (bson.D, error) {
+	if strings.TrimSpace(sort) == "" {
+		return nil, nil
+	}
+
+	var result bson.D
+	sortsByID := false
+	for _, name := range strings.Split(sort, ",") {
+		name = strings.TrimSpace(name)
+		direction := 1
+		if strings.HasPrefix(name, "-") {
+			direction = -1
+			name = name[1:]
+		}
+
+		field, path, err := s.field(name)
+		if err != nil {
+			return nil, err
+		}
+		if !field.Sortable {
+			return nil, fmt.Errorf("%w: %q cannot be sorted by", ErrInvalidQuery, name)
+		}
+		result = append(result, bson.E{Key: path, Value: direction})
+		sortsByID = sortsByID || path == "_id"
+	}
+	if !sortsByID {
+		result = append(result, bson.E{Key: "_id", Value: 1})
+	}
+	return result, nil
+}
+
+// field finds the declared field of a parameter and the path it applies to
+func (s Schema) field(name string) (Field, string, error) {
+	if field, ok := s.Fields[name]; ok {
+		return field, field.Path, nil
+	}
+	for prefix, field := range s.Prefixes {
+		key, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		if !keyPattern.MatchString(key) {
+			return Field{}, "", fmt.Errorf("%w: invalid key %q", ErrInvalidQuery, key)
+		}
+		if field.KeyType != nil {
+			if keyType, ok := field.KeyType(key); ok {
+				field.Type = keyType
+			}
+		}
+		return field, field.Path + key, nil
+	}
+	return Field{}, "", fmt.Errorf("%w: unknown parameter %q", ErrInvalidQuery, name)
+}
+
+func (s Schema) isReserved(param string) bool {
+	for _, reserved := range s.Reserved {
+		if param == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Field) allows(operator Operator) bool {
+	for _, allowed := range f.Operators {
+		if allowed == operator {
+			return true
+		}
+	}
+	return false
+}
+
+// condition builds the filter of a single parameter value. The in and nin operators take comma separated values.
+func (f Field) condition(path string, operator Operator, raw string) (bson.M, error) {
+	var values []interface{}
+	switch operator {
+	case Exists:
+		exists, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("exists takes true or false")
+		}
+		if f.Build == nil {
+			return bson.M{path: bson.M{"$exists": exists}}, nil
+		}
+		values = []interface{}{exists}
+	case In, Nin:
+		for _, part := range strings.Split(raw, ",") {
+			value, err := f.parse(strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	default:
+		value, err := f.parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		values = []interface{}{value}
+	}
+
+	if f.Build != nil {
+		return f.Build(operator, values)
+	}
+	if operator == Eq {
+		return bson.M{path: values[0]}, nil
+	}
+	if operator == In || operator == Nin {
+		return bson.M{path: bson.M{"$" + string(operator): values}}, nil
+	}
+	return bson.M{path: bson.M{"$" + string(operator): values[0]}}, nil
+}
+
+// parse parses a value as the type of the field. Times are RFC 3339 or dates.
+func (f Field) parse(raw string) (interface{}, error) {
+	switch f.Type {
+	case ObjectID:
+		return primitive.ObjectIDFromHex(raw)
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, raw)
+	case Number:
+		return strconv.ParseFloat(raw, 64)
+	case Bool:
+		return strconv.ParseBool(raw)
+	default:
+		return raw, nil
+	}
+}
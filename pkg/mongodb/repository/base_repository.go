@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/LydiaTrack/ground/pkg/constants"
 	"github.com/LydiaTrack/ground/pkg/responses"
+	"github.com/LydiaTrack/ground/pkg/tenant"
 	"github.com/LydiaTrack/ground/pkg/utils"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -16,6 +19,15 @@ import (
 // BaseRepository provides default implementations for common CRUD operations.
 type BaseRepository[T any] struct {
 	Collection *mongo.Collection
+	// TenantField is the field holding the tenant ID of the documents. When it is set, every operation is scoped
+	// to the tenant of the context and fails with tenant.ErrScopeMissing if the context does not declare one.
+	TenantField string
+	// SoftDelete excludes the documents marked as deleted from every operation, unless the context includes them
+	// with IncludeDeleted.
+	SoftDelete bool
+	// Versioned increments the version field of the documents on every update, so updates guarded with
+	// WithExpectedVersion detect the concurrent ones.
+	Versioned bool
 }
 
 // NewBaseRepository creates a new instance of BaseRepository.
@@ -23,9 +35,72 @@ func NewBaseRepository[T any](collection *mongo.Collection) *BaseRepository[T] {
 	return &BaseRepository[T]{Collection: collection}
 }
 
+// NewTenantBaseRepository creates a new instance of BaseRepository scoped by the tenant ID in tenantField.
+func NewTenantBaseRepository[T any](collection *mongo.Collection, tenantField string) *BaseRepository[T] {
+	return &BaseRepository[T]{Collection: collection, TenantField: tenantField}
+}
+
+// NewSoftDeleteBaseRepository creates a new instance of BaseRepository that excludes the documents marked as deleted.
+func NewSoftDeleteBaseRepository[T any](collection *mongo.Collection) *BaseRepository[T] {
+	return &BaseRepository[T]{Collection: collection, SoftDelete: true}
+}
+
+// ScopeFilter restricts the filter to the tenant of the context and to the documents that are not marked as deleted.
+// Repositories that are not tenant scoped and contexts with a global scope are not restricted to a tenant.
+func (r *BaseRepository[T]) ScopeFilter(ctx context.Context, filter interface{}) (interface{}, error) {
+	var conditions bson.A
+
+	if r.TenantField != "" {
+		scope, ok := tenant.FromContext(ctx)
+		if !ok {
+			return nil, tenant.ErrScopeMissing
+		}
+		if !scope.Global {
+			conditions = append(conditions, bson.M{r.TenantField: scope.TenantID})
+		}
+	}
+
+	if r.SoftDelete && !includesDeleted(ctx) {
+		conditions = append(conditions, bson.M{DeletedAtField: bson.M{"$exists": false}})
+	}
+
+	if len(conditions) == 0 {
+		return filter, nil
+	}
+	if filter == nil {
+		if len(conditions) == 1 {
+			return conditions[0], nil
+		}
+		return bson.M{"$and": conditions}, nil
+	}
+	return bson.M{"$and": append(bson.A{filter}, conditions...)}, nil
+}
+
 // Create inserts a new document into the collection.
+// Tenant scoped repositories set the tenant field to the tenant of the context.
 func (r *BaseRepository[T]) Create(ctx context.Context, entity T) (*mongo.InsertOneResult, error) {
-	return r.Collection.InsertOne(ctx, entity)
+	if r.TenantField == "" {
+		return r.Collection.InsertOne(ctx, entity)
+	}
+
+	scope, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, tenant.ErrScopeMissing
+	}
+	if scope.Global {
+		return r.Collection.InsertOne(ctx, entity)
+	}
+
+	data, err := bson.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var document bson.M
+	if err = bson.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	document[r.TenantField] = scope.TenantID
+	return r.Collection.InsertOne(ctx, document)
 }
 
 // Exists checks if a document matching the filter exists in the collection.
@@ -34,9 +109,13 @@ func (r *BaseRepository[T]) Exists(ctx context.Context, filter interface{}) (boo
 	if filter == nil {
 		filter = bson.M{}
 	}
+	filter, err := r.ScopeFilter(ctx, filter)
+	if err != nil {
+		return false, err
+	}
 
 	// Perform the query
-	err := r.Collection.FindOne(ctx, filter).Err()
+	err = r.Collection.FindOne(ctx, filter).Err()
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return false, nil
 	}
@@ -61,7 +140,10 @@ func (r *BaseRepository[T]) GetByID(ctx context.Context, id interface{}) (T, err
 	if err != nil {
 		return result, err
 	}
-	filter := bson.M{"_id": objectID}
+	filter, err := r.ScopeFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return result, err
+	}
 	err = r.Collection.FindOne(ctx, filter).Decode(&result)
 	if err != nil {
 		elapsed := time.Since(now)
@@ -73,6 +155,8 @@ func (r *BaseRepository[T]) GetByID(ctx context.Context, id interface{}) (T, err
 }
 
 // Update modifies an existing document identified by its ID.
+// If the context expects a version, the document is only updated if it has that version and
+// constants.ErrorVersionConflict is returned if it has another one.
 func (r *BaseRepository[T]) Update(ctx context.Context, id interface{}, updateCommand interface{}) (*mongo.UpdateResult, error) {
 	now := time.Now()
 	// Convert the ID to ObjectID
@@ -87,8 +171,61 @@ func (r *BaseRepository[T]) Update(ctx context.Context, id interface{}, updateCo
 		return nil, err
 	}
 
+	return r.updateOne(ctx, objectID, bson.M{"$set": updateDoc}, now)
+}
+
+// UpdateFields sets and unsets the fields of an existing document identified by its ID, the paths are dotted.
+// It is guarded by the version of the context like Update.
+func (r *BaseRepository[T]) UpdateFields(ctx context.Context, id interface{}, set bson.M, unset []string) (*mongo.UpdateResult, error) {
+	now := time.Now()
+	objectID, err := utils.ToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		unsetDoc := bson.M{}
+		for _, path := range unset {
+			unsetDoc[path] = ""
+		}
+		update["$unset"] = unsetDoc
+	}
+	return r.updateOne(ctx, objectID, update, now)
+}
+
+// UpdateOne applies an update document with operators, e.g. $pull, to an existing document identified by its ID.
+// It is guarded by the version of the context like Update.
+func (r *BaseRepository[T]) UpdateOne(ctx context.Context, id interface{}, update bson.M) (*mongo.UpdateResult, error) {
+	now := time.Now()
+	objectID, err := utils.ToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.updateOne(ctx, objectID, update, now)
+}
+
+// updateOne applies the update to the document, incrementing its version if the repository is versioned
+func (r *BaseRepository[T]) updateOne(ctx context.Context, objectID primitive.ObjectID, update bson.M, now time.Time) (*mongo.UpdateResult, error) {
+	selector := bson.M{"_id": objectID}
+	version, guarded := ExpectedVersion(ctx)
+	if guarded {
+		selector[VersionField] = versionSelector(version)
+	}
+	if r.Versioned || guarded {
+		incrementVersion(update)
+	}
+
+	filter, err := r.ScopeFilter(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
 	// Perform the update
-	result, err := r.Collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": updateDoc})
+	result, err := r.Collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		elapsed := time.Since(now)
 		if elapsed > 100*time.Millisecond {
@@ -96,6 +233,17 @@ func (r *BaseRepository[T]) Update(ctx context.Context, id interface{}, updateCo
 		}
 		return nil, err
 	}
+
+	// Nothing matched the version, the document was either updated concurrently or does not exist
+	if guarded && result.MatchedCount == 0 {
+		exists, err := r.ExistsByID(ctx, objectID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return result, constants.ErrorVersionConflict
+		}
+	}
 	return result, nil
 }
 
@@ -105,17 +253,30 @@ func (r *BaseRepository[T]) Delete(ctx context.Context, id interface{}) (*mongo.
 	if err != nil {
 		return nil, err
 	}
-	return r.Collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	filter, err := r.ScopeFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return nil, err
+	}
+	return r.Collection.DeleteOne(ctx, filter)
 }
 
 // Query retrieves documents matching the provided filter.
 func (r *BaseRepository[T]) Query(ctx context.Context, filter interface{}, searchFields []string, searchText string) (responses.QueryResult[T], error) {
+	return r.QuerySorted(ctx, filter, searchFields, searchText, nil)
+}
+
+// QuerySorted retrieves documents matching the provided filter in the order of sort, a nil sort keeps the natural order.
+func (r *BaseRepository[T]) QuerySorted(ctx context.Context, filter interface{}, searchFields []string, searchText string, sort interface{}) (responses.QueryResult[T], error) {
 	var results []T
 
 	// Ensure filter is not nil
 	if filter == nil {
 		filter = bson.M{}
 	}
+	filter, err := r.ScopeFilter(ctx, filter)
+	if err != nil {
+		return responses.QueryResult[T]{}, err
+	}
 
 	// Add search criteria if searchText and searchFields are provided
 	if searchText != "" && len(searchFields) > 0 {
@@ -128,9 +289,14 @@ func (r *BaseRepository[T]) Query(ctx context.Context, filter interface{}, searc
 		filter = bson.M{"$and": []bson.M{filter.(bson.M), {"$or": searchConditions}}}
 	}
 
+	findOptions := options.Find()
+	if sort != nil {
+		findOptions.SetSort(sort)
+	}
+
 	// Execute the query
 	now := time.Now()
-	cursor, err := r.Collection.Find(ctx, filter)
+	cursor, err := r.Collection.Find(ctx, filter, findOptions)
 	if err != nil {
 		return responses.QueryResult[T]{}, err
 	}
@@ -161,6 +327,35 @@ func (r *BaseRepository[T]) Query(ctx context.Context, filter interface{}, searc
 	return responses.QueryResult[T]{Data: results, TotalElements: len(results)}, nil
 }
 
+// ForEach calls fn for every document matching the filter in the order of their IDs, without loading them all
+// into memory. It stops at the first error fn returns.
+func (r *BaseRepository[T]) ForEach(ctx context.Context, filter interface{}, fn func(T) error) error {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	filter, err := r.ScopeFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := r.Collection.Find(ctx, filter, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var result T
+		if err := cursor.Decode(&result); err != nil {
+			return err
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
 func (r *BaseRepository[T]) QueryPaginate(ctx context.Context, filter interface{}, searchFields []string, searchText string, page, limit int, sort interface{}) (responses.PaginatedResult[T], error) {
 	var results []T
 
@@ -168,6 +363,10 @@ func (r *BaseRepository[T]) QueryPaginate(ctx context.Context, filter interface{
 	if filter == nil {
 		filter = bson.M{}
 	}
+	filter, err := r.ScopeFilter(ctx, filter)
+	if err != nil {
+		return responses.PaginatedResult[T]{}, err
+	}
 
 	// Add search criteria if searchText and searchFields are provided
 	if searchText != "" && len(searchFields) > 0 {
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LydiaTrack/ground/pkg/tenant"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestScopeFilter(t *testing.T) {
+	tenantID := primitive.NewObjectID()
+	scoped := &BaseRepository[bson.M]{TenantField: "organizationId"}
+	filter := bson.M{"name": "test"}
+
+	t.Run("Missing scope is refused", func(t *testing.T) {
+		_, err := scoped.ScopeFilter(context.Background(), filter)
+		if !errors.Is(err, tenant.ErrScopeMissing) {
+			t.Errorf("Expected ErrScopeMissing, got %v", err)
+		}
+	})
+
+	t.Run("Tenant scope is added to the filter", func(t *testing.T) {
+		result, err := scoped.ScopeFilter(tenant.WithTenant(context.Background(), tenantID), filter)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		conditions := result.(bson.M)["$and"].(bson.A)
+		if len(conditions) != 2 || conditions[1].(bson.M)["organizationId"] != tenantID {
+			t.Errorf("Expected the filter to be restricted to the tenant, got %v", result)
+		}
+	})
+
+	t.Run("Global scope keeps the filter", func(t *testing.T) {
+		result, err := scoped.ScopeFilter(tenant.Global(context.Background()), filter)
+		if err != nil || result.(bson.M)["name"] != "test" {
+			t.Errorf("Expected the filter to be unchanged, got %v (%v)", result, err)
+		}
+	})
+
+	t.Run("Repositories without tenant field are not scoped", func(t *testing.T) {
+		result, err := (&BaseRepository[bson.M]{}).ScopeFilter(context.Background(), filter)
+		if err != nil || result.(bson.M)["name"] != "test" {
+			t.Errorf("Expected the filter to be unchanged, got %v (%v)", result, err)
+		}
+	})
+}
+
+func TestScopeFilterSoftDelete(t *testing.T) {
+	softDeleted := &BaseRepository[bson.M]{SoftDelete: true}
+	filter := bson.M{"name": "test"}
+
+	t.Run("Deleted documents are excluded", func(t *testing.T) {
+		result, err := softDeleted.ScopeFilter(context.Background(), filter)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		conditions := result.(bson.M)["$and"].(bson.A)
+		if len(conditions) != 2 || conditions[1].(bson.M)[DeletedAtField] == nil {
+			t.Errorf("Expected the deleted documents to be excluded, got %v", result)
+		}
+	})
+
+	t.Run("Deleted documents can be included", func(t *testing.T) {
+		result, err := softDeleted.ScopeFilter(IncludeDeleted(context.Background()), filter)
+		if err != nil || result.(bson.M)["name"] != "test" {
+			t.Errorf("Expected the filter to be unchanged, got %v (%v)", result, err)
+		}
+	})
+
+	t.Run("Tenant and deletion conditions are combined", func(t *testing.T) {
+		scoped := &BaseRepository[bson.M]{TenantField: "organizationId", SoftDelete: true}
+		result, err := scoped.ScopeFilter(tenant.WithTenant(context.Background(), primitive.NewObjectID()), nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if conditions := result.(bson.M)["$and"].(bson.A); len(conditions) != 2 {
+			t.Errorf("Expected both conditions, got %v", result)
+		}
+	})
+}
+
+func TestIncrementVersion(t *testing.T) {
+	for name, update := range map[string]bson.M{
+		"No increments":      {"$set": bson.M{"name": "test"}},
+		"Map increments":     {"$inc": bson.M{"count": 2}},
+		"Ordered increments": {"$inc": bson.D{{Key: "count", Value: 2}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			incrementVersion(update)
+			increments := update["$inc"].(bson.M)
+			if increments[VersionField] != 1 {
+				t.Errorf("Expected the version to be incremented, got %v", increments)
+			}
+			if _, ok := update["$set"]; !ok && increments["count"] != 2 {
+				t.Errorf("Expected the increments of the caller to be kept, got %v", increments)
+			}
+		})
+	}
+}
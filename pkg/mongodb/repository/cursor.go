@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/LydiaTrack/ground/pkg/responses"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrInvalidCursor is returned for cursors that cannot be decoded or were issued for another sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorRequest describes a page of keyset pagination
+type CursorRequest struct {
+	// Cursor is the Next or Prev cursor of the previous page, empty for the first page
+	Cursor string
+	// Limit is the page size, defaults to 10 and is at most MaxLimit
+	Limit int
+	// Sort is the order of the pages, the ID is appended so the order is total. The sort fields should be present
+	// on every document, documents missing them cannot be paged past.
+	Sort bson.D
+	// Count also counts the documents matching the filter, which is slow on large collections
+	Count bool
+}
+
+// cursorToken is the content of a cursor: the sort key of the document the page starts after and the direction
+type cursorToken struct {
+	Keys     []string `bson:"k"`
+	Values   bson.A   `bson:"v"`
+	Backward bool     `bson:"b,omitempty"`
+}
+
+// QueryCursor retrieves a page of the documents matching the filter after or before the document of the cursor.
+// Unlike QueryPaginate, pages stay consistent when documents are inserted or deleted between requests.
+func (r *BaseRepository[T]) QueryCursor(ctx context.Context, filter interface{}, searchFields []string, searchText string, request CursorRequest) (responses.CursorResult[T], error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	filter, err := r.ScopeFilter(ctx, filter)
+	if err != nil {
+		return responses.CursorResult[T]{}, err
+	}
+
+	if searchText != "" && len(searchFields) > 0 {
+		searchConditions := bson.A{}
+		for _, field := range searchFields {
+			searchConditions = append(searchConditions, bson.M{
+				field: bson.M{"$regex": searchText, "$options": "i"}, // Case-insensitive search
+			})
+		}
+		filter = bson.M{"$and": bson.A{filter, bson.M{"$or": searchConditions}}}
+	}
+
+	if request.Limit < 1 {
+		request.Limit = 10
+	}
+	request.Limit = min(request.Limit, MaxLimit)
+	sort := cursorSort(request.Sort)
+
+	var token *cursorToken
+	pageFilter := filter
+	if request.Cursor != "" {
+		token, err = decodeCursor(request.Cursor, sort)
+		if err != nil {
+			return responses.CursorResult[T]{}, err
+		}
+		pageFilter = bson.M{"$and": bson.A{filter, keysetFilter(sort, token.Values, token.Backward)}}
+	}
+	backward := token != nil && token.Backward
+
+	// One more document is fetched to know if there is a page after this one
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(request.Limit + 1))
+	if backward {
+		findOptions.SetSort(reverseSort(sort))
+	} else {
+		findOptions.SetSort(sort)
+	}
+
+	cursor, err := r.Collection.Find(ctx, pageFilter, findOptions)
+	if err != nil {
+		return responses.CursorResult[T]{}, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []T
+	if err := cursor.All(ctx, &results); err != nil {
+		return responses.CursorResult[T]{}, err
+	}
+
+	hasMore := len(results) > request.Limit
+	if hasMore {
+		results = results[:request.Limit]
+	}
+	if backward {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+	if results == nil {
+		results = []T{}
+	}
+
+	result := responses.CursorResult[T]{Data: results, Limit: request.Limit}
+	if len(results) > 0 {
+		// Going backward, the page after this one is the one the cursor came from and always exists
+		if hasMore || backward {
+			if result.Next, err = encodeCursor(results[len(results)-1], sort, false); err != nil {
+				return responses.CursorResult[T]{}, err
+			}
+		}
+		if (backward && hasMore) || (!backward && token != nil) {
+			if result.Prev, err = encodeCursor(results[0], sort, true); err != nil {
+				return responses.CursorResult[T]{}, err
+			}
+		}
+	}
+
+	if request.Count {
+		totalElements, err := r.Collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return responses.CursorResult[T]{}, err
+		}
+		result.TotalElements = &totalElements
+	}
+
+	return result, nil
+}
+
+// encodeCursor encodes the sort key of the document as an opaque cursor. A backward cursor pages to the documents
+// before the document, a forward one to the documents after it.
+func encodeCursor(document interface{}, sort bson.D, backward bool) (string, error) {
+	sort = cursorSort(sort)
+	raw, err := bson.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+
+	token := cursorToken{Keys: sortKeys(sort), Backward: backward}
+	for _, key := range token.Keys {
+		var value interface{}
+		if element, err := bson.Raw(raw).LookupErr(strings.Split(key, ".")...); err == nil {
+			if err := element.Unmarshal(&value); err != nil {
+				return "", err
+			}
+		}
+		token.Values = append(token.Values, value)
+	}
+
+	data, err := bson.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decodes a cursor and checks it was issued for the sort. Cursors come from the clients, so only scalar
+// values are accepted: a document could carry query operators into the keyset filter.
+func decodeCursor(cursor string, sort bson.D) (*cursorToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var token cursorToken
+	if err := bson.Unmarshal(data, &token); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	keys := sortKeys(cursorSort(sort))
+	if len(token.Keys) != len(keys) || len(token.Values) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+	for i, key := range keys {
+		if token.Keys[i] != key || !isScalar(token.Values[i]) {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return &token, nil
+}
+
+// isScalar checks if the value of a cursor is a plain value that is compared as is, not a document, an array,
+// a regular expression or code
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case nil, string, bool, int32, int64, float64, primitive.ObjectID, primitive.DateTime, primitive.Timestamp,
+		primitive.Decimal128:
+		return true
+	}
+	return false
+}
+
+// cursorSort appends the ID to the sort so every document has a distinct sort key
+func cursorSort(sort bson.D) bson.D {
+	for _, element := range sort {
+		if element.Key == "_id" {
+			return sort
+		}
+	}
+	result := make(bson.D, len(sort), len(sort)+1)
+	copy(result, sort)
+	return append(result, bson.E{Key: "_id", Value: 1})
+}
+
+// keysetFilter matches the documents after the sort key in the order of the sort, or before it if backward:
+// (k1 > v1) or (k1 = v1 and k2 > v2) or ...
+func keysetFilter(sort bson.D, values bson.A, backward bool) bson.M {
+	conditions := bson.A{}
+	for i, element := range sort {
+		condition := bson.M{}
+		for j := 0; j < i; j++ {
+			condition[sort[j].Key] = values[j]
+		}
+		operator := "$gt"
+		if isDescending(element.Value) != backward {
+			operator = "$lt"
+		}
+		condition[element.Key] = bson.M{operator: values[i]}
+		conditions = append(conditions, condition)
+	}
+	return bson.M{"$or": conditions}
+}
+
+func reverseSort(sort bson.D) bson.D {
+	result := make(bson.D, len(sort))
+	for i, element := range sort {
+		direction := -1
+		if isDescending(element.Value) {
+			direction = 1
+		}
+		result[i] = bson.E{Key: element.Key, Value: direction}
+	}
+	return result
+}
+
+func isDescending(direction interface{}) bool {
+	switch value := direction.(type) {
+	case int:
+		return value < 0
+	case int32:
+		return value < 0
+	case int64:
+		return value < 0
+	case float64:
+		return value < 0
+	}
+	return false
+}
+
+func sortKeys(sort bson.D) []string {
+	keys := make([]string, len(sort))
+	for i, element := range sort {
+		keys[i] = element.Key
+	}
+	return keys
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type cursorDocument struct {
+	ID      primitive.ObjectID `bson:"_id"`
+	Created time.Time          `bson:"createdDate"`
+	Info    struct {
+		Name string `bson:"name"`
+	} `bson:"info"`
+}
+
+func TestCursor(t *testing.T) {
+	document := cursorDocument{ID: primitive.NewObjectID(), Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	document.Info.Name = "jane"
+	sort := bson.D{{Key: "createdDate", Value: -1}, {Key: "info.name", Value: 1}}
+
+	t.Run("Cursor keeps the sort key", func(t *testing.T) {
+		cursor, err := encodeCursor(document, sort, true)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		token, err := decodeCursor(cursor, sort)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !token.Backward || len(token.Values) != 3 {
+			t.Fatalf("Unexpected token %+v", token)
+		}
+		if token.Values[0] != primitive.NewDateTimeFromTime(document.Created) || token.Values[1] != "jane" || token.Values[2] != document.ID {
+			t.Errorf("Expected the sort key of the document, got %v", token.Values)
+		}
+	})
+
+	t.Run("Cursor of another sort is refused", func(t *testing.T) {
+		cursor, err := encodeCursor(document, sort, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := decodeCursor(cursor, bson.D{{Key: "createdDate", Value: -1}}); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("Malformed cursor is refused", func(t *testing.T) {
+		for _, cursor := range []string{"not a cursor", "e30"} {
+			if _, err := decodeCursor(cursor, sort); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Expected ErrInvalidCursor for %q, got %v", cursor, err)
+			}
+		}
+	})
+
+	t.Run("Cursor with operators or non-scalar values is refused", func(t *testing.T) {
+		keys := sortKeys(cursorSort(sort))
+		for _, value := range []interface{}{
+			bson.M{"$ne": nil},
+			bson.A{"jane"},
+			primitive.Regex{Pattern: ".*"},
+			primitive.JavaScript("return true"),
+		} {
+			data, err := bson.Marshal(cursorToken{Keys: keys, Values: bson.A{primitive.NewDateTimeFromTime(document.Created), value, document.ID}})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if _, err := decodeCursor(base64.RawURLEncoding.EncodeToString(data), sort); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Expected ErrInvalidCursor for %v, got %v", value, err)
+			}
+		}
+	})
+
+	t.Run("Cursor of a document missing a sort field is accepted", func(t *testing.T) {
+		cursor, err := encodeCursor(cursorDocument{ID: document.ID, Created: document.Created}, bson.D{{Key: "missing", Value: 1}}, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := decodeCursor(cursor, bson.D{{Key: "missing", Value: 1}}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestKeysetFilter(t *testing.T) {
+	sort := cursorSort(bson.D{{Key: "createdDate", Value: -1}})
+	values := bson.A{"2024", "id"}
+
+	t.Run("Forward follows the sort", func(t *testing.T) {
+		conditions := keysetFilter(sort, values, false)["$or"].(bson.A)
+		if len(conditions) != 2 {
+			t.Fatalf("Expected 2 conditions, got %v", conditions)
+		}
+		first := conditions[0].(bson.M)
+		if first["createdDate"].(bson.M)["$lt"] != "2024" {
+			t.Errorf("Expected the dates before the cursor, got %v", first)
+		}
+		second := conditions[1].(bson.M)
+		if second["createdDate"] != "2024" || second["_id"].(bson.M)["$gt"] != "id" {
+			t.Errorf("Expected the ties after the cursor ID, got %v", second)
+		}
+	})
+
+	t.Run("Backward reverses the sort", func(t *testing.T) {
+		conditions := keysetFilter(sort, values, true)["$or"].(bson.A)
+		if conditions[0].(bson.M)["createdDate"].(bson.M)["$gt"] != "2024" {
+			t.Errorf("Expected the dates after the cursor, got %v", conditions[0])
+		}
+		reversed := reverseSort(sort)
+		if reversed[0].Value != 1 || reversed[1].Value != -1 {
+			t.Errorf("Expected the reversed sort, got %v", reversed)
+		}
+	})
+}
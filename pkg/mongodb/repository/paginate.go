@@ -8,6 +8,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// MaxLimit is the largest page size of the paginated queries, larger limits are reduced to it
+const MaxLimit = 100
+
 // Paginate retrieves a paginated list of documents based on the filter, page, limit, and sort criteria.
 func (r *BaseRepository[T]) Paginate(ctx context.Context, filter interface{}, page, limit int, sort interface{}) (responses.PaginatedResult[T], error) {
 	var results []T
@@ -19,11 +22,16 @@ func (r *BaseRepository[T]) Paginate(ctx context.Context, filter interface{}, pa
 	if limit < 1 {
 		limit = 10
 	}
+	limit = min(limit, MaxLimit)
 
 	// Default to an empty filter if none is provided
 	if filter == nil {
 		filter = bson.M{}
 	}
+	filter, err := r.ScopeFilter(ctx, filter)
+	if err != nil {
+		return responses.PaginatedResult[T]{}, err
+	}
 
 	// Default sort order if none is provided
 	if sort == nil {
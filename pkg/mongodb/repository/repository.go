@@ -17,4 +17,5 @@ type Repository[T any] interface {
 	ExistsByID(ctx context.Context, id interface{}) (bool, error)
 	Query(ctx context.Context, filter interface{}, searchFields []string, searchText string) (responses.QueryResult[T], error)
 	QueryPaginate(ctx context.Context, filter interface{}, searchFields []string, searchText string, page, limit int, sort interface{}) (responses.PaginatedResult[T], error)
+	QueryCursor(ctx context.Context, filter interface{}, searchFields []string, searchText string, request CursorRequest) (responses.CursorResult[T], error)
 }
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// DeletedAtField is the field holding the time a document was marked as deleted
+	DeletedAtField = "deletedAt"
+	// DeletedByField is the field holding the ID of the user that marked a document as deleted
+	DeletedByField = "deletedBy"
+)
+
+type includeDeletedKey struct{}
+
+// IncludeDeleted returns a context whose operations also see the documents marked as deleted
+func IncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// includesDeleted checks if the context includes the documents marked as deleted
+func includesDeleted(ctx context.Context) bool {
+	included, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return included
+}
+
+// MarkDeleted marks a document as deleted by the given user, the document is kept until it is deleted.
+func (r *BaseRepository[T]) MarkDeleted(ctx context.Context, id interface{}, deletedBy *primitive.ObjectID) (*mongo.UpdateResult, error) {
+	objectID, err := utils.ToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := r.ScopeFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{DeletedAtField: time.Now()}
+	if deletedBy != nil {
+		set[DeletedByField] = deletedBy
+	}
+	return r.Collection.UpdateOne(ctx, filter, r.versioned(bson.M{"$set": set}))
+}
+
+// Restore removes the deletion mark of a document
+func (r *BaseRepository[T]) Restore(ctx context.Context, id interface{}) (*mongo.UpdateResult, error) {
+	objectID, err := utils.ToObjectID(id)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := r.ScopeFilter(IncludeDeleted(ctx), bson.M{"_id": objectID, DeletedAtField: bson.M{"$exists": true}})
+	if err != nil {
+		return nil, err
+	}
+	return r.Collection.UpdateOne(ctx, filter, r.versioned(bson.M{"$unset": bson.M{DeletedAtField: "", DeletedByField: ""}}))
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"maps"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// VersionField is the field holding the version of a document, it is incremented on every update of versioned
+// repositories
+const VersionField = "version"
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion returns a context whose updates only apply if the document still has the version, otherwise
+// they fail with constants.ErrorVersionConflict
+func WithExpectedVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// ExpectedVersion returns the version the context expects the updated document to have
+func ExpectedVersion(ctx context.Context) (int, bool) {
+	version, ok := ctx.Value(expectedVersionKey{}).(int)
+	return version, ok
+}
+
+// versioned adds the increment of the version to the update if the repository is versioned
+func (r *BaseRepository[T]) versioned(update bson.M) bson.M {
+	if r.Versioned {
+		incrementVersion(update)
+	}
+	return update
+}
+
+// incrementVersion adds the increment of the version to the $inc of an update, keeping the other increments
+func incrementVersion(update bson.M) {
+	increments := bson.M{}
+	switch inc := update["$inc"].(type) {
+	case bson.M:
+		maps.Copy(increments, inc)
+	case map[string]interface{}:
+		maps.Copy(increments, inc)
+	case bson.D:
+		for _, element := range inc {
+			increments[element.Key] = element.Value
+		}
+	}
+	increments[VersionField] = 1
+	update["$inc"] = increments
+}
+
+// versionSelector returns the condition on the version of a document a guarded update expects, the documents stored
+// before they were versioned have version zero
+func versionSelector(version int) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// VersionIncrement is the stage of an update pipeline that increments the version of the documents, for the
+// pipeline updates of versioned repositories
+func VersionIncrement() bson.M {
+	return bson.M{"$set": bson.M{VersionField: bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$" + VersionField, 0}}, 1}}}}
+}
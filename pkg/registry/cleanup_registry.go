@@ -0,0 +1,47 @@
+package registry
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// UserCleanupHook defines the interface for removing the data a user owns when the user is purged.
+type UserCleanupHook interface {
+	// CleanupUser removes the data of the user.
+	CleanupUser(userID primitive.ObjectID) error
+}
+
+// UserCleanupFunc adapts a function to a UserCleanupHook, so host applications can register plain callbacks.
+type UserCleanupFunc func(userID primitive.ObjectID) error
+
+// CleanupUser calls f(userID).
+func (f UserCleanupFunc) CleanupUser(userID primitive.ObjectID) error {
+	return f(userID)
+}
+
+// cleanupRegistry is the singleton instance of CleanupRegistry.
+var cleanupRegistry = &CleanupRegistry{
+	userCleanupHooks: []UserCleanupHook{},
+}
+
+// CleanupRegistry manages the registration of the cleanup hooks.
+type CleanupRegistry struct {
+	userCleanupHooks []UserCleanupHook
+}
+
+// RegisterUserCleanupHook registers a new UserCleanupHook in the global CleanupRegistry.
+func RegisterUserCleanupHook(hook UserCleanupHook) {
+	cleanupRegistry.userCleanupHooks = append(cleanupRegistry.userCleanupHooks, hook)
+}
+
+// RunUserCleanupHooks runs every registered UserCleanupHook for the user, stopping at the first failure.
+func RunUserCleanupHooks(userID primitive.ObjectID) error {
+	for _, hook := range cleanupRegistry.userCleanupHooks {
+		if err := hook.CleanupUser(userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterUserCleanupFunc registers a callback that removes the data a user owns when the user is purged.
+func RegisterUserCleanupFunc(cleanup func(userID primitive.ObjectID) error) {
+	RegisterUserCleanupHook(UserCleanupFunc(cleanup))
+}
@@ -0,0 +1,38 @@
+package registry
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// UserDataExporter defines the interface for contributing the data a user owns to the export of the user.
+type UserDataExporter interface {
+	// ExportUserData returns the data of the user, it is written to the export as JSON.
+	ExportUserData(userID primitive.ObjectID) (interface{}, error)
+}
+
+// UserDataExportFunc adapts a function to a UserDataExporter, so host applications can register plain callbacks.
+type UserDataExportFunc func(userID primitive.ObjectID) (interface{}, error)
+
+// ExportUserData calls f(userID).
+func (f UserDataExportFunc) ExportUserData(userID primitive.ObjectID) (interface{}, error) {
+	return f(userID)
+}
+
+// exportRegistry is the singleton instance of ExportRegistry.
+var exportRegistry = &ExportRegistry{
+	userDataExporters: map[string]UserDataExporter{},
+}
+
+// ExportRegistry manages the registration of the user data exporters.
+type ExportRegistry struct {
+	userDataExporters map[string]UserDataExporter
+}
+
+// RegisterUserDataExporter registers a new UserDataExporter under a name in the global ExportRegistry.
+// The name is the file the data is written to in the export, a later registration replaces the previous one.
+func RegisterUserDataExporter(name string, exporter UserDataExporter) {
+	exportRegistry.userDataExporters[name] = exporter
+}
+
+// GetAllUserDataExporters retrieves every registered UserDataExporter by name.
+func GetAllUserDataExporters() map[string]UserDataExporter {
+	return exportRegistry.userDataExporters
+}
@@ -0,0 +1,39 @@
+package registry
+
+import "github.com/LydiaTrack/ground/pkg/auth"
+
+// PermissionProvider defines the interface for providing the permissions an application checks.
+type PermissionProvider interface {
+	// GetPermissions returns the permissions that can be assigned to roles.
+	GetPermissions() []auth.Permission
+}
+
+// permissionRegistry is the singleton instance of PermissionRegistry.
+var permissionRegistry = &PermissionRegistry{
+	permissionProviders: []PermissionProvider{},
+}
+
+// PermissionRegistry manages the registration and retrieval of permissions.
+type PermissionRegistry struct {
+	permissionProviders []PermissionProvider
+}
+
+// RegisterPermissionProvider registers a new PermissionProvider in the global PermissionRegistry.
+func RegisterPermissionProvider(permissionProvider PermissionProvider) {
+	permissionRegistry.permissionProviders = append(permissionRegistry.permissionProviders, permissionProvider)
+}
+
+// GetAllPermissions retrieves all permissions from all registered PermissionProviders without duplicates.
+func GetAllPermissions() []auth.Permission {
+	seen := make(map[auth.Permission]bool)
+	permissions := []auth.Permission{}
+	for _, permissionProvider := range permissionRegistry.permissionProviders {
+		for _, permission := range permissionProvider.GetPermissions() {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return permissions
+}
@@ -0,0 +1,66 @@
+package registry
+
+import (
+	"fmt"
+
+	"github.com/LydiaTrack/ground/pkg/jsonschema"
+)
+
+// PropertyVisibility defines who can see a key of the custom properties of a user.
+type PropertyVisibility string
+
+const (
+	// PropertyVisibilityPublic keys are visible to anyone who can see the user.
+	PropertyVisibilityPublic PropertyVisibility = "PUBLIC"
+	// PropertyVisibilitySelf keys are visible to the user and the admins. Keys without a visibility are self keys.
+	PropertyVisibilitySelf PropertyVisibility = "SELF"
+	// PropertyVisibilityAdmin keys are only visible to, and can only be changed by, the admins.
+	PropertyVisibilityAdmin PropertyVisibility = "ADMIN"
+)
+
+// UserPropertiesSchema describes the custom properties of the users.
+type UserPropertiesSchema struct {
+	// Schema validates the properties object, nil if only the visibility is declared.
+	Schema *jsonschema.Schema
+	// Visibility is the visibility of the keys by name.
+	Visibility map[string]PropertyVisibility
+}
+
+// VisibilityOf returns the visibility of a key, self if it was not declared.
+func (s UserPropertiesSchema) VisibilityOf(key string) PropertyVisibility {
+	if visibility, ok := s.Visibility[key]; ok {
+		return visibility
+	}
+	return PropertyVisibilitySelf
+}
+
+// userPropertiesSchema is the schema registered by the host application.
+var userPropertiesSchema = UserPropertiesSchema{}
+
+// RegisterUserPropertiesSchema registers the JSON Schema of the custom properties of the users and the visibility of
+// their keys. The schema is optional, see package jsonschema for the supported keywords. A later registration
+// replaces the previous one.
+func RegisterUserPropertiesSchema(schema []byte, visibility map[string]PropertyVisibility) error {
+	var compiled *jsonschema.Schema
+	if len(schema) > 0 {
+		var err error
+		if compiled, err = jsonschema.Compile(schema); err != nil {
+			return err
+		}
+	}
+	for key, keyVisibility := range visibility {
+		switch keyVisibility {
+		case PropertyVisibilityPublic, PropertyVisibilitySelf, PropertyVisibilityAdmin:
+		default:
+			return fmt.Errorf("unknown visibility %q of property %q", keyVisibility, key)
+		}
+	}
+
+	userPropertiesSchema = UserPropertiesSchema{Schema: compiled, Visibility: visibility}
+	return nil
+}
+
+// GetUserPropertiesSchema retrieves the registered schema of the custom properties of the users.
+func GetUserPropertiesSchema() UserPropertiesSchema {
+	return userPropertiesSchema
+}
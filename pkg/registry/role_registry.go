@@ -1,19 +1,33 @@
 package registry
 
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
 // RoleProvider defines the interface for providing roles in specific situations.
 type RoleProvider interface {
 	// GetDefaultRoleNames returns the default roles for a new user.
 	GetDefaultRoleNames() []string
 }
 
+// UserRoleProvider defines the interface for providing the roles a user holds indirectly, e.g. through groups.
+type UserRoleProvider interface {
+	// GetUserRoleIDs returns the IDs of the roles the user holds indirectly.
+	GetUserRoleIDs(userID primitive.ObjectID) ([]primitive.ObjectID, error)
+}
+
 // roleRegistry is the singleton instance of RoleRegistry.
 var roleRegistry = &RoleRegistry{
-	roleProviders: []RoleProvider{},
+	roleProviders:     []RoleProvider{},
+	userRoleProviders: []UserRoleProvider{},
 }
 
 // RoleRegistry manages the registration and retrieval of roles.
 type RoleRegistry struct {
-	roleProviders []RoleProvider
+	roleProviders     []RoleProvider
+	userRoleProviders []UserRoleProvider
 }
 
 // RegisterRoleProvider registers a new RoleProvider in the global RoleRegistry.
@@ -29,3 +43,29 @@ func GetAllDefaultRoleNames() []string {
 	}
 	return roleNames
 }
+
+// RegisterUserRoleProvider registers a new UserRoleProvider in the global RoleRegistry.
+func RegisterUserRoleProvider(userRoleProvider UserRoleProvider) {
+	roleRegistry.userRoleProviders = append(roleRegistry.userRoleProviders, userRoleProvider)
+}
+
+// UnregisterUserRoleProvider removes a registered UserRoleProvider from the global RoleRegistry, the provider is
+// compared by identity so it should be registered as a pointer.
+func UnregisterUserRoleProvider(userRoleProvider UserRoleProvider) {
+	roleRegistry.userRoleProviders = slices.DeleteFunc(roleRegistry.userRoleProviders, func(registered UserRoleProvider) bool {
+		return registered == userRoleProvider
+	})
+}
+
+// GetAllUserRoleIDs retrieves the IDs of the roles the user holds indirectly from all registered UserRoleProviders.
+func GetAllUserRoleIDs(userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	var roleIDs []primitive.ObjectID
+	for _, userRoleProvider := range roleRegistry.userRoleProviders {
+		providedRoleIDs, err := userRoleProvider.GetUserRoleIDs(userID)
+		if err != nil {
+			return nil, err
+		}
+		roleIDs = append(roleIDs, providedRoleIDs...)
+	}
+	return roleIDs, nil
+}
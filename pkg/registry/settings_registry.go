@@ -0,0 +1,23 @@
+package registry
+
+import "github.com/LydiaTrack/ground/pkg/domain/settings"
+
+// settingsNamespaces are the namespaces of the user settings registered by the host application, by name.
+var settingsNamespaces = map[string]settings.Namespace{}
+
+// RegisterUserSettings registers a namespace of the user settings with the defaults of its keys. The users can only
+// set the registered keys, to values of the JSON type of their default. A later registration replaces the previous one.
+func RegisterUserSettings(namespace string, defaults map[string]interface{}) error {
+	registered, err := settings.NewNamespace(namespace, defaults)
+	if err != nil {
+		return err
+	}
+	settingsNamespaces[namespace] = registered
+	return nil
+}
+
+// GetUserSettingsNamespace retrieves a registered namespace of the user settings.
+func GetUserSettingsNamespace(namespace string) (settings.Namespace, bool) {
+	registered, ok := settingsNamespaces[namespace]
+	return registered, ok
+}
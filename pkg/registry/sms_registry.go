@@ -0,0 +1,18 @@
+package registry
+
+import "github.com/LydiaTrack/ground/pkg/sms"
+
+// smsSender is the sender registered by the host application, nil to use the one configured by the environment.
+var smsSender sms.SMSSender
+
+// RegisterSMSSender registers the sender of the text messages, such as the phone verification codes. It replaces
+// the sender selected by SMS_SENDER_TYPE, and must be registered before the server is initialized in production mode
+// unless SMS_SENDER_TYPE selects a real sender.
+func RegisterSMSSender(sender sms.SMSSender) {
+	smsSender = sender
+}
+
+// GetSMSSender retrieves the registered sender of the text messages, nil if none was registered.
+func GetSMSSender() sms.SMSSender {
+	return smsSender
+}
@@ -16,3 +16,13 @@ type PaginatedResult[T any] struct {
 	Page          int   `json:"page"`
 	Limit         int   `json:"limit"`
 }
+
+// CursorResult holds a page of keyset paginated data along with the cursors of the adjacent pages.
+// Next and Prev are empty when there is no page in that direction, TotalElements is only set when counted.
+type CursorResult[T any] struct {
+	Data          []T    `json:"data"`
+	Next          string `json:"next,omitempty"`
+	Prev          string `json:"prev,omitempty"`
+	Limit         int    `json:"limit"`
+	TotalElements *int64 `json:"totalElements,omitempty"`
+}
@@ -1,19 +1,36 @@
 package service_initializer
 
 import (
+	"errors"
+
 	"github.com/LydiaTrack/ground/internal/repository"
 	"github.com/LydiaTrack/ground/internal/service"
 	"github.com/LydiaTrack/ground/pkg/auth"
+	"github.com/LydiaTrack/ground/pkg/registry"
+	"github.com/LydiaTrack/ground/pkg/sms"
 )
 
 type Services struct {
-	AuthService          *auth.Service
-	RoleService          *service.RoleService
-	SessionService       *service.SessionService
-	UserService          *service.UserService
-	UserStatsService     *service.UserStatsService
-	ResetPasswordService *service.ResetPasswordService
-	FeedbackService      *service.FeedbackService
+	AuthService              *auth.Service
+	RoleService              *service.RoleService
+	SessionService           *service.SessionService
+	UserService              *service.UserService
+	UserStatsService         *service.UserStatsService
+	ResetPasswordService     *service.ResetPasswordService
+	FeedbackService          *service.FeedbackService
+	PermissionService        *service.PermissionService
+	OrganizationService      *service.OrganizationService
+	AuditService             *service.AuditService
+	GroupService             *service.GroupService
+	InvitationService        *service.InvitationService
+	AccountStatusService     *service.AccountStatusService
+	AccountDeletionService   *service.AccountDeletionService
+	DataExportService        *service.DataExportService
+	UserImportService        *service.UserImportService
+	AvatarService            *service.AvatarService
+	PhoneVerificationService *service.PhoneVerificationService
+	SettingsService          *service.SettingsService
+	ChangelogService         *service.ChangelogService
 }
 
 var services Services
@@ -21,6 +38,7 @@ var services Services
 // InitializeServices initializes all services and assigns them to the gin Engine
 func InitializeServices() {
 	roleRepository := repository.GetRoleMongoRepository()
+	blobStore := repository.GetBlobStore()
 	services.RoleService = service.NewRoleService(roleRepository)
 
 	// Initialize UserStatsService before UserService
@@ -31,12 +49,78 @@ func InitializeServices() {
 		repository.GetUserMongoRepository(roleRepository),
 		*services.RoleService,
 		services.UserStatsService,
+		repository.GetIdentityChangeMongoRepository(),
+		blobStore,
 	)
 
 	services.SessionService = service.NewSessionService(repository.GetSessionRepository(), *services.UserService)
+	services.AccountStatusService = service.NewAccountStatusService(*services.UserService, *services.SessionService)
+	services.DataExportService = service.NewDataExportService(repository.GetDataExportMongoRepository(), *services.UserService, blobStore)
 	services.AuthService = auth.NewAuthService(*services.UserService, *services.SessionService)
+	services.AccountDeletionService = service.NewAccountDeletionService(*services.UserService, *services.SessionService, *services.AuthService)
 	services.ResetPasswordService = service.NewResetPasswordService(repository.GetResetPasswordRepository(), *services.UserService)
 	services.FeedbackService = service.NewFeedbackService(repository.GetFeedbackRepository(), *services.UserService)
+	services.PermissionService = service.NewPermissionService(*services.UserService, *services.RoleService)
+	services.OrganizationService = service.NewOrganizationService(
+		repository.GetOrganizationMongoRepository(),
+		repository.GetMembershipMongoRepository(),
+		*services.UserService,
+		*services.RoleService,
+	)
+
+	auditService := service.NewAuditService(repository.GetAuditRepository())
+	services.AuditService = &auditService
+	services.GroupService = service.NewGroupService(
+		repository.GetGroupMongoRepository(),
+		*services.UserService,
+		*services.RoleService,
+		*services.AuditService,
+	)
+	services.InvitationService = service.NewInvitationService(
+		repository.GetInvitationMongoRepository(),
+		*services.UserService,
+		*services.RoleService,
+	)
+	services.UserImportService = service.NewUserImportService(*services.UserService, *services.RoleService, *services.InvitationService)
+	services.AvatarService = service.NewAvatarService(*services.UserService, blobStore)
+	// A sender registered by the host application is used instead of the one of the environment
+	smsSender, err := sms.NewSMSSenderFromEnv()
+	if err != nil && !(errors.Is(err, sms.ErrNoSender) && registry.GetSMSSender() != nil) {
+		panic(err)
+	}
+	services.PhoneVerificationService = service.NewPhoneVerificationService(
+		repository.GetPhoneVerificationMongoRepository(),
+		*services.UserService,
+		smsSender,
+	)
+	services.SettingsService = service.NewSettingsService(repository.GetSettingsMongoRepository())
+	services.ChangelogService = service.NewChangelogService(
+		repository.GetChangelogMongoRepository(),
+		*services.UserService,
+		*services.RoleService,
+	)
+
+	// Users hold the roles of their groups in addition to their own roles
+	registry.RegisterUserRoleProvider(services.GroupService)
+	registry.RegisterUserCleanupHook(services.SessionService)
+	registry.RegisterUserCleanupHook(services.UserStatsService)
+	registry.RegisterUserCleanupHook(services.FeedbackService)
+	registry.RegisterUserCleanupHook(services.ResetPasswordService)
+	registry.RegisterUserCleanupHook(services.GroupService)
+	registry.RegisterUserCleanupHook(services.OrganizationService)
+	registry.RegisterUserCleanupHook(services.DataExportService)
+	registry.RegisterUserCleanupHook(services.AvatarService)
+	registry.RegisterUserCleanupHook(services.PhoneVerificationService)
+	registry.RegisterUserCleanupHook(services.SettingsService)
+	registry.RegisterUserCleanupFunc(services.UserService.DeleteIdentityHistory)
+	registry.RegisterUserDataExporter("sessions", services.SessionService)
+	registry.RegisterUserDataExporter("stats", services.UserStatsService)
+	registry.RegisterUserDataExporter("feedback", services.FeedbackService)
+	registry.RegisterUserDataExporter("audit", services.AuditService)
+	registry.RegisterUserDataExporter("settings", services.SettingsService)
+	registry.RegisterUserDataExporter("identity-history", registry.UserDataExportFunc(services.UserService.ExportIdentityHistory))
+	// Resolve the permissions of the requests with an active organization
+	auth.SetTenantPermissionProvider(services.OrganizationService)
 }
 
 // GetServices returns the services.
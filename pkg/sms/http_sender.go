@@ -0,0 +1,70 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPSMSSender posts the messages as JSON to the endpoint of an SMS provider or of a relay in front of it:
+//
+//	{"to": "+905321234567", "from": "Ground", "message": "..."}
+//
+// Any status other than 2xx fails the send.
+type HTTPSMSSender struct {
+	url     string
+	from    string
+	headers map[string]string
+	client  *http.Client
+}
+
+// httpSMSRequest is the body posted by HTTPSMSSender
+type httpSMSRequest struct {
+	To      string `json:"to"`
+	From    string `json:"from,omitempty"`
+	Message string `json:"message"`
+}
+
+// NewHTTPSMSSender creates an HTTPSMSSender posting to url with the headers, e.g. the credentials of the provider
+func NewHTTPSMSSender(url string, from string, headers map[string]string) (*HTTPSMSSender, error) {
+	if url == "" {
+		return nil, errors.New("the URL of the SMS provider is required")
+	}
+	return &HTTPSMSSender{
+		url:     url,
+		from:    from,
+		headers: headers,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (s *HTTPSMSSender) Send(ctx context.Context, to string, message string) error {
+	body, err := json.Marshal(httpSMSRequest{To: to, From: s.from, Message: message})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("SMS provider responded with %d: %s", response.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
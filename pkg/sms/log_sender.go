@@ -0,0 +1,59 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LydiaTrack/ground/pkg/log"
+)
+
+// codePattern matches the codes in the messages, such as the phone verification codes
+var codePattern = regexp.MustCompile(`\d{4,}`)
+
+// LogSMSSender writes the messages to the log or appends them to a file instead of sending them, for development
+// and tests
+type LogSMSSender struct {
+	file      string
+	maskCodes bool
+	mutex     *sync.Mutex
+}
+
+// NewLogSMSSender creates a LogSMSSender appending to file, or logging when file is empty
+func NewLogSMSSender(file string) *LogSMSSender {
+	return &LogSMSSender{file: file, mutex: &sync.Mutex{}}
+}
+
+// NewMaskedLogSMSSender creates a LogSMSSender like NewLogSMSSender that masks the codes in the messages, so the
+// logs of deployments without a real sender cannot be used to verify phone numbers
+func NewMaskedLogSMSSender(file string) *LogSMSSender {
+	return &LogSMSSender{file: file, maskCodes: true, mutex: &sync.Mutex{}}
+}
+
+func (s *LogSMSSender) Send(_ context.Context, to string, message string) error {
+	if s.maskCodes {
+		message = codePattern.ReplaceAllStringFunc(message, func(code string) string {
+			return strings.Repeat("*", len(code))
+		})
+	}
+	if s.file == "" {
+		log.Log("SMS to %s: %s", to, message)
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	file, err := os.OpenFile(s.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(file, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), to, message); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
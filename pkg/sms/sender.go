@@ -0,0 +1,62 @@
+// Package sms sends text messages to phone numbers through a pluggable provider.
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/LydiaTrack/ground/pkg/log"
+)
+
+const (
+	// LogSenderType logs the messages, or appends them to SMS_LOG_FILE, instead of sending them
+	LogSenderType = "LOG"
+	// HTTPSenderType posts the messages to SMS_HTTP_URL
+	HTTPSenderType = "HTTP"
+)
+
+// ErrNoSender is returned in production mode when only the log sender is configured, which does not send the messages
+var ErrNoSender = errors.New("no SMS sender is configured")
+
+// SMSSender sends text messages, the phone numbers are in the E.164 format
+type SMSSender interface {
+	Send(ctx context.Context, to string, message string) error
+}
+
+// SMSSenderFunc adapts a function to an SMSSender, so host applications can plug in a provider SDK
+type SMSSenderFunc func(ctx context.Context, to string, message string) error
+
+// Send calls f(ctx, to, message).
+func (f SMSSenderFunc) Send(ctx context.Context, to string, message string) error {
+	return f(ctx, to, message)
+}
+
+// NewSMSSenderFromEnv creates the sender selected by SMS_SENDER_TYPE, the log sender by default so development
+// setups never send real messages. The log sender masks the codes in test mode and is refused with ErrNoSender in
+// production mode, where the codes would never reach the users.
+func NewSMSSenderFromEnv() (SMSSender, error) {
+	switch senderType := strings.ToUpper(os.Getenv("SMS_SENDER_TYPE")); senderType {
+	case "", LogSenderType:
+		switch envType := os.Getenv("ENV_TYPE"); envType {
+		case "production":
+			return nil, fmt.Errorf("%w: the %s sender does not send the messages in production mode, set SMS_SENDER_TYPE "+
+				"to %s or register a sender", ErrNoSender, LogSenderType, HTTPSenderType)
+		case "test":
+			log.LogWarning(fmt.Sprintf("SMS_SENDER_TYPE is %s in test mode: the text messages are NOT sent and their codes "+
+				"are masked in the log", LogSenderType))
+			return NewMaskedLogSMSSender(os.Getenv("SMS_LOG_FILE")), nil
+		}
+		return NewLogSMSSender(os.Getenv("SMS_LOG_FILE")), nil
+	case HTTPSenderType:
+		headers := map[string]string{}
+		if authorization := os.Getenv("SMS_HTTP_AUTHORIZATION"); authorization != "" {
+			headers["Authorization"] = authorization
+		}
+		return NewHTTPSMSSender(os.Getenv("SMS_HTTP_URL"), os.Getenv("SMS_HTTP_FROM"), headers)
+	default:
+		return nil, fmt.Errorf("unknown SMS sender type %q", senderType)
+	}
+}
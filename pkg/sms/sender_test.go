@@ -0,0 +1,114 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LydiaTrack/ground/pkg/log"
+)
+
+func TestLogSMSSender(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sms.log")
+	sender := NewLogSMSSender(file)
+	if err := sender.Send(context.Background(), "+905321234567", "first"); err != nil {
+		t.Fatalf("failed to send the first message: %v", err)
+	}
+	if err := sender.Send(context.Background(), "+905321234567", "second"); err != nil {
+		t.Fatalf("failed to send the second message: %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read the log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "\t+905321234567\tfirst") || !strings.HasSuffix(lines[1], "\tsecond") {
+		t.Errorf("unexpected log file %q", data)
+	}
+}
+
+func TestMaskedLogSMSSender(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sms.log")
+	sender := NewMaskedLogSMSSender(file)
+	if err := sender.Send(context.Background(), "+905321234567", "Your verification code is 123456. It expires in 10 minutes."); err != nil {
+		t.Fatalf("failed to send the message: %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read the log file: %v", err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(string(data)), "\t+905321234567\tYour verification code is ******. It expires in 10 minutes.") {
+		t.Errorf("expected the code to be masked, got %q", data)
+	}
+}
+
+func TestSMSSenderFromEnv(t *testing.T) {
+	log.InitLogging()
+	t.Setenv("SMS_SENDER_TYPE", "")
+	t.Setenv("SMS_LOG_FILE", "")
+	for envType, masked := range map[string]bool{"": false, "development": false, "test": true} {
+		t.Setenv("ENV_TYPE", envType)
+		sender, err := NewSMSSenderFromEnv()
+		if err != nil {
+			t.Fatalf("failed to create the sender: %v", err)
+		}
+		if logSender, ok := sender.(*LogSMSSender); !ok || logSender.maskCodes != masked {
+			t.Errorf("expected the log sender masking the codes %v in %q mode, got %+v", masked, envType, sender)
+		}
+	}
+
+	t.Setenv("ENV_TYPE", "production")
+	for _, senderType := range []string{"", LogSenderType} {
+		t.Setenv("SMS_SENDER_TYPE", senderType)
+		if _, err := NewSMSSenderFromEnv(); !errors.Is(err, ErrNoSender) {
+			t.Errorf("expected the %q sender to be refused in production mode, got %v", senderType, err)
+		}
+	}
+}
+
+func TestHTTPSMSSender(t *testing.T) {
+	var received httpSMSRequest
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if received.To == "+10000000000" {
+			http.Error(w, "invalid number", http.StatusUnprocessableEntity)
+		}
+	}))
+	defer server.Close()
+
+	sender, err := NewHTTPSMSSender(server.URL, "Ground", map[string]string{"Authorization": "Bearer token"})
+	if err != nil {
+		t.Fatalf("failed to create the sender: %v", err)
+	}
+	if err = sender.Send(context.Background(), "+905321234567", "hello"); err != nil {
+		t.Fatalf("failed to send the message: %v", err)
+	}
+	if received.To != "+905321234567" || received.From != "Ground" || received.Message != "hello" {
+		t.Errorf("unexpected request %+v", received)
+	}
+	if authorization != "Bearer token" {
+		t.Errorf("expected the headers to be sent, got %q", authorization)
+	}
+
+	err = sender.Send(context.Background(), "+10000000000", "hello")
+	if err == nil || !strings.Contains(err.Error(), "422") || !strings.Contains(err.Error(), "invalid number") {
+		t.Errorf("expected the provider error, got %v", err)
+	}
+
+	if _, err = NewHTTPSMSSender("", "", nil); err == nil {
+		t.Errorf("expected the URL to be required")
+	}
+}
@@ -0,0 +1,43 @@
+// Package storage stores binary objects, such as the avatars of the users, outside the documents that reference them.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	// ErrBlobNotFound is returned when no blob is stored with a key
+	ErrBlobNotFound = errors.New("blob not found")
+	// ErrInvalidKey is returned for keys that are not relative slash-separated paths
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Blob is a stored binary object with its content type
+type Blob struct {
+	ContentType string
+	Data        []byte
+}
+
+// BlobStore stores blobs by key. Keys are relative slash-separated paths of letters, digits, dots, dashes and
+// underscores, e.g. avatars/66f1c2/256.png. Putting a blob with an existing key replaces it.
+type BlobStore interface {
+	// Put stores the blob with the key
+	Put(ctx context.Context, key string, blob Blob) error
+	// Get retrieves the blob with the key, ErrBlobNotFound if there is none
+	Get(ctx context.Context, key string) (Blob, error)
+	// Delete deletes the blob with the key, deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*(/[A-Za-z0-9_-][A-Za-z0-9._-]*)*$`)
+
+// ValidateKey checks that the key is a relative path that stays inside the store
+func ValidateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
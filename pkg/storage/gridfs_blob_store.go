@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GridFSBlobStore stores the blobs as the files of a GridFS bucket, the key is the name of the file and the content
+// type is kept in its metadata.
+type GridFSBlobStore struct {
+	bucket *gridfs.Bucket
+}
+
+// gridFSMetadata is the metadata of the files of a GridFSBlobStore
+type gridFSMetadata struct {
+	ContentType string `bson:"contentType"`
+}
+
+// NewGridFSBlobStore creates a GridFSBlobStore storing the blobs in the bucket of the database
+func NewGridFSBlobStore(db *mongo.Database, bucketName string) (*GridFSBlobStore, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(bucketName))
+	if err != nil {
+		return nil, err
+	}
+	return &GridFSBlobStore{bucket: bucket}, nil
+}
+
+// Put uploads the blob as a new revision of the file and deletes the previous revisions
+func (s *GridFSBlobStore) Put(ctx context.Context, key string, blob Blob) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	fileID, err := s.bucket.UploadFromStream(key, bytes.NewReader(blob.Data),
+		options.GridFSUpload().SetMetadata(gridFSMetadata{ContentType: blob.ContentType}))
+	if err != nil {
+		return err
+	}
+	return s.deleteFiles(ctx, bson.M{"filename": key, "_id": bson.M{"$ne": fileID}})
+}
+
+// Get downloads the latest revision of the file
+func (s *GridFSBlobStore) Get(_ context.Context, key string) (Blob, error) {
+	if err := ValidateKey(key); err != nil {
+		return Blob{}, err
+	}
+	stream, err := s.bucket.OpenDownloadStreamByName(key)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return Blob{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return Blob{}, err
+	}
+	defer stream.Close()
+
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return Blob{}, err
+	}
+	var metadata gridFSMetadata
+	if raw := stream.GetFile().Metadata; raw != nil {
+		if err = bson.Unmarshal(raw, &metadata); err != nil {
+			return Blob{}, err
+		}
+	}
+	return Blob{ContentType: metadata.ContentType, Data: data}, nil
+}
+
+// Delete deletes every revision of the file
+func (s *GridFSBlobStore) Delete(ctx context.Context, key string) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	return s.deleteFiles(ctx, bson.M{"filename": key})
+}
+
+// deleteFiles deletes the files matching the filter with their chunks
+func (s *GridFSBlobStore) deleteFiles(ctx context.Context, filter interface{}) error {
+	cursor, err := s.bucket.FindContext(ctx, filter)
+	if err != nil {
+		return err
+	}
+	var files []struct {
+		ID interface{} `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &files); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err = s.bucket.DeleteContext(ctx, file.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// LocalBlobStore stores the blobs as files under a directory of the local filesystem. The content type of a blob is
+// derived from the extension of its key, or sniffed from its content when the extension is unknown.
+type LocalBlobStore struct {
+	dir string
+}
+
+// NewLocalBlobStore creates a LocalBlobStore storing the blobs under dir, the directory is created if needed
+func NewLocalBlobStore(dir string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalBlobStore{dir: dir}, nil
+}
+
+// Put writes the blob to a temporary file and renames it, so readers never see a partial blob
+func (s *LocalBlobStore) Put(_ context.Context, key string, blob Blob) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(filePath), ".blob-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(blob.Data); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), filePath)
+}
+
+func (s *LocalBlobStore) Get(_ context.Context, key string) (Blob, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return Blob{}, err
+	}
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return Blob{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return Blob{}, err
+	}
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return Blob{ContentType: contentType, Data: data}, nil
+}
+
+func (s *LocalBlobStore) Delete(_ context.Context, key string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path returns the path of the file of a key
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
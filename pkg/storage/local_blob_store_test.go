@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create the store: %v", err)
+	}
+
+	if _, err = store.Get(ctx, "avatars/a/64.png"); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("expected a missing blob, got %v", err)
+	}
+
+	if err = store.Put(ctx, "avatars/a/64.png", Blob{ContentType: "image/png", Data: []byte("first")}); err != nil {
+		t.Fatalf("failed to put the blob: %v", err)
+	}
+	if err = store.Put(ctx, "avatars/a/64.png", Blob{ContentType: "image/png", Data: []byte("second")}); err != nil {
+		t.Fatalf("failed to replace the blob: %v", err)
+	}
+	blob, err := store.Get(ctx, "avatars/a/64.png")
+	if err != nil {
+		t.Fatalf("failed to get the blob: %v", err)
+	}
+	if string(blob.Data) != "second" || blob.ContentType != "image/png" {
+		t.Fatalf("unexpected blob %q of type %s", blob.Data, blob.ContentType)
+	}
+
+	if err = store.Delete(ctx, "avatars/a/64.png"); err != nil {
+		t.Fatalf("failed to delete the blob: %v", err)
+	}
+	if err = store.Delete(ctx, "avatars/a/64.png"); err != nil {
+		t.Fatalf("deleting a missing blob failed: %v", err)
+	}
+	if _, err = store.Get(ctx, "avatars/a/64.png"); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatalf("expected the blob to be deleted, got %v", err)
+	}
+}
+
+func TestValidateKey(t *testing.T) {
+	for _, key := range []string{"avatar.png", "avatars/66f1c2/256.png", "a_b-c/d.e"} {
+		if err := ValidateKey(key); err != nil {
+			t.Errorf("expected %q to be valid, got %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "/etc/passwd", "../secret", "avatars/../../secret", "avatars//a", "avatars/a/", ".hidden", `a\b`} {
+		if err := ValidateKey(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected %q to be invalid", key)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrScopeMissing is returned by tenant scoped repositories when the context does not declare a tenant scope
+var ErrScopeMissing = errors.New("tenant scope is missing from the context")
+
+// Scope is the tenant a request operates on. A global scope operates on every tenant.
+type Scope struct {
+	TenantID primitive.ObjectID
+	Global   bool
+}
+
+type scopeKey struct{}
+
+// WithTenant returns a context scoped to the given tenant
+func WithTenant(ctx context.Context, tenantID primitive.ObjectID) context.Context {
+	return context.WithValue(ctx, scopeKey{}, Scope{TenantID: tenantID})
+}
+
+// Global returns a context that explicitly operates on every tenant
+func Global(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, Scope{Global: true})
+}
+
+// FromContext retrieves the tenant scope of the context
+func FromContext(ctx context.Context) (Scope, bool) {
+	scope, ok := ctx.Value(scopeKey{}).(Scope)
+	return scope, ok
+}
@@ -22,10 +22,21 @@ func EvaluateError(err error, c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 	case errors.Is(err, constants.ErrorInternalServerError):
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	case errors.Is(err, constants.ErrorVersionConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "VERSION_CONFLICT"})
 	case errors.Is(err, constants.ErrorConflict):
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, constants.ErrorTooManyRequests):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
 	case errors.Is(err, constants.ErrorOAuthWithPassWord):
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	// Clients tell the account states apart by the code
+	case errors.Is(err, constants.ErrorAccountSuspended):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "ACCOUNT_SUSPENDED"})
+	case errors.Is(err, constants.ErrorAccountBanned):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "ACCOUNT_BANNED"})
+	case errors.Is(err, constants.ErrorAccountDeactivated):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": "ACCOUNT_DEACTIVATED"})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 	}